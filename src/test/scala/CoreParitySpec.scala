@@ -0,0 +1,40 @@
+import munit.FunSuite
+import java.nio.file.{ Files, Path, Paths }
+import scala.jdk.CollectionConverters.*
+import io.circe.Decoder
+import io.circe.parser.decode
+import chess.format.pgn.PgnStr
+import chessfinder.core.{ Finder, PgnReader, SearchFen }
+
+// The Go searcher asserts the same matches in src_go/search/process/board_searcher_test.go.
+class CoreParitySpec extends FunSuite {
+
+  case class CoreMatches(board: String, matches: List[String])
+  case class ArchiveGame(url: String, pgn: String)
+  case class Archive(games: List[ArchiveGame])
+
+  given Decoder[CoreMatches] = Decoder.forProduct2("board", "matches")(CoreMatches.apply)
+  given Decoder[ArchiveGame] = Decoder.forProduct2("url", "pgn")(ArchiveGame.apply)
+  given Decoder[Archive]     = Decoder.forProduct1("games")(Archive.apply)
+
+  val testdata = Paths.get("src_go", "search", "process", "testdata")
+
+  def read(path: Path): String = new String(Files.readAllBytes(path), "UTF-8")
+
+  test("the core should match the games recorded in core_matches.json in every archive") {
+    val coreMatches = decode[List[CoreMatches]](read(testdata.resolve("core_matches.json"))).toTry.get
+
+    val archives = Files.list(testdata).iterator().asScala.toList
+      .filter(path => path.getFileName.toString.matches("2022-.*\\.json"))
+      .sortBy(_.getFileName.toString)
+    val games = archives.flatMap(archive => decode[Archive](read(archive)).toTry.get.games)
+
+    coreMatches.foreach { expected =>
+      val board = SearchFen.read(SearchFen(expected.board)).toOption.get
+      val actualMatches = games.filter { game =>
+        PgnReader.read(PgnStr(game.pgn)).map(replay => Finder.find(replay, board)).getOrElse(false)
+      }.map(_.url)
+      assertEquals(actualMatches, expected.matches, expected.board)
+    }
+  }
+}
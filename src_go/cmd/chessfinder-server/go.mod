@@ -0,0 +1,72 @@
+module github.com/chessfinder/chessfinder-faster-backend/src_go/cmd/chessfinder-server
+
+go 1.21.1
+
+require (
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing v0.0.0-00010101000000-000000000000
+	github.com/aws/aws-lambda-go v1.41.0
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/api v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/db v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/download/check_status v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/download/initiate v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/download/process v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/download/upload v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/search/check_status v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/search/initiate v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/search/process v0.0.0-00010101000000-000000000000
+	github.com/google/uuid v1.3.1
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/stretchr/testify v1.8.4
+	go.uber.org/zap v1.26.0
+)
+
+require (
+	github.com/aws/aws-sdk-go v1.46.1 // indirect
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/batcher v0.0.0-00010101000000-000000000000 // indirect
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess v0.0.0-00010101000000-000000000000 // indirect
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics v0.0.0-00010101000000-000000000000 // indirect
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/pgn v0.0.0-00010101000000-000000000000 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/api => ../../details/api
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/batcher => ../../details/batcher
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess => ../../details/chess
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/db => ../../details/db
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging => ../../details/logging
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics => ../../details/metrics
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/pgn => ../../details/pgn
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue => ../../details/queue
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources => ../../details/sources
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/download/check_status => ../../download/check_status
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/download/initiate => ../../download/initiate
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/download/process => ../../download/process
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/download/upload => ../../download/upload
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/search/check_status => ../../search/check_status
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/search/initiate => ../../search/initiate
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/search/process => ../../search/process
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing => ../../details/tracing
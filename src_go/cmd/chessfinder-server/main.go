@@ -0,0 +1,113 @@
+// chessfinder-server serves the API of the lambdas over HTTP and runs their workers in the same process, without AWS.
+//
+//	chessfinder-server [-addr localhost:8080] [-database-driver sqlite3] [-database file:chessfinder.db?_busy_timeout=5000]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "the address to listen on")
+	databaseDriver := flag.String("database-driver", "sqlite3", "the SQL driver, sqlite3 or postgres")
+	database := flag.String("database", "file:chessfinder.db?_busy_timeout=5000", "the data source of the SQL database")
+	chessDotComUrl := flag.String("chess-dot-com-url", "https://api.chess.com", "the API of chess.com")
+	lichessUrl := flag.String("lichess-url", "https://lichess.org", "the API of lichess")
+	infoExpiresIn := flag.Duration("info-expires-in", 24*time.Hour, "how long the downloads and the searches are kept")
+	visibilityTimeout := flag.Duration("visibility-timeout", 30*time.Second, "how long a failed command waits to be processed again")
+	flag.Parse()
+
+	logger := logging.MustCreateZuluTimeLogger()
+	defer logger.Sync()
+
+	shutdownTracing := tracing.MustSetUp("chessfinder-server")
+	defer shutdownTracing()
+
+	sqlDatabase, err := db.OpenSqlDatabase(*databaseDriver, *database)
+	if err != nil {
+		logger.Error("impossible to open the database", zap.Error(err))
+		os.Exit(1)
+	}
+	defer sqlDatabase.Close()
+
+	err = sqlDatabase.Migrate()
+	if err != nil {
+		logger.Error("impossible to migrate the database", zap.Error(err))
+		os.Exit(1)
+	}
+
+	downloadsTable := downloads.SqlDownloadsTable{Database: sqlDatabase}
+	searchesTable := searches.SqlSearchesTable{Database: sqlDatabase}
+	gamesTable := games.SqlGamesTable{Database: sqlDatabase}
+
+	server := NewServer(
+		Tables{
+			Users:      users.SqlUsersTable{Database: sqlDatabase},
+			Archives:   archives.SqlArchivesTable{Database: sqlDatabase},
+			Downloads:  downloadsTable,
+			Searches:   searchesTable,
+			Games:      gamesTable,
+			LatestGame: gamesTable,
+		},
+		// without CloudWatch the meters publish nothing
+		sources.Platforms(*chessDotComUrl, *lichessUrl, "", nil),
+		*infoExpiresIn,
+		*visibilityTimeout,
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go server.Work(ctx, logger)
+	go expireRecords(ctx, downloadsTable, searchesTable, logger)
+
+	httpServer := &http.Server{Addr: *addr, Handler: server.Routes(logger)}
+	go func() {
+		<-ctx.Done()
+		httpServer.Shutdown(context.Background())
+	}()
+
+	logger.Info("listening", zap.String("addr", *addr))
+	err = httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("the server stopped", zap.Error(err))
+		os.Exit(1)
+	}
+}
+
+// expireRecords plays the part of the time to live of DynamoDB.
+func expireRecords(ctx context.Context, downloadsTable downloads.SqlDownloadsTable, searchesTable searches.SqlSearchesTable, logger *zap.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			err := errors.Join(downloadsTable.ExpireRecords(now), searchesTable.ExpireRecords(now))
+			if err != nil {
+				logger.Error("impossible to expire the records", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	downloadcheck "github.com/chessfinder/chessfinder-faster-backend/src_go/download/check_status"
+	downloadinitiate "github.com/chessfinder/chessfinder-faster-backend/src_go/download/initiate"
+	downloadprocess "github.com/chessfinder/chessfinder-faster-backend/src_go/download/process"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/download/upload"
+	searchcheck "github.com/chessfinder/chessfinder-faster-backend/src_go/search/check_status"
+	searchinitiate "github.com/chessfinder/chessfinder-faster-backend/src_go/search/initiate"
+	searchprocess "github.com/chessfinder/chessfinder-faster-backend/src_go/search/process"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxReceiveCount is how many times a command is received before it is dropped, as the redrive policy of SQS.
+const maxReceiveCount = 3
+
+type Tables struct {
+	Users      users.UsersRepository
+	Archives   archives.ArchivesRepository
+	Downloads  downloads.DownloadsRepository
+	Searches   searches.SearchesRepository
+	Games      games.GamesRepository
+	LatestGame games.LatestGameRepository
+}
+
+// Server runs the lambdas of the backend in one process.
+// The routes call the handlers as API Gateway does and the workers consume the commands as the SQS triggers do.
+type Server struct {
+	downloadStatusChecker downloadcheck.DownloadStatusChecker
+	archiveDownloader     downloadinitiate.ArchiveDownloader
+	pgnUploader           upload.PgnUploader
+	gameDownloader        downloadprocess.GameDownloader
+	searchResultChecker   searchcheck.SearchResultChecker
+	searchRegistrar       searchinitiate.SearchRegistrar
+	boardFinder           searchprocess.BoardFinder
+	downloadGamesQueue    queue.Queue
+	searchBoardQueue      queue.Queue
+}
+
+// NewServer passes the commands over the local queues, visibilityTimeout being how long a failed command waits to be received again.
+func NewServer(
+	tables Tables,
+	gameSources map[users.Platform]sources.GameSource,
+	infoExpiresIn time.Duration,
+	visibilityTimeout time.Duration,
+) *Server {
+	downloadGamesQueue := queue.NewLocalQueue()
+	downloadGamesQueue.VisibilityTimeout = visibilityTimeout
+	downloadGamesQueue.MaxReceiveCount = maxReceiveCount
+	searchBoardQueue := queue.NewLocalQueue()
+	searchBoardQueue.VisibilityTimeout = visibilityTimeout
+	searchBoardQueue.MaxReceiveCount = maxReceiveCount
+
+	return &Server{
+		downloadStatusChecker: downloadcheck.DownloadStatusChecker{
+			DownloadsTable: tables.Downloads,
+		},
+		archiveDownloader: downloadinitiate.ArchiveDownloader{
+			GameSources:           gameSources,
+			UsersTable:            tables.Users,
+			ArchivesTable:         tables.Archives,
+			DownloadsTable:        tables.Downloads,
+			DownloadGamesQueue:    downloadGamesQueue,
+			DownloadInfoExpiresIn: infoExpiresIn,
+		},
+		pgnUploader: upload.PgnUploader{
+			UsersTable:            tables.Users,
+			ArchivesTable:         tables.Archives,
+			DownloadsTable:        tables.Downloads,
+			DownloadGamesQueue:    downloadGamesQueue,
+			DownloadInfoExpiresIn: infoExpiresIn,
+		},
+		gameDownloader: downloadprocess.GameDownloader{
+			GameSources:           gameSources,
+			DownloadsTable:        tables.Downloads,
+			ArchivesTable:         tables.Archives,
+			GamesTable:            tables.Games,
+			LatestGameIndex:       tables.LatestGame,
+			PgnFilter:             downloadprocess.PgnSqueezer{},
+			EncodeMoves:           true, // a new database has no squeezed pgns to stay compatible with
+			DownloadInfoExpiresIn: infoExpiresIn,
+			MaxReceiveCount:       maxReceiveCount,
+		},
+		searchResultChecker: searchcheck.SearchResultChecker{
+			SearchesTable: tables.Searches,
+		},
+		searchRegistrar: searchinitiate.SearchRegistrar{
+			UsersTable:          tables.Users,
+			ArchivesTable:       tables.Archives,
+			DownloadsTable:      tables.Downloads,
+			SearchesTable:       tables.Searches,
+			GamesTable:          tables.Games,
+			SearchBoardQueue:    searchBoardQueue,
+			SearchInfoExpiresIn: infoExpiresIn,
+			Validator:           searchinitiate.PpnBoardValidator{},
+		},
+		boardFinder: searchprocess.BoardFinder{
+			SearchesTable:       tables.Searches,
+			GamesTable:          tables.Games,
+			SearchInfoExpiresIn: infoExpiresIn,
+			Searcher:            searchprocess.PpnBoardSearcher{},
+		},
+		downloadGamesQueue: downloadGamesQueue,
+		searchBoardQueue:   searchBoardQueue,
+	}
+}
+
+// Routes are the routes of API Gateway.
+func (server *Server) Routes(logger *zap.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/api/faster/game", byMethod(map[string]http.Handler{
+		http.MethodGet:  lambdaHandler(server.downloadStatusChecker.Check, logger),
+		http.MethodPost: lambdaHandler(server.archiveDownloader.DownloadArchiveAndDistributeDownloadGameCommands, logger),
+	}))
+	mux.Handle("/api/faster/upload", byMethod(map[string]http.Handler{
+		http.MethodPost: lambdaHandler(server.pgnUploader.UploadPgn, logger),
+	}))
+	mux.Handle("/api/faster/board", byMethod(map[string]http.Handler{
+		http.MethodGet:  lambdaHandler(server.searchResultChecker.Check, logger),
+		http.MethodPost: lambdaHandler(server.searchRegistrar.RegisterSearchRequest, logger),
+	}))
+	return mux
+}
+
+// Work consumes the commands until the context is done.
+func (server *Server) Work(ctx context.Context, logger *zap.Logger) {
+	var workers sync.WaitGroup
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		server.downloadGamesQueue.Consume(ctx, server.gameDownloader.Download, logger)
+	}()
+	go func() {
+		defer workers.Done()
+		server.searchBoardQueue.Consume(ctx, server.boardFinder.Find, logger)
+	}()
+	workers.Wait()
+}
+
+func byMethod(handlers map[string]http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		handler, handlerExists := handlers[request.Method]
+		if !handlerExists {
+			http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+// lambdaHandler adapts the request to the event of API Gateway. A panic of the handler is answered with 500, as API Gateway does when a lambda fails.
+func lambdaHandler(handler func(*events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error), logger *zap.Logger) http.Handler {
+	recovered := api.WithRecover(handler)
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requestId := uuid.NewString()
+		logger := logger.With(zap.String("requestId", requestId), zap.String("method", request.Method), zap.String("path", request.URL.Path))
+
+		defer func() {
+			if failure := recover(); failure != nil {
+				logger.Error("the handler failed", zap.Any("failure", failure))
+				http.Error(writer, `{"message":"Internal Server Error"}`, http.StatusInternalServerError)
+			}
+		}()
+
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			logger.Error("impossible to read the request body", zap.Error(err))
+			http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		event := events.APIGatewayV2HTTPRequest{
+			RawPath:               request.URL.Path,
+			RawQueryString:        request.URL.RawQuery,
+			Headers:               headers(request.Header),
+			QueryStringParameters: joinValues(request.URL.Query()),
+			Body:                  string(body),
+			RequestContext: events.APIGatewayV2HTTPRequestContext{
+				RequestID: requestId,
+				HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+					Method:    request.Method,
+					Path:      request.URL.Path,
+					Protocol:  request.Proto,
+					SourceIP:  request.RemoteAddr,
+					UserAgent: request.UserAgent(),
+				},
+			},
+		}
+
+		responseEvent, err := recovered(&event)
+		if err != nil {
+			panic(err)
+		}
+
+		for name, value := range responseEvent.Headers {
+			writer.Header().Set(name, value)
+		}
+		statusCode := responseEvent.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		writer.WriteHeader(statusCode)
+		_, err = io.WriteString(writer, responseEvent.Body)
+		if err != nil {
+			logger.Error("impossible to write the response body", zap.Error(err))
+		}
+	})
+}
+
+// joinValues joins the repeated values with commas, as API Gateway does in the events of the version 2.0.
+func joinValues(values map[string][]string) map[string]string {
+	joined := make(map[string]string, len(values))
+	for name, value := range values {
+		joined[name] = strings.Join(value, ",")
+	}
+	return joined
+}
+
+// headers are joined as the other values, but their names are in lower case.
+func headers(header http.Header) map[string]string {
+	joined := joinValues(header)
+	for name, value := range joined {
+		delete(joined, name)
+		joined[strings.ToLower(name)] = value
+	}
+	return joined
+}
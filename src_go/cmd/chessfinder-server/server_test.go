@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/sqltest"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+const smotheredMatePgn = `[Event "Live Chess"]
+[Site "Chess.com"]
+[White "tigran-c-137"]
+[Black "rick-c-137"]
+[Result "0-1"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bc4 Nd4 4. Nxe5 Qg5 5. Nxf7 Qxg2 6. Rf1 Qxe4+ 7. Be2 Nf3# 0-1`
+
+type MockedGameSource struct{}
+
+func (source MockedGameSource) Profile(ctx context.Context, logger *zap.Logger, username string) (profile sources.Profile, err error) {
+	profile = sources.Profile{UserId: "https://api.chess.com/pub/player/" + username}
+	return
+}
+
+func (source MockedGameSource) Archives(ctx context.Context, logger *zap.Logger, username string, now time.Time) (archiveUrls []string, err error) {
+	archiveUrls = []string{"https://api.chess.com/pub/player/" + username + "/games/2023/10"}
+	return
+}
+
+func (source MockedGameSource) Games(
+	ctx context.Context,
+	logger *zap.Logger,
+	username string,
+	archive archives.ArchiveRecord,
+	consume func(gameRecords []games.GameRecord) error,
+) (validators sources.ArchiveValidators, err error) {
+	err = consume([]games.GameRecord{
+		{
+			UserId:       archive.UserId,
+			ArchiveId:    archive.ArchiveId,
+			GameId:       "https://www.chess.com/game/live/1",
+			Resource:     "https://www.chess.com/game/live/1",
+			Pgn:          smotheredMatePgn,
+			EndTimestamp: 1697414400,
+		},
+	})
+	return
+}
+
+// FlakyGameSource fails the first download of the games, as chess.com does once in a while.
+type FlakyGameSource struct {
+	MockedGameSource
+	calls *atomic.Int32
+}
+
+func (source FlakyGameSource) Games(
+	ctx context.Context,
+	logger *zap.Logger,
+	username string,
+	archive archives.ArchiveRecord,
+	consume func(gameRecords []games.GameRecord) error,
+) (validators sources.ArchiveValidators, err error) {
+	if source.calls.Add(1) == 1 {
+		err = errors.New("connection reset by peer")
+		return
+	}
+	return source.MockedGameSource.Games(ctx, logger, username, archive, consume)
+}
+
+func startServer(t *testing.T, gameSource sources.GameSource) (httpServer *httptest.Server) {
+	database := sqltest.OpenDatabase(t)
+	gamesTable := games.SqlGamesTable{Database: database}
+
+	server := NewServer(
+		Tables{
+			Users:      users.SqlUsersTable{Database: database},
+			Archives:   archives.SqlArchivesTable{Database: database},
+			Downloads:  downloads.SqlDownloadsTable{Database: database},
+			Searches:   searches.SqlSearchesTable{Database: database},
+			Games:      gamesTable,
+			LatestGame: gamesTable,
+		},
+		map[users.Platform]sources.GameSource{users.ChessDotCom: gameSource},
+		time.Hour,
+		50*time.Millisecond,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go server.Work(ctx, zap.NewNop())
+
+	httpServer = httptest.NewServer(server.Routes(zap.NewNop()))
+	t.Cleanup(httpServer.Close)
+	return
+}
+
+func Test_Server_should_download_the_games_and_find_the_board_in_one_process(t *testing.T) {
+	httpServer := startServer(t, MockedGameSource{})
+
+	downloadResponse := struct {
+		DownloadId string `json:"downloadId"`
+	}{}
+	statusCode := call(t, http.MethodPost, httpServer.URL+"/api/faster/game", `{"username":"tigran-c-137","platform":"chess_dot_com"}`, &downloadResponse)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	assert.Eventually(t, func() bool {
+		downloadResult := struct {
+			Done  int `json:"done"`
+			Total int `json:"total"`
+		}{}
+		statusCode := call(t, http.MethodGet, httpServer.URL+"/api/faster/game?downloadId="+downloadResponse.DownloadId, "", &downloadResult)
+		return statusCode == http.StatusOK && downloadResult.Total == 1 && downloadResult.Done == 1
+	}, 10*time.Second, 50*time.Millisecond)
+
+	searchResponse := struct {
+		SearchId string `json:"searchId"`
+	}{}
+	statusCode = call(
+		t,
+		http.MethodPost,
+		httpServer.URL+"/api/faster/board",
+		`{"username":"tigran-c-137","platform":"CHESS_DOT_COM","board":"????k???/????????/????????/????????/????q???/?????n??/????B???/????K???"}`,
+		&searchResponse,
+	)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	searchResult := struct {
+		Matched []string `json:"matched"`
+		Status  string   `json:"status"`
+	}{}
+	assert.Eventually(t, func() bool {
+		statusCode := call(t, http.MethodGet, httpServer.URL+"/api/faster/board?searchId="+searchResponse.SearchId, "", &searchResult)
+		return statusCode == http.StatusOK && searchResult.Status == string(searches.SearchedAll)
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(t, []string{"https://www.chess.com/game/live/1"}, searchResult.Matched)
+}
+
+func Test_Server_should_download_the_games_again_after_a_failure(t *testing.T) {
+	gameSource := FlakyGameSource{calls: &atomic.Int32{}}
+	httpServer := startServer(t, gameSource)
+
+	downloadResponse := struct {
+		DownloadId string `json:"downloadId"`
+	}{}
+	statusCode := call(t, http.MethodPost, httpServer.URL+"/api/faster/game", `{"username":"tigran-c-137","platform":"chess_dot_com"}`, &downloadResponse)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	downloadResult := struct {
+		Succeed int `json:"succeed"`
+		Failed  int `json:"failed"`
+		Done    int `json:"done"`
+	}{}
+	assert.Eventually(t, func() bool {
+		statusCode := call(t, http.MethodGet, httpServer.URL+"/api/faster/game?downloadId="+downloadResponse.DownloadId, "", &downloadResult)
+		return statusCode == http.StatusOK && downloadResult.Done == 1
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(t, 1, downloadResult.Succeed)
+	assert.Equal(t, 0, downloadResult.Failed)
+	assert.Equal(t, int32(2), gameSource.calls.Load())
+}
+
+func Test_Server_should_reject_the_methods_without_a_lambda(t *testing.T) {
+	server := NewServer(Tables{}, nil, time.Hour, time.Second)
+	httpServer := httptest.NewServer(server.Routes(zap.NewNop()))
+	defer httpServer.Close()
+
+	statusCode := call(t, http.MethodDelete, httpServer.URL+"/api/faster/game", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, statusCode)
+
+	statusCode = call(t, http.MethodGet, httpServer.URL+"/api/faster/upload", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, statusCode)
+}
+
+func call(t *testing.T, method string, url string, body string, response any) (statusCode int) {
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.NoError(t, err)
+
+	httpResponse, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer httpResponse.Body.Close()
+
+	if response != nil && httpResponse.StatusCode == http.StatusOK {
+		assert.NoError(t, json.NewDecoder(httpResponse.Body).Decode(response))
+	}
+	return httpResponse.StatusCode
+}
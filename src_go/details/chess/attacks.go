@@ -0,0 +1,84 @@
+package chess
+
+var knightAttacks [64]Bitboard
+var kingAttacks [64]Bitboard
+var pawnAttacks [2][64]Bitboard
+
+var bishopDirections = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+var rookDirections = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+func init() {
+	knightJumps := [][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	kingSteps := [][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	for square := Square(0); square < 64; square++ {
+		knightAttacks[square] = steps(square, knightJumps)
+		kingAttacks[square] = steps(square, kingSteps)
+		pawnAttacks[White][square] = steps(square, [][2]int{{-1, 1}, {1, 1}})
+		pawnAttacks[Black][square] = steps(square, [][2]int{{-1, -1}, {1, -1}})
+	}
+}
+
+func steps(square Square, deltas [][2]int) (attacks Bitboard) {
+	for _, delta := range deltas {
+		file := square.File() + delta[0]
+		rank := square.Rank() + delta[1]
+		if file >= 0 && file < 8 && rank >= 0 && rank < 8 {
+			attacks |= NewSquare(file, rank).Bitboard()
+		}
+	}
+	return
+}
+
+func slidingAttacks(square Square, occupied Bitboard, directions [][2]int) (attacks Bitboard) {
+	for _, direction := range directions {
+		file := square.File() + direction[0]
+		rank := square.Rank() + direction[1]
+		for file >= 0 && file < 8 && rank >= 0 && rank < 8 {
+			target := NewSquare(file, rank).Bitboard()
+			attacks |= target
+			if occupied&target != 0 {
+				break
+			}
+			file += direction[0]
+			rank += direction[1]
+		}
+	}
+	return
+}
+
+func bishopAttacks(square Square, occupied Bitboard) Bitboard {
+	return slidingAttacks(square, occupied, bishopDirections)
+}
+
+func rookAttacks(square Square, occupied Bitboard) Bitboard {
+	return slidingAttacks(square, occupied, rookDirections)
+}
+
+// Attacks returns the squares a piece of the given role attacks from the square.
+// For pawns only the capturing squares are returned.
+func Attacks(piece Piece, square Square, occupied Bitboard) Bitboard {
+	switch piece.Role {
+	case Pawn:
+		return pawnAttacks[piece.Color][square]
+	case Knight:
+		return knightAttacks[square]
+	case Bishop:
+		return bishopAttacks(square, occupied)
+	case Rook:
+		return rookAttacks(square, occupied)
+	case Queen:
+		return bishopAttacks(square, occupied) | rookAttacks(square, occupied)
+	default:
+		return kingAttacks[square]
+	}
+}
+
+func (board Board) IsAttacked(square Square, by Color) bool {
+	occupied := board.Occupied()
+	attackers := board.Colors[by]
+	return knightAttacks[square]&attackers&board.Roles[Knight] != 0 ||
+		kingAttacks[square]&attackers&board.Roles[King] != 0 ||
+		pawnAttacks[by.Opposite()][square]&attackers&board.Roles[Pawn] != 0 ||
+		bishopAttacks(square, occupied)&attackers&(board.Roles[Bishop]|board.Roles[Queen]) != 0 ||
+		rookAttacks(square, occupied)&attackers&(board.Roles[Rook]|board.Roles[Queen]) != 0
+}
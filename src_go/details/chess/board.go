@@ -0,0 +1,160 @@
+package chess
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+type Color int
+
+const (
+	White Color = iota
+	Black
+)
+
+func (color Color) Opposite() Color {
+	return color ^ 1
+}
+
+func (color Color) String() string {
+	if color == White {
+		return "white"
+	}
+	return "black"
+}
+
+type Role int
+
+const (
+	Pawn Role = iota
+	Knight
+	Bishop
+	Rook
+	Queen
+	King
+)
+
+var roleSymbols = [...]byte{'p', 'n', 'b', 'r', 'q', 'k'}
+
+type Piece struct {
+	Color Color
+	Role  Role
+}
+
+func PieceFromSymbol(symbol byte) (piece Piece, ok bool) {
+	color := Black
+	lower := symbol
+	if symbol >= 'A' && symbol <= 'Z' {
+		color = White
+		lower = symbol - 'A' + 'a'
+	}
+	for role, roleSymbol := range roleSymbols {
+		if roleSymbol == lower {
+			return Piece{Color: color, Role: Role(role)}, true
+		}
+	}
+	return
+}
+
+func (piece Piece) Symbol() byte {
+	symbol := roleSymbols[piece.Role]
+	if piece.Color == White {
+		symbol = symbol - 'a' + 'A'
+	}
+	return symbol
+}
+
+// Square indexes the board from a1 = 0 to h8 = 63.
+type Square int
+
+func NewSquare(file int, rank int) Square {
+	return Square(rank*8 + file)
+}
+
+func SquareFromString(name string) (square Square, ok bool) {
+	if len(name) != 2 || name[0] < 'a' || name[0] > 'h' || name[1] < '1' || name[1] > '8' {
+		return
+	}
+	return NewSquare(int(name[0]-'a'), int(name[1]-'1')), true
+}
+
+func (square Square) File() int { return int(square) % 8 }
+
+func (square Square) Rank() int { return int(square) / 8 }
+
+func (square Square) Bitboard() Bitboard { return Bitboard(1) << uint(square) }
+
+func (square Square) String() string {
+	return fmt.Sprintf("%c%d", 'a'+square.File(), square.Rank()+1)
+}
+
+type Bitboard uint64
+
+func (bitboard Bitboard) Has(square Square) bool {
+	return bitboard&square.Bitboard() != 0
+}
+
+func (bitboard Bitboard) IsSubsetOf(wider Bitboard) bool {
+	return bitboard&^wider == 0
+}
+
+func (bitboard Bitboard) Count() int {
+	return bits.OnesCount64(uint64(bitboard))
+}
+
+func (bitboard Bitboard) Squares() []Square {
+	squares := make([]Square, 0, bitboard.Count())
+	for bitboard != 0 {
+		squares = append(squares, Square(bits.TrailingZeros64(uint64(bitboard))))
+		bitboard &= bitboard - 1
+	}
+	return squares
+}
+
+// Board holds one bitboard per role and per color, the same layout the scala core uses.
+type Board struct {
+	Roles  [6]Bitboard
+	Colors [2]Bitboard
+}
+
+func (board Board) Occupied() Bitboard {
+	return board.Colors[White] | board.Colors[Black]
+}
+
+func (board Board) Pieces(color Color, role Role) Bitboard {
+	return board.Colors[color] & board.Roles[role]
+}
+
+func (board Board) PieceAt(square Square) (piece Piece, ok bool) {
+	mask := square.Bitboard()
+	if board.Occupied()&mask == 0 {
+		return
+	}
+	piece.Color = White
+	if board.Colors[Black]&mask != 0 {
+		piece.Color = Black
+	}
+	for role, roleBitboard := range board.Roles {
+		if roleBitboard&mask != 0 {
+			piece.Role = Role(role)
+			return piece, true
+		}
+	}
+	return
+}
+
+func (board *Board) Put(square Square, piece Piece) {
+	board.Remove(square)
+	mask := square.Bitboard()
+	board.Roles[piece.Role] |= mask
+	board.Colors[piece.Color] |= mask
+}
+
+func (board *Board) Remove(square Square) {
+	mask := ^square.Bitboard()
+	for role := range board.Roles {
+		board.Roles[role] &= mask
+	}
+	board.Colors[White] &= mask
+	board.Colors[Black] &= mask
+}
@@ -0,0 +1,101 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraints restrict a search to positions with the given side to move, castling rights and en passant square.
+// A nil field is not constrained. Castling lists the rights that must still be available, "-" requires none of them.
+// EnPassant requires the en passant square of the FEN, NoSquare requires that there is none.
+type Constraints struct {
+	Turn      *Color
+	Castling  *CastlingRights
+	EnPassant *Square
+}
+
+type ConstraintsError struct {
+	Reason string
+}
+
+func (err ConstraintsError) Error() string {
+	return "invalid constraints: " + err.Reason
+}
+
+// ParseConstraints reads the constraints in FEN notation, an empty string leaves the field unconstrained.
+// The side to move is either "w", "b", "white" or "black".
+func ParseConstraints(sideToMove string, castling string, enPassant string) (constraints Constraints, err error) {
+	switch strings.ToLower(sideToMove) {
+	case "":
+	case "w", "white":
+		turn := White
+		constraints.Turn = &turn
+	case "b", "black":
+		turn := Black
+		constraints.Turn = &turn
+	default:
+		err = ConstraintsError{Reason: fmt.Sprintf("unknown side to move %q", sideToMove)}
+		return
+	}
+
+	if castling != "" {
+		rights, castlingErr := ParseCastlingRights(castling)
+		if castlingErr != nil {
+			err = ConstraintsError{Reason: fmt.Sprintf("unknown castling rights %q", castling)}
+			return
+		}
+		constraints.Castling = &rights
+	}
+
+	switch enPassant {
+	case "":
+	case "-":
+		square := NoSquare
+		constraints.EnPassant = &square
+	default:
+		square, ok := SquareFromString(enPassant)
+		if !ok || (square.Rank() != 2 && square.Rank() != 5) {
+			err = ConstraintsError{Reason: fmt.Sprintf("unknown en passant square %q", enPassant)}
+			return
+		}
+		constraints.EnPassant = &square
+	}
+	return
+}
+
+func (constraints Constraints) IsEmpty() bool {
+	return constraints.Turn == nil && constraints.Castling == nil && constraints.EnPassant == nil
+}
+
+// Fields returns the canonical form of every constraint, the same that ParseConstraints reads.
+func (constraints Constraints) Fields() (sideToMove string, castling string, enPassant string) {
+	if constraints.Turn != nil {
+		sideToMove = constraints.Turn.String()
+	}
+	if constraints.Castling != nil {
+		castling = constraints.Castling.String()
+	}
+	if constraints.EnPassant != nil {
+		enPassant = "-"
+		if *constraints.EnPassant != NoSquare {
+			enPassant = constraints.EnPassant.String()
+		}
+	}
+	return
+}
+
+func (constraints Constraints) SatisfiedBy(position Position) bool {
+	if constraints.Turn != nil && *constraints.Turn != position.Turn {
+		return false
+	}
+	if constraints.Castling != nil {
+		required := *constraints.Castling
+		if required == 0 && position.Castling != 0 || position.Castling&required != required {
+			return false
+		}
+	}
+	if constraints.EnPassant != nil && *constraints.EnPassant != position.EnPassant {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseConstraints_should_leave_empty_fields_unconstrained(t *testing.T) {
+	constraints, err := ParseConstraints("", "", "")
+	assert.NoError(t, err)
+	assert.True(t, constraints.IsEmpty())
+	assert.True(t, constraints.SatisfiedBy(StandardPosition()))
+}
+
+func Test_ParseConstraints_should_read_and_print_the_canonical_form(t *testing.T) {
+	constraints, err := ParseConstraints("b", "qK", "-")
+	assert.NoError(t, err)
+
+	sideToMove, castling, enPassant := constraints.Fields()
+	assert.Equal(t, "black", sideToMove)
+	assert.Equal(t, "Kq", castling)
+	assert.Equal(t, "-", enPassant)
+}
+
+func Test_ParseConstraints_should_reject_unknown_values(t *testing.T) {
+	_, err := ParseConstraints("red", "", "")
+	assert.Equal(t, ConstraintsError{Reason: `unknown side to move "red"`}, err)
+
+	_, err = ParseConstraints("", "KX", "")
+	assert.IsType(t, ConstraintsError{}, err)
+
+	_, err = ParseConstraints("", "", "e4")
+	assert.IsType(t, ConstraintsError{}, err)
+}
+
+func Test_Constraints_should_require_the_listed_castling_rights_only(t *testing.T) {
+	position, err := ParseFen("r3k2r/8/8/8/8/8/8/R3K2R b KQk - 0 1")
+	assert.NoError(t, err)
+
+	whiteCanCastle, _ := ParseConstraints("black", "KQ", "")
+	assert.True(t, whiteCanCastle.SatisfiedBy(position))
+
+	blackCanCastleLong, _ := ParseConstraints("", "q", "")
+	assert.False(t, blackCanCastleLong.SatisfiedBy(position))
+
+	nobodyCanCastle, _ := ParseConstraints("", "-", "")
+	assert.False(t, nobodyCanCastle.SatisfiedBy(position))
+
+	whiteToMove, _ := ParseConstraints("white", "", "")
+	assert.False(t, whiteToMove.SatisfiedBy(position))
+}
+
+func Test_Find_should_skip_positions_that_do_not_satisfy_the_constraints(t *testing.T) {
+	pgn := "1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 1-0"
+	board, _ := ParsePpn("????????/????????/????????/????????/????P???/????????/????????/????????")
+
+	occurrence, found, err := Find(pgn, board, Constraints{})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1, occurrence.Ply)
+
+	enPassantOnE6, _ := ParseConstraints("", "", "e6")
+	occurrence, found, err = Find(pgn, board, enPassantOnE6)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 2, occurrence.Ply)
+
+	whiteToMoveWithoutEnPassant, _ := ParseConstraints("w", "KQkq", "-")
+	occurrence, found, err = Find(pgn, board, whiteToMoveWithoutEnPassant)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 4, occurrence.Ply)
+
+	nobodyCanCastle, _ := ParseConstraints("", "-", "")
+	_, found, err = Find(pgn, board, nobodyCanCastle)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,208 @@
+package chess
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/pgn"
+)
+
+// A move is encoded in two bytes, big endian: the origin in the bits 0-5, the destination in the bits 6-11
+// and the promotion role in the bits 12-14. The encoded moves of a game are the concatenation of its plies.
+const encodedMoveSize = 2
+
+var ErrInvalidEncodedMoves = errors.New("encoded moves are not a whole number of moves")
+
+func EncodeMoves(moves []Move) (encoded []byte) {
+	encoded = make([]byte, 0, len(moves)*encodedMoveSize)
+	for _, move := range moves {
+		encoded = binary.BigEndian.AppendUint16(encoded, uint16(move.From)|uint16(move.To)<<6|uint16(move.Promotion)<<12)
+	}
+	return
+}
+
+func DecodeMoves(encoded []byte) (moves []Move, err error) {
+	if len(encoded)%encodedMoveSize != 0 {
+		err = ErrInvalidEncodedMoves
+		return
+	}
+	moves = make([]Move, 0, len(encoded)/encodedMoveSize)
+	for i := 0; i < len(encoded); i += encodedMoveSize {
+		value := binary.BigEndian.Uint16(encoded[i:])
+		moves = append(moves, Move{
+			From:      Square(value & 0x3f),
+			To:        Square(value >> 6 & 0x3f),
+			Promotion: Role(value >> 12 & 0x7),
+		})
+	}
+	return
+}
+
+var ErrNonStandardSetup = errors.New("only the games played from the standard position are encoded")
+
+// EncodePgn encodes the mainline of the PGN. The encoded moves carry no setup, so the game must start from the standard position.
+func EncodePgn(pgnString string) (encoded []byte, err error) {
+	game, err := pgn.ParseGame(pgnString)
+	if err != nil {
+		return
+	}
+	if fen := game.Tag("FEN"); fen != "" && fen != StandardFen {
+		err = ErrNonStandardSetup
+		return
+	}
+	return EncodeSan(StandardPosition(), mainlineSans(game))
+}
+
+// EncodeSan encodes the SAN moves played from the setup.
+func EncodeSan(setup Position, sans []string) (encoded []byte, err error) {
+	position := setup
+	moves := make([]Move, len(sans))
+	for ply, san := range sans {
+		moves[ply], err = position.ParseSan(san)
+		if err != nil {
+			err = fmt.Errorf("ply %d: %w", ply+1, err)
+			return
+		}
+		position = position.Play(moves[ply])
+	}
+	encoded = EncodeMoves(moves)
+	return
+}
+
+// DecodeSan decodes the moves played from the setup into SAN.
+func DecodeSan(setup Position, encoded []byte) (sans []string, err error) {
+	positions, err := ReplayMoves(setup, encoded)
+	if err != nil {
+		return
+	}
+	moves, _ := DecodeMoves(encoded)
+	sans = make([]string, len(moves))
+	for ply, move := range moves {
+		sans[ply] = positions[ply].San(move)
+	}
+	return
+}
+
+// DecodePgn renders the moves played from the standard position as the mainline of a squeezed PGN, without the result.
+func DecodePgn(encoded []byte) (pgn string, err error) {
+	setup := StandardPosition()
+	sans, err := DecodeSan(setup, encoded)
+	if err != nil {
+		return
+	}
+	tokens := make([]string, 0, len(sans)*3/2)
+	for ply, san := range sans {
+		if ply%2 == 0 {
+			tokens = append(tokens, strconv.Itoa(setup.FullmoveNumber+ply/2)+".")
+		}
+		tokens = append(tokens, san)
+	}
+	pgn = strings.Join(tokens, " ")
+	return
+}
+
+// ReplayMoves is Replay of encoded moves. Every move must be a legal move of a piece of the side to move.
+func ReplayMoves(setup Position, encoded []byte) (positions []Position, err error) {
+	moves, err := DecodeMoves(encoded)
+	if err != nil {
+		return
+	}
+	position := setup
+	positions = make([]Position, 0, len(moves)+1)
+	positions = append(positions, position)
+	for ply, move := range moves {
+		piece, hasPiece := position.Board.PieceAt(move.From)
+		if !hasPiece || piece.Color != position.Turn || !position.IsLegal(move) {
+			err = fmt.Errorf("ply %d: move %s%s is illegal in %s", ply+1, move.From, move.To, position.Fen())
+			return
+		}
+		position = position.Play(move)
+		positions = append(positions, position)
+	}
+	return
+}
+
+// San writes the legal move in Standard Algebraic Notation, with the check and mate suffixes.
+func (position Position) San(move Move) (san string) {
+	piece, _ := position.Board.PieceAt(move.From)
+	_, isCapture := position.Board.PieceAt(move.To)
+	builder := strings.Builder{}
+
+	switch {
+	case piece.Role == King && (move.To.File()-move.From.File() == 2 || move.From.File()-move.To.File() == 2):
+		if move.To.File() > move.From.File() {
+			builder.WriteString("O-O")
+		} else {
+			builder.WriteString("O-O-O")
+		}
+	case piece.Role == Pawn:
+		if move.From.File() != move.To.File() {
+			builder.WriteByte(byte('a' + move.From.File()))
+			builder.WriteByte('x')
+		}
+		builder.WriteString(move.To.String())
+		if move.Promotion != Pawn {
+			builder.WriteByte('=')
+			builder.WriteByte(Piece{Color: White, Role: move.Promotion}.Symbol())
+		}
+	default:
+		builder.WriteByte(Piece{Color: White, Role: piece.Role}.Symbol())
+		sameFile, sameRank, isAmbiguous := false, false, false
+		for _, from := range position.origins(piece, move.To, false).Squares() {
+			if from == move.From || !position.IsLegal(Move{From: from, To: move.To, Promotion: Pawn}) {
+				continue
+			}
+			isAmbiguous = true
+			sameFile = sameFile || from.File() == move.From.File()
+			sameRank = sameRank || from.Rank() == move.From.Rank()
+		}
+		switch {
+		case isAmbiguous && !sameFile:
+			builder.WriteByte(byte('a' + move.From.File()))
+		case isAmbiguous && !sameRank:
+			builder.WriteByte(byte('1' + move.From.Rank()))
+		case isAmbiguous:
+			builder.WriteString(move.From.String())
+		}
+		if isCapture {
+			builder.WriteByte('x')
+		}
+		builder.WriteString(move.To.String())
+	}
+
+	next := position.Play(move)
+	if next.IsCheck() {
+		if next.hasLegalMove() {
+			builder.WriteByte('+')
+		} else {
+			builder.WriteByte('#')
+		}
+	}
+	return builder.String()
+}
+
+// hasLegalMove tells whether the side in check has any legal move, castling never being one of them.
+func (position Position) hasLegalMove() bool {
+	for to := Square(0); to < 64; to++ {
+		for role := Pawn; role <= King; role++ {
+			piece := Piece{Color: position.Turn, Role: role}
+			origins := position.origins(piece, to, false)
+			if role == Pawn {
+				origins |= position.origins(piece, to, true)
+			}
+			for _, from := range origins.Squares() {
+				promotion := Pawn
+				if role == Pawn && (to.Rank() == 0 || to.Rank() == 7) {
+					promotion = Queen
+				}
+				if position.IsLegal(Move{From: from, To: to, Promotion: promotion}) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
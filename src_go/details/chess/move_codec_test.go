@@ -0,0 +1,81 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EncodeMoves_should_take_two_bytes_per_ply_and_decode_back(t *testing.T) {
+	moves := []Move{
+		{From: NewSquare(4, 1), To: NewSquare(4, 3), Promotion: Pawn},
+		{From: NewSquare(6, 6), To: NewSquare(7, 7), Promotion: Knight},
+		{From: NewSquare(7, 0), To: NewSquare(0, 7), Promotion: Queen},
+	}
+
+	encoded := EncodeMoves(moves)
+	assert.Len(t, encoded, 6)
+
+	actualMoves, err := DecodeMoves(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, moves, actualMoves)
+
+	_, err = DecodeMoves(encoded[:5])
+	assert.ErrorIs(t, err, ErrInvalidEncodedMoves)
+}
+
+func Test_DecodePgn_should_restore_the_squeezed_mainline(t *testing.T) {
+	pgns := []string{
+		"1. e4 d5 2. e5 f5 3. exf6 Nc6 4. fxg7 Bd7 5. gxh8=Q e6 6. Nf3 Qe7 7. Bc4 O-O-O 8. O-O e5 9. Qxg8 e4 10. Qxf8 exf3 11. Qxe7 fxg2 12. Qxd7+ Kxd7 13. d3 gxf1=N",
+		"1. Nf3 d5 2. g3 Bg4 3. b3 Nd7 4. Bb2 e6 5. Bg2 Ngf6 6. O-O c6 7. d3 Bd6 8. Nbd2 O-O 9. h3 Bh5 10. e3 h6",
+		"1. e4 e5 2. Nf3 Nc6 3. Nc3 Nf6 4. Nd5 Nd4 5. Ne3 Ne6 6. Ng4 Ng5 7. Nfxe5 Nfxe4",
+		"1. f3 e5 2. g4 Qh4#",
+	}
+
+	for _, pgn := range pgns {
+		encoded, err := EncodePgn(pgn + " 1-0")
+		assert.NoError(t, err)
+
+		actualPgn, err := DecodePgn(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, pgn, actualPgn)
+	}
+}
+
+func Test_San_should_disambiguate_by_rank_when_the_files_are_the_same(t *testing.T) {
+	position, err := ParseFen("4k3/8/8/R7/8/8/8/R3K3 w - - 0 1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "R1a3", position.San(Move{From: NewSquare(0, 0), To: NewSquare(0, 2), Promotion: Pawn}))
+	assert.Equal(t, "Ra8+", position.San(Move{From: NewSquare(0, 4), To: NewSquare(0, 7), Promotion: Pawn}))
+}
+
+func Test_ReplayMoves_should_fail_on_a_move_of_the_wrong_side(t *testing.T) {
+	encoded := EncodeMoves([]Move{{From: NewSquare(4, 6), To: NewSquare(4, 4), Promotion: Pawn}})
+
+	_, err := ReplayMoves(StandardPosition(), encoded)
+	assert.EqualError(t, err, "ply 1: move e7e5 is illegal in "+StandardFen)
+}
+
+func Test_FindSequenceInMoves_should_find_what_FindSequence_finds(t *testing.T) {
+	pgn := "1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 4. Ba4 Nf6 5. O-O Be7 1-0"
+	knightOnF3, _ := ParsePpn("????????/????????/????????/????????/????????/?????N??/????????/????????")
+	castled, _ := ParsePpn("????????/????????/????????/????????/????????/????????/????????/?????RK?")
+	encoded, err := EncodePgn(pgn)
+	assert.NoError(t, err)
+
+	expectedOccurrences, _, err := FindSequence(pgn, []ProbabilisticBoard{knightOnF3, castled}, 6, Constraints{})
+	assert.NoError(t, err)
+
+	actualOccurrences, found, err := FindSequenceInMoves(encoded, []ProbabilisticBoard{knightOnF3, castled}, 6, Constraints{})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, expectedOccurrences, actualOccurrences)
+}
+
+func Test_EncodePgn_should_refuse_a_game_from_a_non_standard_setup(t *testing.T) {
+	_, err := EncodePgn(`[FEN "4k3/8/8/8/8/8/8/R3K2R w KQ - 0 1"]
+
+1. O-O-O Kf7 *`)
+	assert.ErrorIs(t, err, ErrNonStandardSetup)
+}
@@ -0,0 +1,295 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type CastlingRights uint8
+
+const (
+	WhiteKingside CastlingRights = 1 << iota
+	WhiteQueenside
+	BlackKingside
+	BlackQueenside
+)
+
+const NoSquare Square = -1
+
+const StandardFen = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+type Position struct {
+	Board          Board
+	Turn           Color
+	Castling       CastlingRights
+	EnPassant      Square
+	HalfmoveClock  int
+	FullmoveNumber int
+}
+
+// Move is a move in coordinates. Castling is a king move of two files,
+// Promotion is Pawn when the move does not promote.
+type Move struct {
+	From      Square
+	To        Square
+	Promotion Role
+}
+
+var ErrInvalidFen = errors.New("invalid FEN")
+
+func StandardPosition() Position {
+	position, err := ParseFen(StandardFen)
+	if err != nil {
+		panic(err)
+	}
+	return position
+}
+
+// ParseFen reads a FEN. The clocks are optional and default to 0 and 1.
+func ParseFen(fen string) (position Position, err error) {
+	fields := strings.Fields(fen)
+	if len(fields) < 4 || len(fields) > 6 {
+		err = fmt.Errorf("%w: expected from 4 to 6 fields, got %d", ErrInvalidFen, len(fields))
+		return
+	}
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		err = fmt.Errorf("%w: expected 8 ranks, got %d", ErrInvalidFen, len(ranks))
+		return
+	}
+	for i, rankSymbols := range ranks {
+		rank := 7 - i
+		file := 0
+		for _, symbol := range []byte(rankSymbols) {
+			if symbol >= '1' && symbol <= '8' {
+				file += int(symbol - '0')
+				continue
+			}
+			piece, isPiece := PieceFromSymbol(symbol)
+			if !isPiece || file > 7 {
+				err = fmt.Errorf("%w: unexpected %q on rank %d", ErrInvalidFen, symbol, rank+1)
+				return
+			}
+			position.Board.Put(NewSquare(file, rank), piece)
+			file++
+		}
+		if file != 8 {
+			err = fmt.Errorf("%w: rank %d has %d squares", ErrInvalidFen, rank+1, file)
+			return
+		}
+	}
+
+	switch fields[1] {
+	case "w":
+		position.Turn = White
+	case "b":
+		position.Turn = Black
+	default:
+		err = fmt.Errorf("%w: unknown side to move %q", ErrInvalidFen, fields[1])
+		return
+	}
+
+	position.Castling, err = ParseCastlingRights(fields[2])
+	if err != nil {
+		return
+	}
+
+	position.EnPassant = NoSquare
+	if fields[3] != "-" {
+		square, ok := SquareFromString(fields[3])
+		if !ok {
+			err = fmt.Errorf("%w: unknown en passant square %q", ErrInvalidFen, fields[3])
+			return
+		}
+		position.EnPassant = square
+	}
+
+	position.FullmoveNumber = 1
+	if len(fields) > 4 {
+		position.HalfmoveClock, err = strconv.Atoi(fields[4])
+		if err != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidFen, err)
+			return
+		}
+	}
+	if len(fields) > 5 {
+		position.FullmoveNumber, err = strconv.Atoi(fields[5])
+		if err != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidFen, err)
+			return
+		}
+	}
+	return
+}
+
+func ParseCastlingRights(symbols string) (rights CastlingRights, err error) {
+	if symbols == "-" {
+		return
+	}
+	for _, symbol := range []byte(symbols) {
+		switch symbol {
+		case 'K':
+			rights |= WhiteKingside
+		case 'Q':
+			rights |= WhiteQueenside
+		case 'k':
+			rights |= BlackKingside
+		case 'q':
+			rights |= BlackQueenside
+		default:
+			err = fmt.Errorf("%w: unknown castling right %q", ErrInvalidFen, symbol)
+			return
+		}
+	}
+	return
+}
+
+func (rights CastlingRights) String() string {
+	symbols := ""
+	for i, symbol := range "KQkq" {
+		if rights&(1<<i) != 0 {
+			symbols += string(symbol)
+		}
+	}
+	if symbols == "" {
+		return "-"
+	}
+	return symbols
+}
+
+func (position Position) Placement() string {
+	var placement strings.Builder
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			piece, ok := position.Board.PieceAt(NewSquare(file, rank))
+			if !ok {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				placement.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			placement.WriteByte(piece.Symbol())
+		}
+		if empty > 0 {
+			placement.WriteByte(byte('0' + empty))
+		}
+		if rank > 0 {
+			placement.WriteByte('/')
+		}
+	}
+	return placement.String()
+}
+
+func (position Position) Fen() string {
+	turn := "w"
+	if position.Turn == Black {
+		turn = "b"
+	}
+	enPassant := "-"
+	if position.EnPassant != NoSquare {
+		enPassant = position.EnPassant.String()
+	}
+	return fmt.Sprintf(
+		"%s %s %s %s %d %d",
+		position.Placement(),
+		turn,
+		position.Castling,
+		enPassant,
+		position.HalfmoveClock,
+		position.FullmoveNumber,
+	)
+}
+
+func (position Position) king(color Color) Square {
+	kings := position.Board.Pieces(color, King).Squares()
+	if len(kings) == 0 {
+		return NoSquare
+	}
+	return kings[0]
+}
+
+func (position Position) IsCheck() bool {
+	king := position.king(position.Turn)
+	return king != NoSquare && position.Board.IsAttacked(king, position.Turn.Opposite())
+}
+
+// Play applies the move without checking its legality.
+func (position Position) Play(move Move) Position {
+	next := position
+	piece, _ := position.Board.PieceAt(move.From)
+	_, isCapture := position.Board.PieceAt(move.To)
+
+	next.EnPassant = NoSquare
+	next.HalfmoveClock++
+
+	switch piece.Role {
+	case Pawn:
+		next.HalfmoveClock = 0
+		if move.To == position.EnPassant && !isCapture {
+			next.Board.Remove(NewSquare(move.To.File(), move.From.Rank()))
+		}
+		if distance := move.To.Rank() - move.From.Rank(); distance == 2 || distance == -2 {
+			next.EnPassant = NewSquare(move.From.File(), (move.From.Rank()+move.To.Rank())/2)
+		}
+		if move.Promotion != Pawn {
+			piece.Role = move.Promotion
+		}
+	case King:
+		if distance := move.To.File() - move.From.File(); distance == 2 || distance == -2 {
+			rookFrom, rookTo := NewSquare(7, move.From.Rank()), NewSquare(5, move.From.Rank())
+			if distance < 0 {
+				rookFrom, rookTo = NewSquare(0, move.From.Rank()), NewSquare(3, move.From.Rank())
+			}
+			next.Board.Remove(rookFrom)
+			next.Board.Put(rookTo, Piece{Color: piece.Color, Role: Rook})
+		}
+		if piece.Color == White {
+			next.Castling &^= WhiteKingside | WhiteQueenside
+		} else {
+			next.Castling &^= BlackKingside | BlackQueenside
+		}
+	}
+
+	if isCapture {
+		next.HalfmoveClock = 0
+	}
+
+	next.Castling &^= castlingRightsOf(move.From) | castlingRightsOf(move.To)
+
+	next.Board.Remove(move.From)
+	next.Board.Put(move.To, piece)
+
+	if position.Turn == Black {
+		next.FullmoveNumber++
+	}
+	next.Turn = position.Turn.Opposite()
+	return next
+}
+
+func castlingRightsOf(square Square) CastlingRights {
+	switch square {
+	case 7:
+		return WhiteKingside
+	case 0:
+		return WhiteQueenside
+	case 63:
+		return BlackKingside
+	case 56:
+		return BlackQueenside
+	}
+	return 0
+}
+
+// IsLegal tells whether the move of the side to move leaves its own king safe.
+// The move itself is expected to be pseudo legal.
+func (position Position) IsLegal(move Move) bool {
+	next := position.Play(move)
+	king := next.king(position.Turn)
+	return king == NoSquare || !next.Board.IsAttacked(king, position.Turn.Opposite())
+}
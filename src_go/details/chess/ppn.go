@@ -0,0 +1,101 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProbabilisticBoard is a board parsed from PPN (Partial Position Notation).
+// Every square is either a certain piece, certainly occupied by an unknown piece (`0`, `o` or `O`, all three
+// of unknown color as in the core), maybe occupied (`?`) or certainly free (`-`).
+type ProbabilisticBoard struct {
+	Certain           Board
+	OccupiedByUnknown Bitboard
+	MaybeOccupied     Bitboard
+}
+
+func (board ProbabilisticBoard) CertainlyOccupied() Bitboard {
+	return board.Certain.Occupied() | board.OccupiedByUnknown
+}
+
+func (board ProbabilisticBoard) CertainlyFree() Bitboard {
+	return ^(board.CertainlyOccupied() | board.MaybeOccupied)
+}
+
+func (board ProbabilisticBoard) Includes(actual Board) bool {
+	for role := range board.Certain.Roles {
+		if !board.Certain.Roles[role].IsSubsetOf(actual.Roles[role]) {
+			return false
+		}
+	}
+	return board.Certain.Colors[White].IsSubsetOf(actual.Colors[White]) &&
+		board.Certain.Colors[Black].IsSubsetOf(actual.Colors[Black]) &&
+		board.CertainlyOccupied().IsSubsetOf(actual.Occupied()) &&
+		board.CertainlyFree().IsSubsetOf(^actual.Occupied())
+}
+
+type PpnError struct {
+	Rank   int
+	File   int
+	Reason string
+}
+
+func (err PpnError) Error() string {
+	if err.Rank == 0 {
+		return "invalid PPN: " + err.Reason
+	}
+	if err.File == 0 {
+		return fmt.Sprintf("invalid PPN at rank %d: %s", err.Rank, err.Reason)
+	}
+	return fmt.Sprintf("invalid PPN at rank %d, file %c: %s", err.Rank, 'a'+err.File-1, err.Reason)
+}
+
+// ParsePpn reads the board part of a PPN, i.e. everything up to the first space.
+// Ranks go from 8 down to 1, files from a to h, and every square has exactly one symbol.
+func ParsePpn(ppn string) (board ProbabilisticBoard, err error) {
+	placement := strings.TrimSpace(ppn)
+	if spaceAt := strings.IndexByte(placement, ' '); spaceAt >= 0 {
+		placement = placement[:spaceAt]
+	}
+
+	if placement == "" {
+		err = PpnError{Reason: "the board is empty"}
+		return
+	}
+
+	ranks := strings.Split(placement, "/")
+	if len(ranks) != 8 {
+		err = PpnError{Reason: fmt.Sprintf("expected 8 ranks separated by '/', got %d", len(ranks))}
+		return
+	}
+
+	for i, rankSymbols := range ranks {
+		rank := 7 - i
+		symbols := []rune(rankSymbols)
+		if len(symbols) != 8 {
+			err = PpnError{Rank: rank + 1, Reason: fmt.Sprintf("expected 8 squares, got %d", len(symbols))}
+			return
+		}
+
+		for file := 0; file < 8; file++ {
+			symbol := symbols[file]
+			square := NewSquare(file, rank)
+			switch symbol {
+			case '-':
+			case '?':
+				board.MaybeOccupied |= square.Bitboard()
+			case '0', 'o', 'O':
+				board.OccupiedByUnknown |= square.Bitboard()
+			default:
+				piece, isPiece := PieceFromSymbol(byte(symbol))
+				if symbol > 'z' || !isPiece {
+					err = PpnError{Rank: rank + 1, File: file + 1, Reason: fmt.Sprintf("unknown symbol %q", symbol)}
+					return
+				}
+				board.Certain.Put(square, piece)
+			}
+		}
+	}
+
+	return
+}
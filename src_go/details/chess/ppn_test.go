@@ -0,0 +1,135 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParsePpn_should_read_pieces_and_partial_information_square_by_square(t *testing.T) {
+	board, err := ParsePpn("????R?r?/?????kq?/????Q???/--------/0Oo?????/????????/????????/????????")
+	assert.NoError(t, err)
+
+	e8, _ := SquareFromString("e8")
+	g8, _ := SquareFromString("g8")
+	f7, _ := SquareFromString("f7")
+	g7, _ := SquareFromString("g7")
+	e6, _ := SquareFromString("e6")
+	a4, _ := SquareFromString("a4")
+	b4, _ := SquareFromString("b4")
+	c4, _ := SquareFromString("c4")
+
+	piece, ok := board.Certain.PieceAt(e8)
+	assert.True(t, ok)
+	assert.Equal(t, Piece{Color: White, Role: Rook}, piece)
+
+	piece, ok = board.Certain.PieceAt(g8)
+	assert.True(t, ok)
+	assert.Equal(t, Piece{Color: Black, Role: Rook}, piece)
+
+	piece, ok = board.Certain.PieceAt(f7)
+	assert.True(t, ok)
+	assert.Equal(t, Piece{Color: Black, Role: King}, piece)
+
+	piece, ok = board.Certain.PieceAt(g7)
+	assert.True(t, ok)
+	assert.Equal(t, Piece{Color: Black, Role: Queen}, piece)
+
+	piece, ok = board.Certain.PieceAt(e6)
+	assert.True(t, ok)
+	assert.Equal(t, Piece{Color: White, Role: Queen}, piece)
+
+	assert.Equal(t, 5, board.Certain.Occupied().Count())
+	assert.Equal(t, a4.Bitboard()|b4.Bitboard()|c4.Bitboard(), board.OccupiedByUnknown)
+	assert.Equal(t, Bitboard(0xFF00000000), board.CertainlyFree())
+}
+
+func Test_ParsePpn_should_ignore_everything_after_the_board(t *testing.T) {
+	_, err := ParsePpn("  ????????/????????/????????/????????/????????/????????/????????/???????? w - -")
+	assert.NoError(t, err)
+}
+
+func Test_ParsePpn_should_name_the_rank_and_the_file_of_an_unknown_symbol(t *testing.T) {
+	_, err := ParsePpn("????????/????????/????x???/????????/????????/????????/????????/????????")
+	assert.Equal(t, PpnError{Rank: 6, File: 5, Reason: "unknown symbol 'x'"}, err)
+	assert.EqualError(t, err, "invalid PPN at rank 6, file e: unknown symbol 'x'")
+}
+
+func Test_ParsePpn_should_name_the_rank_that_has_wrong_amount_of_squares(t *testing.T) {
+	_, err := ParsePpn("????????/????????/????????/????????/????????/????????/?????????/????????")
+	assert.Equal(t, PpnError{Rank: 2, Reason: "expected 8 squares, got 9"}, err)
+	assert.EqualError(t, err, "invalid PPN at rank 2: expected 8 squares, got 9")
+}
+
+func Test_ParsePpn_should_reject_fen_like_compression_of_empty_squares(t *testing.T) {
+	_, err := ParsePpn("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR")
+	assert.Equal(t, PpnError{Rank: 6, Reason: "expected 8 squares, got 1"}, err)
+}
+
+func Test_ParsePpn_should_reject_a_board_without_8_ranks(t *testing.T) {
+	_, err := ParsePpn("not_an_actual_board")
+	assert.EqualError(t, err, "invalid PPN: expected 8 ranks separated by '/', got 1")
+
+	_, err = ParsePpn("")
+	assert.EqualError(t, err, "invalid PPN: the board is empty")
+}
+
+func Test_ProbabilisticBoard_should_include_only_boards_that_satisfy_every_square(t *testing.T) {
+	board, err := ParsePpn("??----rk/?????Npp/??????--/????????/????????/????????/????????/????????")
+	assert.NoError(t, err)
+
+	matching := Board{}
+	putAll(&matching, map[string]Piece{
+		"a8": {Color: Black, Role: Rook},
+		"g8": {Color: Black, Role: Rook},
+		"h8": {Color: Black, Role: King},
+		"f7": {Color: White, Role: Knight},
+		"g7": {Color: Black, Role: Pawn},
+		"h7": {Color: Black, Role: Pawn},
+		"e1": {Color: White, Role: King},
+	})
+	assert.True(t, board.Includes(matching))
+
+	occupiedFreeSquare := matching
+	putAll(&occupiedFreeSquare, map[string]Piece{"g6": {Color: Black, Role: Knight}})
+	assert.False(t, board.Includes(occupiedFreeSquare))
+
+	wrongColor := matching
+	putAll(&wrongColor, map[string]Piece{"f7": {Color: Black, Role: Knight}})
+	assert.False(t, board.Includes(wrongColor))
+}
+
+func Test_ProbabilisticBoard_should_accept_unknown_pieces_of_any_color(t *testing.T) {
+	board, err := ParsePpn("Oo0?????/????????/????????/????????/????????/????????/????????/????????")
+	assert.NoError(t, err)
+
+	matching := Board{}
+	putAll(&matching, map[string]Piece{
+		"a8": {Color: White, Role: Bishop},
+		"b8": {Color: Black, Role: Queen},
+		"c8": {Color: White, Role: Pawn},
+	})
+	assert.True(t, board.Includes(matching))
+
+	swapped := Board{}
+	putAll(&swapped, map[string]Piece{
+		"a8": {Color: Black, Role: Bishop},
+		"b8": {Color: White, Role: Queen},
+		"c8": {Color: Black, Role: Pawn},
+	})
+	assert.True(t, board.Includes(swapped))
+
+	empty := Board{}
+	putAll(&empty, map[string]Piece{
+		"a8": {Color: White, Role: Bishop},
+		"b8": {Color: Black, Role: Queen},
+	})
+	assert.False(t, board.Includes(empty))
+}
+
+func putAll(board *Board, pieces map[string]Piece) {
+	for name, piece := range pieces {
+		square, _ := SquareFromString(name)
+		board.Put(square, piece)
+	}
+}
@@ -0,0 +1,71 @@
+package chess
+
+import (
+	"fmt"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/pgn"
+)
+
+// ReplayPgn parses the PGN of a single game and replays its mainline.
+func ReplayPgn(pgnString string) (positions []Position, err error) {
+	game, err := pgn.ParseGame(pgnString)
+	if err != nil {
+		return
+	}
+	return Replay(game)
+}
+
+// Replay plays the mainline of the game from its setup. The first position is the setup,
+// the position at index i is the one after the i-th ply.
+func Replay(game pgn.Game) (positions []Position, err error) {
+	position := StandardPosition()
+	if fen := game.Tag("FEN"); fen != "" {
+		position, err = ParseFen(fen)
+		if err != nil {
+			return
+		}
+	}
+
+	positions = make([]Position, 0, len(game.Moves)+1)
+	positions = append(positions, position)
+	for ply, san := range mainlineSans(game) {
+		move, sanErr := position.ParseSan(san)
+		if sanErr != nil {
+			err = fmt.Errorf("ply %d: %w", ply+1, sanErr)
+			return
+		}
+		position = position.Play(move)
+		positions = append(positions, position)
+	}
+	return
+}
+
+// Occurrence is the first position of a game that includes the searched board.
+// Ply 0 is the setup, ply i is the position after the i-th half move.
+type Occurrence struct {
+	Ply      int
+	Position Position
+}
+
+// Find looks for the first position of the game, the setup included, that includes the board and satisfies the constraints.
+// A game that cannot be parsed or replayed completely is never found, the same way the core treats it.
+func Find(pgnString string, board ProbabilisticBoard, constraints Constraints) (occurrence Occurrence, found bool, err error) {
+	positions, err := ReplayPgn(pgnString)
+	if err != nil {
+		return
+	}
+	for ply, position := range positions {
+		if board.Includes(position.Board) && constraints.SatisfiedBy(position) {
+			return Occurrence{Ply: ply, Position: position}, true, nil
+		}
+	}
+	return
+}
+
+func mainlineSans(game pgn.Game) (sans []string) {
+	sans = make([]string, len(game.Moves))
+	for ply, move := range game.Moves {
+		sans[ply] = move.San
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Replay_should_play_castling_en_passant_and_promotion(t *testing.T) {
+	pgn := `[Event "Live Chess"]
+[White "white"]
+[Black "black"]
+
+1. e4 {[%clk 0:09:58]} 1... d5 2. e5 f5 3. exf6 $1 (3. d4 e6) 3... Nc6 4. fxg7 Bd7
+5. gxh8=Q e6 6. Nf3 Qe7 7. Bc4 O-O-O 8. O-O e5 9. Qxg8 e4 10. Qxf8 exf3 11. Qxe7 fxg2
+12. Qxd7+ Kxd7 13. d3 gxf1=N 0-1`
+
+	positions, err := ReplayPgn(pgn)
+	assert.NoError(t, err)
+	assert.Len(t, positions, 27)
+	assert.Equal(t, StandardFen, positions[0].Fen())
+	assert.Equal(t, "rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3", positions[4].Fen())
+	assert.Equal(t, "rnbqkbnr/ppp1p1pp/5P2/3p4/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3", positions[5].Fen())
+	assert.Equal(t, "r2qkbnQ/pppbp2p/2n5/3p4/8/8/PPPP1PPP/RNBQKBNR b KQq - 0 5", positions[9].Fen())
+	assert.Equal(t, "2kr1bnQ/pppbq2p/2n1p3/3p4/2B5/5N2/PPPP1PPP/RNBQK2R w KQ - 4 8", positions[14].Fen())
+	assert.Equal(t, "3r4/pppk3p/2n5/3p4/2B5/3P4/PPP2P1P/RNBQ1nK1 w - - 0 14", positions[26].Fen())
+}
+
+func Test_Replay_should_start_from_the_fen_tag(t *testing.T) {
+	pgn := `[FEN "4k3/8/8/8/8/8/8/R3K2R w KQ - 0 1"]
+
+1. O-O-O Kf7 2. Rh7+ *`
+
+	positions, err := ReplayPgn(pgn)
+	assert.NoError(t, err)
+	assert.Equal(t, "8/5k1R/8/8/8/8/8/2KR4 b - - 3 2", positions[3].Fen())
+}
+
+func Test_Replay_should_fail_on_illegal_and_ambiguous_moves(t *testing.T) {
+	_, err := ReplayPgn("1. e4 e5 2. Ke3")
+	assert.EqualError(t, err, `ply 3: move "Ke3": illegal in rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2`)
+
+	_, err = ReplayPgn("1. e4 e5 2. Bb5 Nc6 3. Bxe5")
+	assert.ErrorContains(t, err, "illegal")
+
+	_, err = ReplayPgn("1. Nf3 a6 2. Nc3 a5 3. Ne4 a4 4. Ng5")
+	assert.ErrorContains(t, err, "ambiguous")
+}
+
+func Test_Replay_should_not_move_pinned_pieces(t *testing.T) {
+	positions, err := ReplayPgn("1. e4 e5 2. d4 Bb4+ 3. Nd2 Nc6 4. Nf3")
+	assert.NoError(t, err)
+	assert.Equal(t, "r1bqk1nr/pppp1ppp/2n5/4p3/1b1PP3/5N2/PPPN1PPP/R1BQKB1R b KQkq - 4 4", positions[7].Fen())
+
+	_, err = ReplayPgn("1. d4 e5 2. Nd2 Bb4 3. Nb3")
+	assert.ErrorContains(t, err, "illegal")
+}
+
+func Test_Find_should_check_every_position_of_the_game(t *testing.T) {
+	pgn := "1. e4 e5 2. Nf3 Nc6 1-0"
+
+	board, _ := ParsePpn("????????/????????/????????/????????/????P???/?????N??/????????/????????")
+	occurrence, found, err := Find(pgn, board, Constraints{})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 3, occurrence.Ply)
+	assert.Equal(t, "rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2", occurrence.Position.Fen())
+
+	board, _ = ParsePpn("rnbqkbnr/pppppppp/--------/--------/--------/--------/PPPPPPPP/RNBQKBNR")
+	occurrence, found, err = Find(pgn, board, Constraints{})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 0, occurrence.Ply)
+
+	board, _ = ParsePpn("????????/????????/????????/????????/????????/??N?????/????????/????????")
+	_, found, err = Find(pgn, board, Constraints{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func Test_Find_should_never_find_a_board_in_a_game_that_cannot_be_replayed(t *testing.T) {
+	board, _ := ParsePpn("????????/????????/????????/????????/????P???/????????/????????/????????")
+	_, found, err := Find("1. e4 e5 2. Ke3", board, Constraints{})
+	assert.Error(t, err)
+	assert.False(t, found)
+}
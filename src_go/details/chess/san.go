@@ -0,0 +1,197 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SanError struct {
+	San    string
+	Reason string
+}
+
+func (err SanError) Error() string {
+	return fmt.Sprintf("move %q: %s", err.San, err.Reason)
+}
+
+// ParseSan resolves a move in Standard Algebraic Notation against the position.
+// Check, mate and annotation suffixes are ignored, castling may be written with zeros.
+func (position Position) ParseSan(san string) (move Move, err error) {
+	text := strings.TrimRight(san, "+#!?")
+
+	switch text {
+	case "O-O", "0-0":
+		return position.castling(san, true)
+	case "O-O-O", "0-0-0":
+		return position.castling(san, false)
+	}
+
+	role := Pawn
+	if len(text) > 0 && strings.IndexByte("NBRQK", text[0]) >= 0 {
+		piece, _ := PieceFromSymbol(text[0])
+		role = piece.Role
+		text = text[1:]
+	}
+
+	move.Promotion = Pawn
+	if role == Pawn && len(text) > 2 {
+		last := text[len(text)-1]
+		if strings.IndexByte("NBRQ", last) >= 0 {
+			piece, _ := PieceFromSymbol(last)
+			move.Promotion = piece.Role
+			text = strings.TrimSuffix(text[:len(text)-1], "=")
+		}
+	}
+
+	if len(text) < 2 {
+		err = SanError{San: san, Reason: "no destination square"}
+		return
+	}
+	to, ok := SquareFromString(text[len(text)-2:])
+	if !ok {
+		err = SanError{San: san, Reason: "no destination square"}
+		return
+	}
+	move.To = to
+
+	fromFile, fromRank := -1, -1
+	for _, symbol := range []byte(text[:len(text)-2]) {
+		switch {
+		case symbol == 'x' || symbol == ':' || symbol == '-':
+		case symbol >= 'a' && symbol <= 'h':
+			fromFile = int(symbol - 'a')
+		case symbol >= '1' && symbol <= '8':
+			fromRank = int(symbol - '1')
+		default:
+			err = SanError{San: san, Reason: fmt.Sprintf("unexpected %q", symbol)}
+			return
+		}
+	}
+
+	promotionRank := 7
+	if position.Turn == Black {
+		promotionRank = 0
+	}
+	if role == Pawn && (to.Rank() == promotionRank) != (move.Promotion != Pawn) {
+		err = SanError{San: san, Reason: "promotion does not match the destination rank"}
+		return
+	}
+
+	candidates := position.origins(Piece{Color: position.Turn, Role: role}, to, fromFile != -1 && role == Pawn)
+	found := 0
+	for _, from := range candidates.Squares() {
+		if fromFile != -1 && from.File() != fromFile || fromRank != -1 && from.Rank() != fromRank {
+			continue
+		}
+		candidate := Move{From: from, To: to, Promotion: move.Promotion}
+		if !position.IsLegal(candidate) {
+			continue
+		}
+		move.From = from
+		found++
+	}
+
+	switch {
+	case found == 0:
+		err = SanError{San: san, Reason: "illegal in " + position.Fen()}
+	case found > 1:
+		err = SanError{San: san, Reason: "ambiguous in " + position.Fen()}
+	}
+	return
+}
+
+// origins returns the squares from which a piece of the side to move can reach the target.
+func (position Position) origins(piece Piece, to Square, pawnCapture bool) Bitboard {
+	own := position.Board.Colors[position.Turn]
+	occupied := position.Board.Occupied()
+	pieces := position.Board.Pieces(piece.Color, piece.Role)
+	if own.Has(to) {
+		return 0
+	}
+
+	if piece.Role != Pawn {
+		// attacks are symmetric for every non pawn piece
+		return Attacks(piece, to, occupied) & pieces
+	}
+
+	if pawnCapture {
+		if occupied.Has(to) || to == position.EnPassant {
+			return pawnAttacks[piece.Color.Opposite()][to] & pieces
+		}
+		return 0
+	}
+
+	if occupied.Has(to) {
+		return 0
+	}
+	forward := 8
+	startRank := 1
+	if piece.Color == Black {
+		forward = -8
+		startRank = 6
+	}
+	from := to - Square(forward)
+	if from < 0 || from > 63 {
+		return 0
+	}
+	if pieces.Has(from) {
+		return from.Bitboard()
+	}
+	if occupied.Has(from) {
+		return 0
+	}
+	from -= Square(forward)
+	if from >= 0 && from <= 63 && from.Rank() == startRank && pieces.Has(from) {
+		return from.Bitboard()
+	}
+	return 0
+}
+
+func (position Position) castling(san string, kingside bool) (move Move, err error) {
+	rank := 0
+	rights := WhiteQueenside
+	if kingside {
+		rights = WhiteKingside
+	}
+	if position.Turn == Black {
+		rank = 7
+		rights <<= 2
+	}
+
+	king := NewSquare(4, rank)
+	rookFile, kingTo, between := 0, NewSquare(2, rank), []int{1, 2, 3}
+	if kingside {
+		rookFile, kingTo, between = 7, NewSquare(6, rank), []int{5, 6}
+	}
+
+	if position.Castling&rights == 0 ||
+		!position.Board.Pieces(position.Turn, King).Has(king) ||
+		!position.Board.Pieces(position.Turn, Rook).Has(NewSquare(rookFile, rank)) {
+		err = SanError{San: san, Reason: "no castling rights in " + position.Fen()}
+		return
+	}
+	for _, file := range between {
+		if position.Board.Occupied().Has(NewSquare(file, rank)) {
+			err = SanError{San: san, Reason: "castling path is blocked in " + position.Fen()}
+			return
+		}
+	}
+	opponent := position.Turn.Opposite()
+	for square := king; ; {
+		if position.Board.IsAttacked(square, opponent) {
+			err = SanError{San: san, Reason: "castling through check in " + position.Fen()}
+			return
+		}
+		if square == kingTo {
+			break
+		}
+		if kingside {
+			square++
+		} else {
+			square--
+		}
+	}
+
+	move = Move{From: king, To: kingTo}
+	return
+}
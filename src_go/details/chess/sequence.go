@@ -0,0 +1,81 @@
+package chess
+
+// FindSequence looks for the boards occurring in the given order, every board at most maxPlyGap plies
+// after the previous one. The constraints apply to the first board only.
+// The occurrences of the earliest completed sequence are returned, one per board.
+func FindSequence(pgnString string, boards []ProbabilisticBoard, maxPlyGap int, constraints Constraints) (occurrences []Occurrence, found bool, err error) {
+	if len(boards) == 0 {
+		return
+	}
+	positions, err := ReplayPgn(pgnString)
+	if err != nil {
+		return
+	}
+	occurrences, found = findSequence(positions, boards, maxPlyGap, constraints)
+	return
+}
+
+// FindSequenceInMoves is FindSequence for the moves encoded by EncodeMoves and played from the standard position.
+func FindSequenceInMoves(encoded []byte, boards []ProbabilisticBoard, maxPlyGap int, constraints Constraints) (occurrences []Occurrence, found bool, err error) {
+	if len(boards) == 0 {
+		return
+	}
+	positions, err := ReplayMoves(StandardPosition(), encoded)
+	if err != nil {
+		return
+	}
+	occurrences, found = findSequence(positions, boards, maxPlyGap, constraints)
+	return
+}
+
+func findSequence(positions []Position, boards []ProbabilisticBoard, maxPlyGap int, constraints Constraints) (occurrences []Occurrence, found bool) {
+
+	// reachable[step][ply] tells whether the first step+1 boards can occur in order with the last one at ply
+	reachable := make([][]bool, len(boards))
+	for step, board := range boards {
+		reachable[step] = make([]bool, len(positions))
+		lastReachable := -1
+		for ply, position := range positions {
+			if step > 0 && ply > 0 && reachable[step-1][ply-1] {
+				lastReachable = ply - 1
+			}
+			if !board.Includes(position.Board) {
+				continue
+			}
+			if step == 0 {
+				reachable[step][ply] = constraints.SatisfiedBy(position)
+			} else {
+				reachable[step][ply] = lastReachable >= 0 && ply-lastReachable <= maxPlyGap
+			}
+		}
+	}
+
+	last := len(boards) - 1
+	end := -1
+	for ply := range positions {
+		if reachable[last][ply] {
+			end = ply
+			break
+		}
+	}
+	if end < 0 {
+		return
+	}
+
+	plies := make([]int, len(boards))
+	plies[last] = end
+	for step := last - 1; step >= 0; step-- {
+		for ply := max(plies[step+1]-maxPlyGap, 0); ply < plies[step+1]; ply++ {
+			if reachable[step][ply] {
+				plies[step] = ply
+				break
+			}
+		}
+	}
+
+	occurrences = make([]Occurrence, len(boards))
+	for step, ply := range plies {
+		occurrences[step] = Occurrence{Ply: ply, Position: positions[ply]}
+	}
+	return occurrences, true
+}
@@ -0,0 +1,74 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindSequence_should_find_the_boards_in_order_within_the_gap(t *testing.T) {
+	pgn := "1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 4. Ba4 Nf6 5. O-O Be7 1-0"
+	knightOnF3, _ := ParsePpn("????????/????????/????????/????????/????????/?????N??/????????/????????")
+	bishopOnA4, _ := ParsePpn("????????/????????/????????/????????/B???????/????????/????????/????????")
+	castled, _ := ParsePpn("????????/????????/????????/????????/????????/????????/????????/?????RK?")
+
+	occurrences, found, err := FindSequence(pgn, []ProbabilisticBoard{knightOnF3, bishopOnA4, castled}, 4, Constraints{})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []int{3, 7, 9}, plies(occurrences))
+	assert.Equal(t, "r1bqkb1r/1ppp1ppp/p1n2n2/4p3/B3P3/5N2/PPPP1PPP/RNBQ1RK1 b kq - 3 5", occurrences[2].Position.Fen())
+
+	occurrences, found, err = FindSequence(pgn, []ProbabilisticBoard{knightOnF3, bishopOnA4, castled}, 3, Constraints{})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []int{4, 7, 9}, plies(occurrences))
+
+	knightOnG1AgainstE5, _ := ParsePpn("????????/????????/????????/????p???/????????/????????/????????/??????N?")
+
+	occurrences, found, err = FindSequence(pgn, []ProbabilisticBoard{knightOnG1AgainstE5, castled}, 7, Constraints{})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []int{2, 9}, plies(occurrences))
+
+	_, found, err = FindSequence(pgn, []ProbabilisticBoard{knightOnG1AgainstE5, castled}, 6, Constraints{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, found, err = FindSequence(pgn, []ProbabilisticBoard{castled, knightOnG1AgainstE5}, 10, Constraints{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func Test_FindSequence_should_not_match_two_boards_in_the_same_position(t *testing.T) {
+	pgn := "1. e4 e5 1-0"
+	pawnOnE4, _ := ParsePpn("????????/????????/????????/????????/????P???/????????/????????/????????")
+	pawnOnE5, _ := ParsePpn("????????/????????/????????/????p???/????????/????????/????????/????????")
+
+	occurrences, found, err := FindSequence(pgn, []ProbabilisticBoard{pawnOnE4, pawnOnE5}, 1, Constraints{})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []int{1, 2}, plies(occurrences))
+
+	_, found, err = FindSequence(pgn, []ProbabilisticBoard{pawnOnE5, pawnOnE4}, 1, Constraints{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func Test_FindSequence_should_apply_the_constraints_to_the_first_board(t *testing.T) {
+	pgn := "1. e4 e5 2. Nf3 Nc6 1-0"
+	pawnOnE4, _ := ParsePpn("????????/????????/????????/????????/????P???/????????/????????/????????")
+	knightOnC6, _ := ParsePpn("????????/????????/??n?????/????????/????????/????????/????????/????????")
+
+	whiteToMove, _ := ParseConstraints("white", "", "")
+	occurrences, found, err := FindSequence(pgn, []ProbabilisticBoard{pawnOnE4, knightOnC6}, 2, whiteToMove)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []int{2, 4}, plies(occurrences))
+}
+
+func plies(occurrences []Occurrence) (plies []int) {
+	for _, occurrence := range occurrences {
+		plies = append(plies, occurrence.Ply)
+	}
+	return
+}
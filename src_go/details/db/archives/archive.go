@@ -12,4 +12,13 @@ type ArchiveRecord struct {
 	Month        int              `dynamodbav:"month"`
 	Downloaded   int              `dynamodbav:"downloaded"`
 	DownloadedAt *db.ZuluDateTime `dynamodbav:"downloaded_at"`
+	// Uploaded archives hold the games of a PGN file uploaded by the user and are never downloaded from the platform.
+	Uploaded bool `dynamodbav:"uploaded,omitempty"`
+	// Etag and LastModified are the validators of the last answer of the platform for the archive.
+	// They are sent back so that an unchanged archive is not downloaded again.
+	Etag         string `dynamodbav:"etag,omitempty"`
+	LastModified string `dynamodbav:"last_modified,omitempty"`
+	// LastGameEndTimestamp is the end of the latest game stored by the downloads of the archive, the games ending later are missing.
+	// It only moves once a download has stored all of its games, so a download failing halfway starts again from the same point.
+	LastGameEndTimestamp int64 `dynamodbav:"last_game_end_timestamp,omitempty"`
 }
@@ -168,3 +168,47 @@ func Test_ArchiveRecord_should_be_stored_in_correct_form_even_if_some_of_its_fie
 
 	assert.Equal(t, expectedArchive, actualArchive)
 }
+
+func Test_uploaded_ArchiveRecord_should_be_marked_as_uploaded(t *testing.T) {
+	archive := ArchiveRecord{
+		UserId:     "https://api.chess.com/pub/player/tigran-c-137",
+		ArchiveId:  "https://api.chess.com/pub/player/tigran-c-137/uploads/5e884898da280471",
+		Resource:   "https://api.chess.com/pub/player/tigran-c-137/uploads/5e884898da280471",
+		Year:       2023,
+		Month:      10,
+		Downloaded: 0,
+		Uploaded:   true,
+	}
+
+	actualMarshalledItems, err := dynamodbattribute.MarshalMap(archive)
+	assert.NoError(t, err)
+	assert.Equal(t, &dynamodb.AttributeValue{BOOL: aws.Bool(true)}, actualMarshalledItems["uploaded"])
+
+	actualArchive := ArchiveRecord{}
+	err = dynamodbattribute.UnmarshalMap(actualMarshalledItems, &actualArchive)
+	assert.NoError(t, err)
+	assert.Equal(t, archive, actualArchive)
+}
+
+func Test_ArchiveRecord_should_keep_the_validators_of_the_last_answer(t *testing.T) {
+	archive := ArchiveRecord{
+		UserId:       "https://api.chess.com/pub/player/tigran-c-137",
+		ArchiveId:    "https://api.chess.com/pub/player/tigran-c-137/games/2023/10",
+		Resource:     "https://api.chess.com/pub/player/tigran-c-137/games/2023/10",
+		Year:         2023,
+		Month:        10,
+		Downloaded:   12,
+		Etag:         `W/"6a1f2e3d"`,
+		LastModified: "Tue, 31 Oct 2023 22:17:04 GMT",
+	}
+
+	actualMarshalledItems, err := dynamodbattribute.MarshalMap(archive)
+	assert.NoError(t, err)
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String(`W/"6a1f2e3d"`)}, actualMarshalledItems["etag"])
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("Tue, 31 Oct 2023 22:17:04 GMT")}, actualMarshalledItems["last_modified"])
+
+	actualArchive := ArchiveRecord{}
+	err = dynamodbattribute.UnmarshalMap(actualMarshalledItems, &actualArchive)
+	assert.NoError(t, err)
+	assert.Equal(t, archive, actualArchive)
+}
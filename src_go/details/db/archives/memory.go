@@ -0,0 +1,67 @@
+package archives
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ ArchivesRepository = &InMemoryArchivesTable{}
+
+// InMemoryArchivesTable behaves as ArchivesTable does, the archives of a user come sorted by their id.
+type InMemoryArchivesTable struct {
+	mutex   sync.Mutex
+	records map[string]map[string]ArchiveRecord
+}
+
+func NewInMemoryArchivesTable() *InMemoryArchivesTable {
+	return &InMemoryArchivesTable{records: map[string]map[string]ArchiveRecord{}}
+}
+
+func (table *InMemoryArchivesTable) GetArchiveRecord(ctx context.Context, userId string, archiveId string) (archiveRecord *ArchiveRecord, err error) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	stored, exists := table.records[userId][archiveId]
+	if !exists {
+		return
+	}
+	archiveRecord = &stored
+	return
+}
+
+func (table *InMemoryArchivesTable) GetArchiveRecords(ctx context.Context, userId string) (archiveRecords []ArchiveRecord, err error) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	for _, stored := range table.records[userId] {
+		archiveRecords = append(archiveRecords, stored)
+	}
+	sort.Slice(archiveRecords, func(i, j int) bool {
+		return archiveRecords[i].ArchiveId < archiveRecords[j].ArchiveId
+	})
+	return
+}
+
+func (table *InMemoryArchivesTable) PutArchiveRecord(ctx context.Context, archiveRecord ArchiveRecord) (err error) {
+	return table.PutArchiveRecords(ctx, []ArchiveRecord{archiveRecord})
+}
+
+func (table *InMemoryArchivesTable) PutArchiveRecords(ctx context.Context, archiveRecords []ArchiveRecord) (err error) {
+	storedRecords := make([]ArchiveRecord, len(archiveRecords))
+	for i, archiveRecord := range archiveRecords {
+		storedRecords[i], err = db.RoundTrip(archiveRecord)
+		if err != nil {
+			return
+		}
+	}
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	for _, stored := range storedRecords {
+		if table.records[stored.UserId] == nil {
+			table.records[stored.UserId] = map[string]ArchiveRecord{}
+		}
+		table.records[stored.UserId][stored.ArchiveId] = stored
+	}
+	return
+}
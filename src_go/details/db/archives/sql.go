@@ -0,0 +1,95 @@
+package archives
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ ArchivesRepository = SqlArchivesTable{}
+
+// SqlArchivesTable behaves as ArchivesTable does, the archives of a user are sorted by their id.
+type SqlArchivesTable struct {
+	Database *db.SqlDatabase
+}
+
+var archiveColumns = []string{"user_id", "archive_id", "resource", "year", "month", "downloaded", "downloaded_at", "uploaded", "etag", "last_modified", "last_game_end_timestamp"}
+
+const selectArchives = `SELECT user_id, archive_id, resource, year, month, downloaded, downloaded_at, uploaded, etag, last_modified, last_game_end_timestamp FROM archives`
+
+func (table SqlArchivesTable) GetArchiveRecord(ctx context.Context, userId string, archiveId string) (archiveRecord *ArchiveRecord, err error) {
+	archiveRecordCandidate := ArchiveRecord{}
+	err = table.Database.QueryRow(ctx, selectArchives+` WHERE user_id = ? AND archive_id = ?`, userId, archiveId).
+		Scan(archiveFields(&archiveRecordCandidate)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	archiveRecord = &archiveRecordCandidate
+	return
+}
+
+func (table SqlArchivesTable) GetArchiveRecords(ctx context.Context, userId string) (archiveRecords []ArchiveRecord, err error) {
+	rows, err := table.Database.Query(ctx, selectArchives+` WHERE user_id = ? ORDER BY archive_id`, userId)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		archiveRecord := ArchiveRecord{}
+		err = rows.Scan(archiveFields(&archiveRecord)...)
+		if err != nil {
+			return
+		}
+		archiveRecords = append(archiveRecords, archiveRecord)
+	}
+	err = rows.Err()
+	return
+}
+
+func (table SqlArchivesTable) PutArchiveRecord(ctx context.Context, archiveRecord ArchiveRecord) (err error) {
+	return table.PutArchiveRecords(ctx, []ArchiveRecord{archiveRecord})
+}
+
+func (table SqlArchivesTable) PutArchiveRecords(ctx context.Context, archiveRecords []ArchiveRecord) (err error) {
+	argLists := make([][]any, len(archiveRecords))
+	for i, archiveRecord := range archiveRecords {
+		argLists[i] = []any{
+			archiveRecord.UserId,
+			archiveRecord.ArchiveId,
+			archiveRecord.Resource,
+			archiveRecord.Year,
+			archiveRecord.Month,
+			archiveRecord.Downloaded,
+			archiveRecord.DownloadedAt,
+			archiveRecord.Uploaded,
+			archiveRecord.Etag,
+			archiveRecord.LastModified,
+			archiveRecord.LastGameEndTimestamp,
+		}
+	}
+	return table.Database.InTransaction(ctx, db.Upsert("archives", archiveColumns[:2], archiveColumns), argLists)
+}
+
+func archiveFields(archiveRecord *ArchiveRecord) []any {
+	return []any{
+		&archiveRecord.UserId,
+		&archiveRecord.ArchiveId,
+		&archiveRecord.Resource,
+		&archiveRecord.Year,
+		&archiveRecord.Month,
+		&archiveRecord.Downloaded,
+		&archiveRecord.DownloadedAt,
+		&archiveRecord.Uploaded,
+		&archiveRecord.Etag,
+		&archiveRecord.LastModified,
+		&archiveRecord.LastGameEndTimestamp,
+	}
+}
@@ -0,0 +1,63 @@
+package archives
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/sqltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SqlArchivesTable_should_get_the_archives_of_the_user_sorted_by_their_id(t *testing.T) {
+	table := SqlArchivesTable{Database: sqltest.OpenDatabase(t)}
+	downloadedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
+
+	october := ArchiveRecord{
+		UserId:       "https://api.chess.com/pub/player/tigran-c-137",
+		ArchiveId:    "https://api.chess.com/pub/player/tigran-c-137/games/2023/10",
+		Resource:     "https://api.chess.com/pub/player/tigran-c-137/games/2023/10",
+		Year:         2023,
+		Month:        10,
+		Downloaded:   12,
+		DownloadedAt: &downloadedAt,
+		Etag:         `"5d41402abc4b2a76b9719d911017c592"`,
+		LastModified: "Sun, 01 Oct 2023 11:30:17 GMT",
+	}
+	september := ArchiveRecord{
+		UserId:    "https://api.chess.com/pub/player/tigran-c-137",
+		ArchiveId: "https://api.chess.com/pub/player/tigran-c-137/games/2023/09",
+		Resource:  "https://api.chess.com/pub/player/tigran-c-137/games/2023/09",
+		Year:      2023,
+		Month:     9,
+	}
+	otherUsers := ArchiveRecord{
+		UserId:    "https://api.chess.com/pub/player/n-60",
+		ArchiveId: "https://api.chess.com/pub/player/n-60/games/2023/09",
+		Resource:  "https://api.chess.com/pub/player/n-60/games/2023/09",
+		Year:      2023,
+		Month:     9,
+	}
+
+	err := table.PutArchiveRecords(context.Background(), []ArchiveRecord{october, september})
+	assert.NoError(t, err)
+	err = table.PutArchiveRecord(context.Background(), otherUsers)
+	assert.NoError(t, err)
+
+	actualArchives, err := table.GetArchiveRecords(context.Background(), "https://api.chess.com/pub/player/tigran-c-137")
+	assert.NoError(t, err)
+	assert.Equal(t, []ArchiveRecord{september, october}, actualArchives)
+
+	actualArchive, err := table.GetArchiveRecord(context.Background(), october.UserId, october.ArchiveId)
+	assert.NoError(t, err)
+	assert.Equal(t, &october, actualArchive)
+
+	actualArchive, err = table.GetArchiveRecord(context.Background(), october.UserId, "https://api.chess.com/pub/player/tigran-c-137/games/2023/11")
+	assert.NoError(t, err)
+	assert.Nil(t, actualArchive)
+
+	actualArchives, err = table.GetArchiveRecords(context.Background(), "https://api.chess.com/pub/player/nobody")
+	assert.NoError(t, err)
+	assert.Nil(t, actualArchives)
+}
@@ -1,6 +1,7 @@
 package archives
 
 import (
+	"context"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -10,16 +11,26 @@ import (
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
 )
 
+type ArchivesRepository interface {
+	GetArchiveRecord(ctx context.Context, userId string, archiveId string) (*ArchiveRecord, error)
+	GetArchiveRecords(ctx context.Context, userId string) ([]ArchiveRecord, error)
+	PutArchiveRecord(ctx context.Context, archiveRecord ArchiveRecord) error
+	PutArchiveRecords(ctx context.Context, archiveRecords []ArchiveRecord) error
+}
+
+var _ ArchivesRepository = ArchivesTable{}
+
 type ArchivesTable struct {
 	Name           string
 	DynamodbClient *dynamodb.DynamoDB
 }
 
 func (table ArchivesTable) GetArchiveRecord(
+	ctx context.Context,
 	userId string,
 	archiveId string,
 ) (archiveRecord *ArchiveRecord, err error) {
-	items, err := table.DynamodbClient.GetItem(&dynamodb.GetItemInput{
+	items, err := table.DynamodbClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"user_id": {
@@ -50,9 +61,10 @@ func (table ArchivesTable) GetArchiveRecord(
 }
 
 func (table ArchivesTable) GetArchiveRecords(
+	ctx context.Context,
 	userId string,
 ) (archiveRecords []ArchiveRecord, err error) {
-	items, err := table.DynamodbClient.Query(&dynamodb.QueryInput{
+	items, err := table.DynamodbClient.QueryWithContext(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(table.Name),
 		KeyConditionExpression: aws.String("user_id = :user_id"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
@@ -79,6 +91,7 @@ func (table ArchivesTable) GetArchiveRecords(
 }
 
 func (table ArchivesTable) PutArchiveRecord(
+	ctx context.Context,
 	archiveRecord ArchiveRecord,
 ) (err error) {
 	archiveRecordItems, err := dynamodbattribute.MarshalMap(archiveRecord)
@@ -86,7 +99,7 @@ func (table ArchivesTable) PutArchiveRecord(
 		return
 	}
 
-	_, err = table.DynamodbClient.PutItem(&dynamodb.PutItemInput{
+	_, err = table.DynamodbClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(table.Name),
 		Item:      archiveRecordItems,
 	})
@@ -99,6 +112,7 @@ func (table ArchivesTable) PutArchiveRecord(
 }
 
 func (table ArchivesTable) PutArchiveRecords(
+	ctx context.Context,
 	archiveRecords []ArchiveRecord,
 ) (err error) {
 	archiveRecordWriteRequests := make([]*dynamodb.WriteRequest, len(archiveRecords))
@@ -128,7 +142,7 @@ func (table ArchivesTable) PutArchiveRecords(
 
 		for len(unprocessedWriteRequests) > 0 {
 			var writeOutput *dynamodb.BatchWriteItemOutput
-			writeOutput, err = table.DynamodbClient.BatchWriteItem(&dynamodb.BatchWriteItemInput{
+			writeOutput, err = table.DynamodbClient.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
 				RequestItems: unprocessedWriteRequests,
 			})
 
@@ -1,6 +1,7 @@
 package archives
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -41,7 +42,7 @@ func Test_ArchiveTable_should_get_the_archive_record_from_the_table(t *testing.T
 	})
 	assert.NoError(t, err)
 
-	actualArchive, err := archivesTable.GetArchiveRecord(userId, archiveId)
+	actualArchive, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualArchive)
 
@@ -105,7 +106,7 @@ func Test_ArchiveTable_should_get_all_the_archive_records_for_the_given_user(t *
 	})
 	assert.NoError(t, err)
 
-	actualArchives, err := archivesTable.GetArchiveRecords(userId)
+	actualArchives, err := archivesTable.GetArchiveRecords(context.Background(), userId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualArchives)
 
@@ -132,7 +133,7 @@ func Test_ArchiveTable_should_persist_an_archive_record_into_the_table(t *testin
 		Downloaded:   0,
 	}
 
-	err = archivesTable.PutArchiveRecord(archive_2021_10)
+	err = archivesTable.PutArchiveRecord(context.Background(), archive_2021_10)
 	assert.NoError(t, err)
 
 	archive_2021_10_items, err := archivesTable.DynamodbClient.GetItem(&dynamodb.GetItemInput{
@@ -188,7 +189,7 @@ func Test_ArchiveTable_should_persist_all_the_archive_records_at_once_into_the_t
 		Downloaded:   0,
 	}
 
-	err = archivesTable.PutArchiveRecords([]ArchiveRecord{archive_2021_10, archive_2021_11})
+	err = archivesTable.PutArchiveRecords(context.Background(), []ArchiveRecord{archive_2021_10, archive_2021_11})
 	assert.NoError(t, err)
 
 	archive_2021_10_items, err := archivesTable.DynamodbClient.GetItem(&dynamodb.GetItemInput{
@@ -265,7 +266,7 @@ func Test_ArchiveTable_should_persist_a_lot_of_archive_records_into_the_table(t
 		archives[i] = archive
 	}
 
-	err = archivesTable.PutArchiveRecords(archives)
+	err = archivesTable.PutArchiveRecords(context.Background(), archives)
 	assert.NoError(t, err)
 
 	var lastKey map[string]*dynamodb.AttributeValue
@@ -0,0 +1,75 @@
+package blobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps the content that does not fit into a DynamoDB item.
+type BlobStore interface {
+	PutBlob(ctx context.Context, key string, content []byte) error
+	GetBlob(ctx context.Context, key string) ([]byte, error)
+}
+
+type S3BlobStore struct {
+	Bucket   string
+	S3Client *s3.S3
+}
+
+func (store S3BlobStore) PutBlob(ctx context.Context, key string, content []byte) (err error) {
+	_, err = store.S3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(content),
+	})
+	return
+}
+
+func (store S3BlobStore) GetBlob(ctx context.Context, key string) (content []byte, err error) {
+	object, err := store.S3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(store.Bucket),
+		Key:    aws.String(key),
+	})
+	if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == s3.ErrCodeNoSuchKey {
+		err = fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+		return
+	}
+	if err != nil {
+		return
+	}
+	defer object.Body.Close()
+	return io.ReadAll(object.Body)
+}
+
+// DirectoryBlobStore keeps the blobs as files under Root, it stands in for S3 locally and in tests.
+type DirectoryBlobStore struct {
+	Root string
+}
+
+func (store DirectoryBlobStore) PutBlob(ctx context.Context, key string, content []byte) (err error) {
+	path := filepath.Join(store.Root, filepath.FromSlash(key))
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return
+	}
+	return os.WriteFile(path, content, 0o644)
+}
+
+func (store DirectoryBlobStore) GetBlob(ctx context.Context, key string) (content []byte, err error) {
+	content, err = os.ReadFile(filepath.Join(store.Root, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		err = fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	return
+}
@@ -0,0 +1,26 @@
+package blobs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DirectoryBlobStore_should_return_the_blob_it_has_put(t *testing.T) {
+	store := DirectoryBlobStore{Root: t.TempDir()}
+
+	err := store.PutBlob(context.Background(), "pgn/5e884898da280471", []byte("1. e4 e5 2. Nf3 Nc6 1-0"))
+	assert.NoError(t, err)
+
+	actualContent, err := store.GetBlob(context.Background(), "pgn/5e884898da280471")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1. e4 e5 2. Nf3 Nc6 1-0"), actualContent)
+}
+
+func Test_DirectoryBlobStore_should_not_find_a_blob_that_was_never_put(t *testing.T) {
+	store := DirectoryBlobStore{Root: t.TempDir()}
+
+	_, err := store.GetBlob(context.Background(), "pgn/5e884898da280471")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
@@ -0,0 +1,89 @@
+package downloads
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ DownloadsRepository = &InMemoryDownloadsTable{}
+
+// InMemoryDownloadsTable behaves as DownloadsTable does. Like the time to live of DynamoDB,
+// which deletes the expired items some time later, it keeps the expired records until ExpireRecords is called.
+type InMemoryDownloadsTable struct {
+	mutex   sync.Mutex
+	records map[string]DownloadRecord
+}
+
+func NewInMemoryDownloadsTable() *InMemoryDownloadsTable {
+	return &InMemoryDownloadsTable{records: map[string]DownloadRecord{}}
+}
+
+func (table *InMemoryDownloadsTable) PutDownloadRecord(ctx context.Context, downloadRecord DownloadRecord) (err error) {
+	stored, err := db.RoundTrip(downloadRecord)
+	if err != nil {
+		return
+	}
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.records[downloadRecord.DownloadId.String()] = stored
+	return
+}
+
+func (table *InMemoryDownloadsTable) GetDownloadRecord(ctx context.Context, downloadId string) (downloadRecord *DownloadRecord, err error) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	stored, exists := table.records[downloadId]
+	if !exists {
+		return
+	}
+	downloadRecord = &stored
+	return
+}
+
+func (table *InMemoryDownloadsTable) IncrementSuccess(ctx context.Context, downloadId string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	return table.increment(downloadId, true, now, expiresIn)
+}
+
+func (table *InMemoryDownloadsTable) IncrementFailure(ctx context.Context, downloadId string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	return table.increment(downloadId, false, now, expiresIn)
+}
+
+func (table *InMemoryDownloadsTable) increment(downloadId string, succeed bool, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	stored, exists := table.records[downloadId]
+	if !exists || stored.Done >= stored.Total {
+		return fmt.Errorf("%w: %s", ErrDownloadAlreadyDone, downloadId)
+	}
+	stored.Done++
+	stored.Pending--
+	if succeed {
+		stored.Succeed++
+	} else {
+		stored.Failed++
+	}
+	stored.LastDownloadedAt = now
+	stored.ExpiresAt = dynamodbattribute.UnixTime(now.ToTime().Add(expiresIn))
+	stored, err = db.RoundTrip(stored)
+	if err != nil {
+		return
+	}
+	table.records[downloadId] = stored
+	return
+}
+
+// ExpireRecords deletes the records whose time to live is over.
+func (table *InMemoryDownloadsTable) ExpireRecords(now time.Time) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	for downloadId, stored := range table.records {
+		if time.Time(stored.ExpiresAt).Before(now) {
+			delete(table.records, downloadId)
+		}
+	}
+}
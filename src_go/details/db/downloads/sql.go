@@ -0,0 +1,122 @@
+package downloads
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ DownloadsRepository = SqlDownloadsTable{}
+
+// SqlDownloadsTable behaves as DownloadsTable does. The expired records stay until ExpireRecords is called.
+type SqlDownloadsTable struct {
+	Database *db.SqlDatabase
+}
+
+var downloadColumns = []string{"download_id", "start_at", "last_downloaded_at", "expires_at", "succeed", "failed", "done", "pending", "total"}
+
+func (table SqlDownloadsTable) PutDownloadRecord(ctx context.Context, downloadRecord DownloadRecord) (err error) {
+	_, err = table.Database.Exec(
+		ctx,
+		db.Upsert("downloads", downloadColumns[:1], downloadColumns),
+		downloadRecord.DownloadId,
+		downloadRecord.StartAt,
+		downloadRecord.LastDownloadedAt,
+		db.UnixSeconds{Time: &downloadRecord.ExpiresAt},
+		downloadRecord.Succeed,
+		downloadRecord.Failed,
+		downloadRecord.Done,
+		downloadRecord.Pending,
+		downloadRecord.Total,
+	)
+	return
+}
+
+func (table SqlDownloadsTable) GetDownloadRecord(ctx context.Context, downloadId string) (downloadRecord *DownloadRecord, err error) {
+	downloadRecordCandidate := DownloadRecord{}
+	err = table.Database.QueryRow(
+		ctx,
+		`SELECT download_id, start_at, last_downloaded_at, expires_at, succeed, failed, done, pending, total FROM downloads WHERE download_id = ?`,
+		downloadId,
+	).Scan(
+		&downloadRecordCandidate.DownloadId,
+		&downloadRecordCandidate.StartAt,
+		&downloadRecordCandidate.LastDownloadedAt,
+		db.UnixSeconds{Time: &downloadRecordCandidate.ExpiresAt},
+		&downloadRecordCandidate.Succeed,
+		&downloadRecordCandidate.Failed,
+		&downloadRecordCandidate.Done,
+		&downloadRecordCandidate.Pending,
+		&downloadRecordCandidate.Total,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	downloadRecord = &downloadRecordCandidate
+	return
+}
+
+func (table SqlDownloadsTable) IncrementSuccess(ctx context.Context, downloadId string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	return table.increment(ctx, downloadId, "succeed", now, expiresIn)
+}
+
+func (table SqlDownloadsTable) IncrementFailure(ctx context.Context, downloadId string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	return table.increment(ctx, downloadId, "failed", now, expiresIn)
+}
+
+// increment is a single conditional update, so that concurrent workers never lose a count nor go beyond the total.
+func (table SqlDownloadsTable) increment(ctx context.Context, downloadId string, outcome string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	expiresAt := dynamodbattribute.UnixTime(now.ToTime().Add(expiresIn))
+	result, err := table.Database.Exec(
+		ctx,
+		`UPDATE downloads SET done = done + 1, `+outcome+` = `+outcome+` + 1, pending = pending - 1, last_downloaded_at = ?, expires_at = ?
+		WHERE download_id = ? AND done < total`,
+		now,
+		db.UnixSeconds{Time: &expiresAt},
+		downloadId,
+	)
+	if err != nil {
+		return
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if updated == 0 {
+		err = fmt.Errorf("%w: %s", ErrDownloadAlreadyDone, downloadId)
+	}
+	return
+}
+
+// ExpireRecords deletes the records whose time to live is over, as the time to live of DynamoDB does.
+func (table SqlDownloadsTable) ExpireRecords(now time.Time) (err error) {
+	_, err = table.Database.Exec(context.Background(), `DELETE FROM downloads WHERE expires_at < ?`, now.Unix())
+	return
+}
+
+func (id DownloadId) Value() (driver.Value, error) {
+	return id.value, nil
+}
+
+func (id *DownloadId) Scan(src any) error {
+	switch value := src.(type) {
+	case string:
+		id.value = value
+	case []byte:
+		id.value = string(value)
+	default:
+		return fmt.Errorf("impossible to scan %T into a download id", src)
+	}
+	return nil
+}
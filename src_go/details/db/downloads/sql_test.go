@@ -0,0 +1,79 @@
+package downloads
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/sqltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SqlDownloadsTable_should_count_the_parallel_increments_up_to_the_total(t *testing.T) {
+	table := SqlDownloadsTable{Database: sqltest.OpenDatabase(t)}
+	downloadId := NewDownloadId("https://api.chess.com/pub/player/tigran-c-137")
+	startAt := time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC)
+	err := table.PutDownloadRecord(context.Background(), NewDownloadRecord(downloadId, 5, startAt, time.Hour))
+	assert.NoError(t, err)
+
+	now := db.Zuludatetime(startAt.Add(time.Minute))
+	errs := make([]error, 8)
+	wait := sync.WaitGroup{}
+	for worker := range errs {
+		wait.Add(1)
+		go func(worker int) {
+			defer wait.Done()
+			if worker%2 == 0 {
+				errs[worker] = table.IncrementSuccess(context.Background(), downloadId.String(), now, time.Hour)
+			} else {
+				errs[worker] = table.IncrementFailure(context.Background(), downloadId.String(), now, time.Hour)
+			}
+		}(worker)
+	}
+	wait.Wait()
+
+	rejected := 0
+	for _, err := range errs {
+		if err != nil {
+			assert.ErrorIs(t, err, ErrDownloadAlreadyDone)
+			rejected++
+		}
+	}
+	assert.Equal(t, 3, rejected)
+
+	actualDownload, err := table.GetDownloadRecord(context.Background(), downloadId.String())
+	assert.NoError(t, err)
+	assert.Equal(t, downloadId, actualDownload.DownloadId)
+	assert.Equal(t, db.Zuludatetime(startAt), actualDownload.StartAt)
+	assert.Equal(t, 5, actualDownload.Done)
+	assert.Equal(t, 5, actualDownload.Succeed+actualDownload.Failed)
+	assert.Equal(t, 0, actualDownload.Pending)
+	assert.Equal(t, 5, actualDownload.Total)
+	assert.Equal(t, now, actualDownload.LastDownloadedAt)
+	assert.Equal(t, time.Date(2023, time.October, 1, 12, 31, 17, 0, time.UTC), time.Time(actualDownload.ExpiresAt).UTC())
+
+	err = table.IncrementFailure(context.Background(), NewDownloadId("https://api.chess.com/pub/player/nobody").String(), now, time.Hour)
+	assert.ErrorIs(t, err, ErrDownloadAlreadyDone)
+}
+
+func Test_SqlDownloadsTable_should_delete_the_records_whose_time_to_live_is_over(t *testing.T) {
+	table := SqlDownloadsTable{Database: sqltest.OpenDatabase(t)}
+	downloadId := NewDownloadId("https://api.chess.com/pub/player/tigran-c-137")
+	startAt := time.Date(2023, time.October, 1, 11, 30, 17, 0, time.UTC)
+	err := table.PutDownloadRecord(context.Background(), NewDownloadRecord(downloadId, 5, startAt, time.Hour))
+	assert.NoError(t, err)
+
+	err = table.ExpireRecords(startAt.Add(59 * time.Minute))
+	assert.NoError(t, err)
+	actualDownload, err := table.GetDownloadRecord(context.Background(), downloadId.String())
+	assert.NoError(t, err)
+	assert.NotNil(t, actualDownload)
+
+	err = table.ExpireRecords(startAt.Add(61 * time.Minute))
+	assert.NoError(t, err)
+	actualDownload, err = table.GetDownloadRecord(context.Background(), downloadId.String())
+	assert.NoError(t, err)
+	assert.Nil(t, actualDownload)
+}
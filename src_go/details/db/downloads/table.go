@@ -1,27 +1,43 @@
 package downloads
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
 )
 
+// ErrDownloadAlreadyDone is returned when an increment would count more archives than the download has.
+var ErrDownloadAlreadyDone = errors.New("all archives of the download are already done")
+
+type DownloadsRepository interface {
+	PutDownloadRecord(ctx context.Context, downloadRecord DownloadRecord) error
+	GetDownloadRecord(ctx context.Context, downloadId string) (*DownloadRecord, error)
+	IncrementSuccess(ctx context.Context, downloadId string, now db.ZuluDateTime, expiresIn time.Duration) error
+	IncrementFailure(ctx context.Context, downloadId string, now db.ZuluDateTime, expiresIn time.Duration) error
+}
+
+var _ DownloadsRepository = DownloadsTable{}
+
 type DownloadsTable struct {
 	Name           string
 	DynamodbClient *dynamodb.DynamoDB
 }
 
-func (table DownloadsTable) PutDownloadRecord(downloadRecord DownloadRecord) (err error) {
+func (table DownloadsTable) PutDownloadRecord(ctx context.Context, downloadRecord DownloadRecord) (err error) {
 	items, err := dynamodbattribute.MarshalMap(downloadRecord)
 	if err != nil {
 		return
 	}
 
-	_, err = table.DynamodbClient.PutItem(&dynamodb.PutItemInput{
+	_, err = table.DynamodbClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(table.Name),
 		Item:      items,
 	})
@@ -29,8 +45,8 @@ func (table DownloadsTable) PutDownloadRecord(downloadRecord DownloadRecord) (er
 	return
 }
 
-func (table DownloadsTable) GetDownloadRecord(downloadId string) (downloadRecord *DownloadRecord, err error) {
-	items, err := table.DynamodbClient.GetItem(&dynamodb.GetItemInput{
+func (table DownloadsTable) GetDownloadRecord(ctx context.Context, downloadId string) (downloadRecord *DownloadRecord, err error) {
+	items, err := table.DynamodbClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"download_id": {
@@ -56,61 +72,36 @@ func (table DownloadsTable) GetDownloadRecord(downloadId string) (downloadRecord
 	return
 }
 
-func (table DownloadsTable) IncrementSuccess(currentDownload DownloadRecord, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
-	pending := currentDownload.Pending - 1
-	done := currentDownload.Done + 1
-	succeed := currentDownload.Succeed + 1
-	expiresAt := now.ToTime().Add(expiresIn).Unix()
-	_, err = table.DynamodbClient.UpdateItem(&dynamodb.UpdateItemInput{
-		TableName: aws.String(table.Name),
-		Key: map[string]*dynamodb.AttributeValue{
-			"download_id": {
-				S: aws.String(currentDownload.DownloadId.String()),
-			},
-		},
-		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":pending": {
-				N: aws.String(strconv.Itoa(pending)),
-			},
-			":done": {
-				N: aws.String(strconv.Itoa(done)),
-			},
-			":succeed": {
-				N: aws.String(strconv.Itoa(succeed)),
-			},
-			":lastExaminedAt": {
-				S: aws.String(now.String()),
-			},
-			":expiresAt": {
-				N: aws.String(strconv.FormatInt(expiresAt, 10)),
-			},
-		},
-		UpdateExpression: aws.String("SET pending = :pending, done = :done, succeed = :succeed, last_downloaded_at = :lastExaminedAt, expires_at = :expiresAt"),
-	})
-	return
+// IncrementSuccess counts one more archive as downloaded. The counters are added atomically,
+// so the workers downloading the archives of the same download do not lose each other's updates.
+func (table DownloadsTable) IncrementSuccess(ctx context.Context, downloadId string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	return table.increment(ctx, downloadId, "succeed", now, expiresIn)
+}
+
+// IncrementFailure counts one more archive as failed, see IncrementSuccess.
+func (table DownloadsTable) IncrementFailure(ctx context.Context, downloadId string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	return table.increment(ctx, downloadId, "failed", now, expiresIn)
 }
 
-func (table DownloadsTable) IncrementFailure(currentDownload DownloadRecord, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
-	pending := currentDownload.Pending - 1
-	done := currentDownload.Done + 1
-	faild := currentDownload.Failed + 1
+// increment fails with ErrDownloadAlreadyDone instead of taking done beyond total, it also fails so if the download does not exist.
+func (table DownloadsTable) increment(ctx context.Context, downloadId string, outcome string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
 	expiresAt := now.ToTime().Add(expiresIn).Unix()
-	_, err = table.DynamodbClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err = table.DynamodbClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"download_id": {
-				S: aws.String(currentDownload.DownloadId.String()),
+				S: aws.String(downloadId),
 			},
 		},
+		ExpressionAttributeNames: map[string]*string{
+			"#outcome": aws.String(outcome),
+		},
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":pending": {
-				N: aws.String(strconv.Itoa(pending)),
-			},
-			":done": {
-				N: aws.String(strconv.Itoa(done)),
+			":one": {
+				N: aws.String("1"),
 			},
-			":failed": {
-				N: aws.String(strconv.Itoa(faild)),
+			":minusOne": {
+				N: aws.String("-1"),
 			},
 			":lastExaminedAt": {
 				S: aws.String(now.String()),
@@ -119,7 +110,11 @@ func (table DownloadsTable) IncrementFailure(currentDownload DownloadRecord, now
 				N: aws.String(strconv.FormatInt(expiresAt, 10)),
 			},
 		},
-		UpdateExpression: aws.String("SET pending = :pending, done = :done, failed = :failed, last_downloaded_at = :lastExaminedAt, expires_at = :expiresAt"),
+		UpdateExpression:    aws.String("SET last_downloaded_at = :lastExaminedAt, expires_at = :expiresAt ADD done :one, #outcome :one, pending :minusOne"),
+		ConditionExpression: aws.String("done < total"),
 	})
+	if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		err = fmt.Errorf("%w: %s", ErrDownloadAlreadyDone, downloadId)
+	}
 	return
 }
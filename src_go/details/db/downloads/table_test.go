@@ -1,7 +1,9 @@
 package downloads
 
 import (
+	"context"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -39,7 +41,7 @@ func Test_DownloadTable_should_persist_download_record_into_the_table(t *testing
 		ExpiresAt:        dynamodbattribute.UnixTime(expiresAt),
 	}
 
-	err = downloadsTable.PutDownloadRecord(download)
+	err = downloadsTable.PutDownloadRecord(context.Background(), download)
 
 	assert.NoError(t, err)
 
@@ -140,7 +142,7 @@ func Test_DownloadTable_should_get_download_record_from_the_table(t *testing.T)
 
 	assert.NoError(t, err)
 
-	actualDownload, err := downloadsTable.GetDownloadRecord(downloadId.String())
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId.String())
 	assert.NoError(t, err)
 	assert.NotNil(t, actualDownload)
 
@@ -161,7 +163,7 @@ func Test_DownloadTable_should_return_nil_if_the_download_is_not_present_in_the_
 
 	downloadId := uuid.New().String()
 
-	actualDownload, err := downloadsTable.GetDownloadRecord(downloadId)
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
 	assert.NoError(t, err)
 	assert.Nil(t, actualDownload)
 }
@@ -208,10 +210,10 @@ func Test_DownloadTable_should_increment_success(t *testing.T) {
 	newDone := done + 1
 	newPending := pending - 1
 
-	err = downloadsTable.IncrementSuccess(download, newLastDownloadedAt, expiresIn)
+	err = downloadsTable.IncrementSuccess(context.Background(), downloadId.String(), newLastDownloadedAt, expiresIn)
 	assert.NoError(t, err)
 
-	actualDownload, err := downloadsTable.GetDownloadRecord(downloadId.String())
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId.String())
 	assert.NoError(t, err)
 
 	assert.Equal(t, newLastDownloadedAt, actualDownload.LastDownloadedAt)
@@ -263,10 +265,10 @@ func Test_DownloadTable_should_increment_failure(t *testing.T) {
 	newDone := done + 1
 	newPending := pending - 1
 
-	err = downloadsTable.IncrementFailure(download, newLastDownloadedAt, expiresIn)
+	err = downloadsTable.IncrementFailure(context.Background(), downloadId.String(), newLastDownloadedAt, expiresIn)
 	assert.NoError(t, err)
 
-	actualDownload, err := downloadsTable.GetDownloadRecord(downloadId.String())
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId.String())
 	assert.NoError(t, err)
 
 	assert.Equal(t, newLastDownloadedAt, actualDownload.LastDownloadedAt)
@@ -275,3 +277,57 @@ func Test_DownloadTable_should_increment_failure(t *testing.T) {
 	assert.Equal(t, newPending, actualDownload.Pending)
 	assert.Equal(t, time.Time(newExpiresAt).UTC(), time.Time(actualDownload.ExpiresAt).UTC())
 }
+
+func Test_DownloadTable_should_not_lose_any_of_the_parallel_increments_nor_exceed_the_total(t *testing.T) {
+	expiresIn := 24 * time.Hour
+	total := 40
+	workers := 60
+
+	downloadId := NewDownloadId(uuid.New().String())
+	startAt := time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC)
+	err := downloadsTable.PutDownloadRecord(context.Background(), NewDownloadRecord(downloadId, total, startAt, expiresIn))
+	assert.NoError(t, err)
+
+	now := db.Zuludatetime(startAt.Add(time.Minute))
+	errs := make([]error, workers)
+	wait := sync.WaitGroup{}
+	for worker := 0; worker < workers; worker++ {
+		wait.Add(1)
+		go func(worker int) {
+			defer wait.Done()
+			if worker%3 == 0 {
+				errs[worker] = downloadsTable.IncrementFailure(context.Background(), downloadId.String(), now, expiresIn)
+			} else {
+				errs[worker] = downloadsTable.IncrementSuccess(context.Background(), downloadId.String(), now, expiresIn)
+			}
+		}(worker)
+	}
+	wait.Wait()
+
+	rejected := 0
+	for _, err := range errs {
+		if err != nil {
+			assert.ErrorIs(t, err, ErrDownloadAlreadyDone)
+			rejected++
+		}
+	}
+	assert.Equal(t, workers-total, rejected)
+
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId.String())
+	assert.NoError(t, err)
+	assert.Equal(t, total, actualDownload.Done)
+	assert.Equal(t, 0, actualDownload.Pending)
+	assert.Equal(t, total, actualDownload.Succeed+actualDownload.Failed)
+	assert.Equal(t, now, actualDownload.LastDownloadedAt)
+}
+
+func Test_DownloadTable_should_not_increment_a_download_that_does_not_exist(t *testing.T) {
+	downloadId := NewDownloadId(uuid.New().String())
+
+	err := downloadsTable.IncrementSuccess(context.Background(), downloadId.String(), db.Zuludatetime(time.Now()), time.Hour)
+	assert.ErrorIs(t, err, ErrDownloadAlreadyDone)
+
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId.String())
+	assert.NoError(t, err)
+	assert.Nil(t, actualDownload)
+}
@@ -1,19 +1,26 @@
 package games
 
 import (
+	"context"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 )
 
+type LatestGameRepository interface {
+	QueryByEndTimestamp(ctx context.Context, archiveId string) (*GameRecord, error)
+}
+
+var _ LatestGameRepository = LatestGameIndex{}
+
 type LatestGameIndex struct {
 	Name           string
 	TableName      string
 	DynamodbClient *dynamodb.DynamoDB
 }
 
-func (index LatestGameIndex) QueryByEndTimestamp(archiveId string) (game *GameRecord, err error) {
-	items, err := index.DynamodbClient.Query(&dynamodb.QueryInput{
+func (index LatestGameIndex) QueryByEndTimestamp(ctx context.Context, archiveId string) (game *GameRecord, err error) {
+	items, err := index.DynamodbClient.QueryWithContext(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(index.TableName),
 		IndexName:              aws.String(index.Name),
 		KeyConditionExpression: aws.String("archive_id = :archive_id"),
@@ -1,6 +1,7 @@
 package games
 
 import (
+	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -50,7 +51,7 @@ func Test_LatestGameIndex_should_get_the_latest_downloaded_game_for_the_given_ar
 	})
 	assert.NoError(t, err)
 
-	latestGame, err := latestGameIndex.QueryByEndTimestamp(archiveId)
+	latestGame, err := latestGameIndex.QueryByEndTimestamp(context.Background(), archiveId)
 	assert.NoError(t, err)
 	assert.NotNil(t, latestGame)
 
@@ -63,7 +64,7 @@ func Test_LatestGameIndex_should_return_nil_if_there_is_no_game_for_the_given_ar
 	var err error
 	archiveId := uuid.New().String()
 
-	latestGame, err := latestGameIndex.QueryByEndTimestamp(archiveId)
+	latestGame, err := latestGameIndex.QueryByEndTimestamp(context.Background(), archiveId)
 	assert.NoError(t, err)
 	assert.Nil(t, latestGame)
 
@@ -0,0 +1,89 @@
+package games
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+const (
+	White = "white"
+	Black = "black"
+)
+
+const (
+	Win  = "win"
+	Draw = "draw"
+	Loss = "loss"
+)
+
+// GameFilter narrows the games of a user down by their metadata, a zero field is not filtered.
+// EndedFrom and EndedTo are inclusive unix timestamps. Color and Result are seen from the user's side,
+// Opponent is the lower cased username of the opponent.
+// Games downloaded before the metadata was captured never pass a non empty filter.
+type GameFilter struct {
+	EndedFrom int64
+	EndedTo   int64
+	Color     string
+	Result    string
+	TimeClass string
+	Opponent  string
+}
+
+func (filter GameFilter) IsEmpty() bool {
+	return filter == GameFilter{}
+}
+
+// matches is the in-memory counterpart of filterExpression.
+func (filter GameFilter) matches(game GameRecord) bool {
+	return (filter.EndedFrom == 0 || game.EndTimestamp >= filter.EndedFrom) &&
+		(filter.EndedTo == 0 || game.EndTimestamp <= filter.EndedTo) &&
+		(filter.Color == "" || game.UserColor == filter.Color) &&
+		(filter.Result == "" || game.UserResult == filter.Result) &&
+		(filter.TimeClass == "" || game.TimeClass == filter.TimeClass) &&
+		(filter.Opponent == "" || game.Opponent == filter.Opponent)
+}
+
+func (filter GameFilter) filterExpression() (
+	expression *string,
+	names map[string]*string,
+	values map[string]*dynamodb.AttributeValue,
+) {
+	if filter.IsEmpty() {
+		return
+	}
+	conditions := []string{}
+	names = map[string]*string{}
+	values = map[string]*dynamodb.AttributeValue{}
+
+	compare := func(attribute string, operator string, value *dynamodb.AttributeValue) {
+		names["#"+attribute] = aws.String(attribute)
+		placeholder := ":" + attribute + strconv.Itoa(len(values))
+		values[placeholder] = value
+		conditions = append(conditions, "#"+attribute+" "+operator+" "+placeholder)
+	}
+
+	if filter.EndedFrom != 0 {
+		compare("end_timestamp", ">=", &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(filter.EndedFrom, 10))})
+	}
+	if filter.EndedTo != 0 {
+		compare("end_timestamp", "<=", &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(filter.EndedTo, 10))})
+	}
+	if filter.Color != "" {
+		compare("user_color", "=", &dynamodb.AttributeValue{S: aws.String(filter.Color)})
+	}
+	if filter.Result != "" {
+		compare("user_result", "=", &dynamodb.AttributeValue{S: aws.String(filter.Result)})
+	}
+	if filter.TimeClass != "" {
+		compare("time_class", "=", &dynamodb.AttributeValue{S: aws.String(filter.TimeClass)})
+	}
+	if filter.Opponent != "" {
+		compare("opponent", "=", &dynamodb.AttributeValue{S: aws.String(filter.Opponent)})
+	}
+
+	expression = aws.String(strings.Join(conditions, " AND "))
+	return
+}
@@ -0,0 +1,46 @@
+package games
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GameFilter_should_not_produce_an_expression_when_empty(t *testing.T) {
+	expression, names, values := GameFilter{}.filterExpression()
+	assert.Nil(t, expression)
+	assert.Nil(t, names)
+	assert.Nil(t, values)
+}
+
+func Test_GameFilter_should_combine_every_field_into_one_expression(t *testing.T) {
+	filter := GameFilter{
+		EndedFrom: 1659429921,
+		EndedTo:   1659431044,
+		Color:     Black,
+		Result:    Win,
+		TimeClass: "blitz",
+		Opponent:  "n-60",
+	}
+
+	expression, names, values := filter.filterExpression()
+
+	assert.Equal(t, "#end_timestamp >= :end_timestamp0 AND #end_timestamp <= :end_timestamp1 AND #user_color = :user_color2 AND #user_result = :user_result3 AND #time_class = :time_class4 AND #opponent = :opponent5", *expression)
+	assert.Equal(t, map[string]*string{
+		"#end_timestamp": aws.String("end_timestamp"),
+		"#user_color":    aws.String("user_color"),
+		"#user_result":   aws.String("user_result"),
+		"#time_class":    aws.String("time_class"),
+		"#opponent":      aws.String("opponent"),
+	}, names)
+	assert.Equal(t, map[string]*dynamodb.AttributeValue{
+		":end_timestamp0": {N: aws.String("1659429921")},
+		":end_timestamp1": {N: aws.String("1659431044")},
+		":user_color2":    {S: aws.String("black")},
+		":user_result3":   {S: aws.String("win")},
+		":time_class4":    {S: aws.String("blitz")},
+		":opponent5":      {S: aws.String("n-60")},
+	}, values)
+}
@@ -7,8 +7,27 @@ type GameRecord struct {
 	ArchiveId    string `dynamodbav:"archive_id"`
 	GameId       string `dynamodbav:"game_id"`
 	Resource     string `dynamodbav:"resource"`
-	Pgn          string `dynamodbav:"pgn"`
+	Pgn          string `dynamodbav:"pgn,omitempty"`
 	EndTimestamp int64  `dynamodbav:"end_timestamp"`
+	White        string `dynamodbav:"white,omitempty"`
+	WhiteRating  int    `dynamodbav:"white_rating,omitempty"`
+	WhiteResult  string `dynamodbav:"white_result,omitempty"`
+	Black        string `dynamodbav:"black,omitempty"`
+	BlackRating  int    `dynamodbav:"black_rating,omitempty"`
+	BlackResult  string `dynamodbav:"black_result,omitempty"`
+	Result       string `dynamodbav:"result,omitempty"`
+	TimeClass    string `dynamodbav:"time_class,omitempty"`
+	TimeControl  string `dynamodbav:"time_control,omitempty"`
+	Rated        bool   `dynamodbav:"rated,omitempty"`
+	Eco          string `dynamodbav:"eco,omitempty"`
+	Rules        string `dynamodbav:"rules,omitempty"`
+	UserColor    string `dynamodbav:"user_color,omitempty"`
+	UserResult   string `dynamodbav:"user_result,omitempty"`
+	Opponent     string `dynamodbav:"opponent,omitempty"`
+	// Moves is the mainline encoded by chess.EncodeMoves. A game stored in this compact form has no Pgn.
+	Moves []byte `dynamodbav:"moves,omitempty"`
+	// PgnBlob is the key of the pgn offloaded to the blob store because it does not fit into the item.
+	PgnBlob string `dynamodbav:"pgn_blob,omitempty"`
 }
 
 func (game GameRecord) String() string {
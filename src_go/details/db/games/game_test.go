@@ -84,3 +84,89 @@ func Test_GameRecord_should_be_stored_in_correct_form(t *testing.T) {
 
 	assert.Equal(t, expectedGame, actualGame)
 }
+
+func Test_GameRecord_should_store_the_metadata_of_the_game_as_typed_attributes(t *testing.T) {
+	game := GameRecord{
+		UserId:       "user",
+		ArchiveId:    "archive",
+		GameId:       "https://www.chess.com/game/live/53168947271",
+		Resource:     "https://www.chess.com/game/live/53168947271",
+		Pgn:          "1. d4 b5 1-0",
+		EndTimestamp: 1659429921,
+		White:        "tigran-c-137",
+		WhiteRating:  997,
+		WhiteResult:  "win",
+		Black:        "akashvishwakarma000",
+		BlackRating:  917,
+		BlackResult:  "abandoned",
+		Result:       "1-0",
+		TimeClass:    "blitz",
+		TimeControl:  "300",
+		Rated:        true,
+		Eco:          "A40",
+		Rules:        "chess",
+	}
+
+	actualMarshalledItems, err := dynamodbattribute.MarshalMap(game)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("tigran-c-137")}, actualMarshalledItems["white"])
+	assert.Equal(t, &dynamodb.AttributeValue{N: aws.String("997")}, actualMarshalledItems["white_rating"])
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("abandoned")}, actualMarshalledItems["black_result"])
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("1-0")}, actualMarshalledItems["result"])
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("blitz")}, actualMarshalledItems["time_class"])
+	assert.Equal(t, &dynamodb.AttributeValue{BOOL: aws.Bool(true)}, actualMarshalledItems["rated"])
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("A40")}, actualMarshalledItems["eco"])
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("chess")}, actualMarshalledItems["rules"])
+
+	actualGame := GameRecord{}
+	err = dynamodbattribute.UnmarshalMap(actualMarshalledItems, &actualGame)
+	assert.NoError(t, err)
+	assert.Equal(t, game, actualGame)
+}
+
+func Test_GameRecord_without_metadata_should_stay_readable(t *testing.T) {
+	legacyItem := map[string]*dynamodb.AttributeValue{
+		"user_id":       {S: aws.String("user")},
+		"archive_id":    {S: aws.String("archive")},
+		"game_id":       {S: aws.String("game")},
+		"resource":      {S: aws.String("game")},
+		"pgn":           {S: aws.String("1. e4 e5 *")},
+		"end_timestamp": {N: aws.String("1696706773")},
+	}
+
+	actualGame := GameRecord{}
+	err := dynamodbattribute.UnmarshalMap(legacyItem, &actualGame)
+	assert.NoError(t, err)
+
+	expectedGame := GameRecord{
+		UserId:       "user",
+		ArchiveId:    "archive",
+		GameId:       "game",
+		Resource:     "game",
+		Pgn:          "1. e4 e5 *",
+		EndTimestamp: 1696706773,
+	}
+	assert.Equal(t, expectedGame, actualGame)
+}
+
+func Test_GameRecord_should_store_the_encoded_moves_instead_of_the_pgn(t *testing.T) {
+	game := GameRecord{
+		UserId:       "user",
+		ArchiveId:    "archive",
+		GameId:       "game",
+		Resource:     "game",
+		Moves:        []byte{0x07, 0x0c, 0x0d, 0x34},
+		EndTimestamp: 1696706773,
+	}
+
+	actualMarshalledItems, err := dynamodbattribute.MarshalMap(game)
+	assert.NoError(t, err)
+	assert.Equal(t, &dynamodb.AttributeValue{B: []byte{0x07, 0x0c, 0x0d, 0x34}}, actualMarshalledItems["moves"])
+	assert.NotContains(t, actualMarshalledItems, "pgn")
+
+	actualGame := GameRecord{}
+	err = dynamodbattribute.UnmarshalMap(actualMarshalledItems, &actualGame)
+	assert.NoError(t, err)
+	assert.Equal(t, game, actualGame)
+}
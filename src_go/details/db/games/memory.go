@@ -0,0 +1,116 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ GamesRepository = &InMemoryGamesTable{}
+var _ LatestGameRepository = &InMemoryGamesTable{}
+
+// InMemoryGamesTable behaves as GamesTable and LatestGameIndex do. As in DynamoDB the games of a user are sorted by their id,
+// a page examines limit games before filtering them and the last key is returned whenever the limit is reached.
+// It has no item size limit, so the pgns are never offloaded.
+type InMemoryGamesTable struct {
+	mutex   sync.Mutex
+	records map[string]map[string]GameRecord
+}
+
+func NewInMemoryGamesTable() *InMemoryGamesTable {
+	return &InMemoryGamesTable{records: map[string]map[string]GameRecord{}}
+}
+
+func (table *InMemoryGamesTable) PutGameRecords(ctx context.Context, gameRecords []GameRecord) (err error) {
+	storedRecords := make([]GameRecord, len(gameRecords))
+	for i, gameRecord := range gameRecords {
+		storedRecords[i], err = db.RoundTrip(gameRecord)
+		if err != nil {
+			return
+		}
+	}
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	for _, stored := range storedRecords {
+		if table.records[stored.UserId] == nil {
+			table.records[stored.UserId] = map[string]GameRecord{}
+		}
+		table.records[stored.UserId][stored.GameId] = stored
+	}
+	return
+}
+
+func (table *InMemoryGamesTable) QueryGames(
+	ctx context.Context,
+	userId string,
+	filter GameFilter,
+	lastKey PageKey,
+	limit int64,
+) (
+	games []GameRecord,
+	nextKey PageKey,
+	err error,
+) {
+	if limit <= 0 {
+		err = errors.New("the limit must be positive")
+		return
+	}
+
+	evaluated := table.gamesOf(userId)
+	if lastGameId := lastKey["game_id"]; lastGameId != "" {
+		start := sort.Search(len(evaluated), func(i int) bool { return evaluated[i].GameId > lastGameId })
+		evaluated = evaluated[start:]
+	}
+	if int64(len(evaluated)) >= limit {
+		evaluated = evaluated[:limit]
+		nextKey = pageKeyOf(evaluated[len(evaluated)-1])
+	}
+
+	for _, game := range evaluated {
+		if filter.matches(game) {
+			games = append(games, game)
+		}
+	}
+	return
+}
+
+func (table *InMemoryGamesTable) CountGames(ctx context.Context, userId string, filter GameFilter) (count int, err error) {
+	for _, game := range table.gamesOf(userId) {
+		if filter.matches(game) {
+			count++
+		}
+	}
+	return
+}
+
+func (table *InMemoryGamesTable) QueryByEndTimestamp(ctx context.Context, archiveId string) (game *GameRecord, err error) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	for _, gamesOfUser := range table.records {
+		for _, stored := range gamesOfUser {
+			if stored.ArchiveId != archiveId {
+				continue
+			}
+			if game == nil || stored.EndTimestamp > game.EndTimestamp {
+				latest := stored
+				game = &latest
+			}
+		}
+	}
+	return
+}
+
+func (table *InMemoryGamesTable) gamesOf(userId string) (games []GameRecord) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	for _, stored := range table.records[userId] {
+		games = append(games, stored)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].GameId < games[j].GameId
+	})
+	return
+}
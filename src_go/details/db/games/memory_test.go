@@ -0,0 +1,63 @@
+package games
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InMemoryGamesTable_should_query_the_games_page_by_page_before_filtering_them(t *testing.T) {
+	table := NewInMemoryGamesTable()
+	userId := "https://api.chess.com/pub/player/tigran-c-137"
+	archiveId := "https://api.chess.com/pub/player/tigran-c-137/games/2022/08"
+
+	newGame := func(gameId string, endTimestamp int64, color string) GameRecord {
+		return GameRecord{
+			UserId:       userId,
+			ArchiveId:    archiveId,
+			GameId:       gameId,
+			Resource:     gameId,
+			Pgn:          "1. e4 e5 *",
+			EndTimestamp: endTimestamp,
+			UserColor:    color,
+		}
+	}
+	game1 := newGame("https://www.chess.com/game/live/1", 1659430140, White)
+	game2 := newGame("https://www.chess.com/game/live/2", 1659429921, Black)
+	game3 := newGame("https://www.chess.com/game/live/3", 1659430643, White)
+	otherUsersGame := GameRecord{
+		UserId:       "https://api.chess.com/pub/player/n-60",
+		ArchiveId:    "https://api.chess.com/pub/player/n-60/games/2022/08",
+		GameId:       "https://www.chess.com/game/live/4",
+		Resource:     "https://www.chess.com/game/live/4",
+		EndTimestamp: 1659431044,
+	}
+
+	err := table.PutGameRecords(context.Background(), []GameRecord{game3, game1, otherUsersGame, game2})
+	assert.NoError(t, err)
+
+	var noKey PageKey
+	firstPage, firstKey, err := table.QueryGames(context.Background(), userId, GameFilter{Color: White}, noKey, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []GameRecord{game1}, firstPage)
+	assert.Equal(t, "https://www.chess.com/game/live/2", firstKey["game_id"])
+	assert.Equal(t, userId, firstKey["user_id"])
+
+	secondPage, secondKey, err := table.QueryGames(context.Background(), userId, GameFilter{Color: White}, firstKey, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []GameRecord{game3}, secondPage)
+	assert.Nil(t, secondKey)
+
+	count, err := table.CountGames(context.Background(), userId, GameFilter{EndedFrom: 1659430140})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	latestGame, err := table.QueryByEndTimestamp(context.Background(), archiveId)
+	assert.NoError(t, err)
+	assert.Equal(t, &game3, latestGame)
+
+	latestGame, err = table.QueryByEndTimestamp(context.Background(), "https://api.chess.com/pub/player/tigran-c-137/games/2022/09")
+	assert.NoError(t, err)
+	assert.Nil(t, latestGame)
+}
@@ -0,0 +1,149 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ GamesRepository = SqlGamesTable{}
+var _ LatestGameRepository = SqlGamesTable{}
+
+// SqlGamesTable behaves as GamesTable and LatestGameIndex do. The games of a user are walked in the order of their ids
+// with the same page keys as in DynamoDB, but the filter is applied before the limit, so a page is never emptied by the filter.
+// There is no item size limit, so the pgns are never offloaded.
+type SqlGamesTable struct {
+	Database *db.SqlDatabase
+}
+
+var gameColumns = []string{
+	"user_id", "game_id", "archive_id", "resource", "pgn", "end_timestamp",
+	"white", "white_rating", "white_result", "black", "black_rating", "black_result",
+	"result", "time_class", "time_control", "rated", "eco", "rules",
+	"user_color", "user_result", "opponent", "moves",
+}
+
+var selectGames = "SELECT " + strings.Join(gameColumns, ", ") + " FROM games"
+
+func (table SqlGamesTable) PutGameRecords(ctx context.Context, gameRecords []GameRecord) (err error) {
+	argLists := make([][]any, len(gameRecords))
+	for i := range gameRecords {
+		argLists[i] = gameFields(&gameRecords[i])
+	}
+	return table.Database.InTransaction(ctx, db.Upsert("games", gameColumns[:2], gameColumns), argLists)
+}
+
+func (table SqlGamesTable) QueryGames(
+	ctx context.Context,
+	userId string,
+	filter GameFilter,
+	lastKey PageKey,
+	limit int64,
+) (
+	games []GameRecord,
+	nextKey PageKey,
+	err error,
+) {
+	if limit <= 0 {
+		err = errors.New("the limit must be positive")
+		return
+	}
+
+	conditions, args := filter.sqlConditions()
+	conditions = append([]string{"user_id = ?"}, conditions...)
+	args = append([]any{userId}, args...)
+	if lastKey["game_id"] != "" {
+		conditions = append(conditions, "game_id > ?")
+		args = append(args, lastKey["game_id"])
+	}
+	args = append(args, limit)
+
+	games, err = table.query(ctx, selectGames+" WHERE "+strings.Join(conditions, " AND ")+" ORDER BY game_id LIMIT ?", args...)
+	if err != nil {
+		return
+	}
+
+	if int64(len(games)) == limit {
+		nextKey = pageKeyOf(games[len(games)-1])
+	}
+	return
+}
+
+func (table SqlGamesTable) CountGames(ctx context.Context, userId string, filter GameFilter) (count int, err error) {
+	conditions, args := filter.sqlConditions()
+	conditions = append([]string{"user_id = ?"}, conditions...)
+	args = append([]any{userId}, args...)
+	err = table.Database.QueryRow(ctx, "SELECT COUNT(*) FROM games WHERE "+strings.Join(conditions, " AND "), args...).Scan(&count)
+	return
+}
+
+func (table SqlGamesTable) QueryByEndTimestamp(ctx context.Context, archiveId string) (game *GameRecord, err error) {
+	games, err := table.query(ctx, selectGames+" WHERE archive_id = ? ORDER BY end_timestamp DESC LIMIT 1", archiveId)
+	if err != nil {
+		return
+	}
+	if len(games) == 0 {
+		return
+	}
+	game = &games[0]
+	return
+}
+
+func (table SqlGamesTable) query(ctx context.Context, query string, args ...any) (games []GameRecord, err error) {
+	rows, err := table.Database.Query(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		game := GameRecord{}
+		err = rows.Scan(gameFields(&game)...)
+		if err != nil {
+			return
+		}
+		games = append(games, game)
+	}
+	err = rows.Err()
+	return
+}
+
+// sqlConditions is the sql counterpart of filterExpression.
+func (filter GameFilter) sqlConditions() (conditions []string, args []any) {
+	compare := func(column string, operator string, value any) {
+		conditions = append(conditions, column+" "+operator+" ?")
+		args = append(args, value)
+	}
+
+	if filter.EndedFrom != 0 {
+		compare("end_timestamp", ">=", filter.EndedFrom)
+	}
+	if filter.EndedTo != 0 {
+		compare("end_timestamp", "<=", filter.EndedTo)
+	}
+	if filter.Color != "" {
+		compare("user_color", "=", filter.Color)
+	}
+	if filter.Result != "" {
+		compare("user_result", "=", filter.Result)
+	}
+	if filter.TimeClass != "" {
+		compare("time_class", "=", filter.TimeClass)
+	}
+	if filter.Opponent != "" {
+		compare("opponent", "=", filter.Opponent)
+	}
+	return
+}
+
+// gameFields are the fields in the order of gameColumns.
+func gameFields(game *GameRecord) []any {
+	return []any{
+		&game.UserId, &game.GameId, &game.ArchiveId, &game.Resource, &game.Pgn, &game.EndTimestamp,
+		&game.White, &game.WhiteRating, &game.WhiteResult, &game.Black, &game.BlackRating, &game.BlackResult,
+		&game.Result, &game.TimeClass, &game.TimeControl, &game.Rated, &game.Eco, &game.Rules,
+		&game.UserColor, &game.UserResult, &game.Opponent, &game.Moves,
+	}
+}
@@ -0,0 +1,76 @@
+package games
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/sqltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SqlGamesTable_should_query_the_filtered_games_page_by_page(t *testing.T) {
+	table := SqlGamesTable{Database: sqltest.OpenDatabase(t)}
+	userId := "https://api.chess.com/pub/player/tigran-c-137"
+	archiveId := "https://api.chess.com/pub/player/tigran-c-137/games/2022/08"
+
+	newGame := func(gameId string, endTimestamp int64, color string) GameRecord {
+		return GameRecord{
+			UserId:       userId,
+			ArchiveId:    archiveId,
+			GameId:       gameId,
+			Resource:     gameId,
+			Pgn:          "1. e4 e5 *",
+			EndTimestamp: endTimestamp,
+			UserColor:    color,
+		}
+	}
+	game1 := newGame("https://www.chess.com/game/live/1", 1659430140, White)
+	game2 := newGame("https://www.chess.com/game/live/2", 1659429921, Black)
+	game3 := newGame("https://www.chess.com/game/live/3", 1659430643, White)
+	game3.Pgn = ""
+	game3.Moves = []byte{0x0c, 0x1c, 0x34, 0x24}
+	otherUsersGame := GameRecord{
+		UserId:       "https://api.chess.com/pub/player/n-60",
+		ArchiveId:    "https://api.chess.com/pub/player/n-60/games/2022/08",
+		GameId:       "https://www.chess.com/game/live/4",
+		Resource:     "https://www.chess.com/game/live/4",
+		EndTimestamp: 1659431044,
+	}
+
+	err := table.PutGameRecords(context.Background(), []GameRecord{game3, game1, otherUsersGame, game2})
+	assert.NoError(t, err)
+
+	var noKey PageKey
+	firstPage, firstKey, err := table.QueryGames(context.Background(), userId, GameFilter{}, noKey, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []GameRecord{game1, game2}, firstPage)
+	assert.Equal(t, "https://www.chess.com/game/live/2", firstKey["game_id"])
+	assert.Equal(t, userId, firstKey["user_id"])
+
+	secondPage, secondKey, err := table.QueryGames(context.Background(), userId, GameFilter{}, firstKey, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []GameRecord{game3}, secondPage)
+	assert.Nil(t, secondKey)
+
+	whitePage, whiteKey, err := table.QueryGames(context.Background(), userId, GameFilter{Color: White}, noKey, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []GameRecord{game1, game3}, whitePage)
+	assert.Equal(t, "https://www.chess.com/game/live/3", whiteKey["game_id"])
+
+	whitePage, whiteKey, err = table.QueryGames(context.Background(), userId, GameFilter{Color: White}, whiteKey, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, whitePage)
+	assert.Nil(t, whiteKey)
+
+	count, err := table.CountGames(context.Background(), userId, GameFilter{EndedFrom: 1659430140})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	latestGame, err := table.QueryByEndTimestamp(context.Background(), archiveId)
+	assert.NoError(t, err)
+	assert.Equal(t, &game3, latestGame)
+
+	latestGame, err = table.QueryByEndTimestamp(context.Background(), "https://api.chess.com/pub/player/tigran-c-137/games/2022/09")
+	assert.NoError(t, err)
+	assert.Nil(t, latestGame)
+}
@@ -1,6 +1,10 @@
 package games
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -8,19 +12,48 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/batcher"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/blobs"
 )
 
 const BatchReadAmount = 100
 
+// MaxInlinePgnSize leaves room for the other attributes within the 400 KB limit of a DynamoDB item.
+const MaxInlinePgnSize = 300 * 1024
+
+// PageKey is where QueryGames stopped, the next query starts after it. An empty key starts from the first game
+// and an empty next key means that there are no more games.
+type PageKey map[string]string
+
+// pageKeyOf is the key of the page that stopped at the game.
+func pageKeyOf(game GameRecord) PageKey {
+	return PageKey{"user_id": game.UserId, "game_id": game.GameId}
+}
+
+type GamesRepository interface {
+	PutGameRecords(ctx context.Context, gameRecords []GameRecord) error
+	QueryGames(ctx context.Context, userId string, filter GameFilter, lastKey PageKey, limit int64) ([]GameRecord, PageKey, error)
+	CountGames(ctx context.Context, userId string, filter GameFilter) (int, error)
+}
+
+var _ GamesRepository = GamesTable{}
+
 type GamesTable struct {
 	Name           string
 	DynamodbClient *dynamodb.DynamoDB
+	// PgnBlobs keeps the pgns larger than MaxInlinePgnSize. Without it every pgn is written inline.
+	PgnBlobs blobs.BlobStore
 }
 
-func (table GamesTable) PutGameRecords(gameRecords []GameRecord) (err error) {
+func (table GamesTable) PutGameRecords(ctx context.Context, gameRecords []GameRecord) (err error) {
 
 	gameRecordWriteRequests := make([]*dynamodb.WriteRequest, len(gameRecords))
 	for i, gameRecord := range gameRecords {
+		if table.PgnBlobs != nil && len(gameRecord.Pgn) > MaxInlinePgnSize {
+			gameRecord, err = table.offloadPgn(ctx, gameRecord)
+			if err != nil {
+				return
+			}
+		}
 		var missingGameRecordItems map[string]*dynamodb.AttributeValue
 		missingGameRecordItems, err = dynamodbattribute.MarshalMap(gameRecord)
 		if err != nil {
@@ -44,7 +77,7 @@ func (table GamesTable) PutGameRecords(gameRecords []GameRecord) (err error) {
 
 		for len(unprocessedWriteRequests) > 0 {
 			var writeOutput *dynamodb.BatchWriteItemOutput
-			writeOutput, err = table.DynamodbClient.BatchWriteItem(&dynamodb.BatchWriteItemInput{
+			writeOutput, err = table.DynamodbClient.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
 				RequestItems: unprocessedWriteRequests,
 			})
 
@@ -61,27 +94,22 @@ func (table GamesTable) PutGameRecords(gameRecords []GameRecord) (err error) {
 }
 
 func (table GamesTable) QueryGames(
+	ctx context.Context,
 	userId string,
-	lastKey map[string]*dynamodb.AttributeValue,
+	filter GameFilter,
+	lastKey PageKey,
 	limit int64,
 ) (
 	games []GameRecord,
-	nextKey map[string]*dynamodb.AttributeValue,
+	nextKey PageKey,
 	err error,
 ) {
 
 	var queryOutput *dynamodb.QueryOutput
-	queryOutput, err = table.DynamodbClient.Query(&dynamodb.QueryInput{
-		TableName:              &table.Name,
-		KeyConditionExpression: aws.String("user_id = :user_id"),
-		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":user_id": {
-				S: aws.String(userId),
-			},
-		},
-		Limit:             aws.Int64(limit),
-		ExclusiveStartKey: lastKey,
-	})
+	queryInput := table.queryByUserId(userId, filter)
+	queryInput.Limit = aws.Int64(limit)
+	queryInput.ExclusiveStartKey = lastKey.toDynamodb()
+	queryOutput, err = table.DynamodbClient.QueryWithContext(ctx, queryInput)
 
 	if err != nil {
 		return
@@ -90,6 +118,98 @@ func (table GamesTable) QueryGames(
 	if err != nil {
 		return
 	}
-	nextKey = queryOutput.LastEvaluatedKey
+	for i := range games {
+		if games[i].PgnBlob == "" {
+			continue
+		}
+		games[i], err = table.resolvePgn(ctx, games[i])
+		if err != nil {
+			return
+		}
+	}
+	nextKey = pageKeyFromDynamodb(queryOutput.LastEvaluatedKey)
+	return
+}
+
+// toDynamodb is the exclusive start key of the query, the attributes of the key of the games table are strings.
+func (key PageKey) toDynamodb() (item map[string]*dynamodb.AttributeValue) {
+	if len(key) == 0 {
+		return
+	}
+	item = make(map[string]*dynamodb.AttributeValue, len(key))
+	for name, value := range key {
+		item[name] = &dynamodb.AttributeValue{S: aws.String(value)}
+	}
 	return
 }
+
+func pageKeyFromDynamodb(item map[string]*dynamodb.AttributeValue) (key PageKey) {
+	if len(item) == 0 {
+		return
+	}
+	key = make(PageKey, len(item))
+	for name, value := range item {
+		key[name] = aws.StringValue(value.S)
+	}
+	return
+}
+
+func (table GamesTable) offloadPgn(ctx context.Context, gameRecord GameRecord) (offloaded GameRecord, err error) {
+	key := PgnBlobKey(gameRecord.UserId, gameRecord.GameId)
+	err = table.PgnBlobs.PutBlob(ctx, key, []byte(gameRecord.Pgn))
+	if err != nil {
+		return
+	}
+	offloaded = gameRecord
+	offloaded.Pgn = ""
+	offloaded.PgnBlob = key
+	return
+}
+
+func (table GamesTable) resolvePgn(ctx context.Context, gameRecord GameRecord) (resolved GameRecord, err error) {
+	if table.PgnBlobs == nil {
+		err = fmt.Errorf("the pgn of %s is offloaded to %s but there is no blob store", gameRecord.GameId, gameRecord.PgnBlob)
+		return
+	}
+	pgn, err := table.PgnBlobs.GetBlob(ctx, gameRecord.PgnBlob)
+	if err != nil {
+		return
+	}
+	resolved = gameRecord
+	resolved.Pgn = string(pgn)
+	return
+}
+
+// PgnBlobKey hashes the ids since the game id is an url.
+func PgnBlobKey(userId string, gameId string) string {
+	hash := sha256.Sum256([]byte(userId + "\n" + gameId))
+	return "pgn/" + hex.EncodeToString(hash[:])
+}
+
+// CountGames counts the games of the user that pass the filter.
+func (table GamesTable) CountGames(ctx context.Context, userId string, filter GameFilter) (count int, err error) {
+	queryInput := table.queryByUserId(userId, filter)
+	queryInput.Select = aws.String(dynamodb.SelectCount)
+	err = table.DynamodbClient.QueryPagesWithContext(ctx, queryInput, func(queryOutput *dynamodb.QueryOutput, lastPage bool) bool {
+		count += int(aws.Int64Value(queryOutput.Count))
+		return true
+	})
+	return
+}
+
+func (table GamesTable) queryByUserId(userId string, filter GameFilter) *dynamodb.QueryInput {
+	filterExpression, names, values := filter.filterExpression()
+	if values == nil {
+		values = map[string]*dynamodb.AttributeValue{}
+	}
+	values[":user_id"] = &dynamodb.AttributeValue{
+		S: aws.String(userId),
+	}
+	return &dynamodb.QueryInput{
+		TableName:                 &table.Name,
+		KeyConditionExpression:    aws.String("user_id = :user_id"),
+		FilterExpression:          filterExpression,
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+}
@@ -1,11 +1,19 @@
 package games
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/blobs"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -88,7 +96,7 @@ func Test_GamesTable_should_persist_a_lot_of_game_records_into_the_table(t *test
 		games[i] = game
 	}
 
-	err = gamesTable.PutGameRecords(games)
+	err = gamesTable.PutGameRecords(context.Background(), games)
 	assert.NoError(t, err)
 
 	var lastKey map[string]*dynamodb.AttributeValue
@@ -165,28 +173,177 @@ func Test_GamesTable_should_query_users_game_records_from_the_table_and_support_
 	})
 	assert.NoError(t, err)
 
-	var initialKey map[string]*dynamodb.AttributeValue
+	var initialKey PageKey
 
-	firstBatch, firstBatchKey, err := gamesTable.QueryGames(userId, initialKey, 1)
+	firstBatch, firstBatchKey, err := gamesTable.QueryGames(context.Background(), userId, GameFilter{}, initialKey, 1)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 1, len(firstBatch))
 	assert.Equal(t, newGame1, firstBatch[0])
-	assert.Equal(t, "https://www.chess.com/game/live/53169604577", *firstBatchKey["game_id"].S)
-	assert.Equal(t, userId, *firstBatchKey["user_id"].S)
+	assert.Equal(t, "https://www.chess.com/game/live/53169604577", firstBatchKey["game_id"])
+	assert.Equal(t, userId, firstBatchKey["user_id"])
 
-	secondBatch, secondBatchKey, err := gamesTable.QueryGames(userId, firstBatchKey, 1)
+	secondBatch, secondBatchKey, err := gamesTable.QueryGames(context.Background(), userId, GameFilter{}, firstBatchKey, 1)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 1, len(secondBatch))
 	assert.Equal(t, newGame2, secondBatch[0])
-	assert.Equal(t, "https://www.chess.com/game/live/53170160741", *secondBatchKey["game_id"].S)
-	assert.Equal(t, userId, *secondBatchKey["user_id"].S)
+	assert.Equal(t, "https://www.chess.com/game/live/53170160741", secondBatchKey["game_id"])
+	assert.Equal(t, userId, secondBatchKey["user_id"])
 
-	thirdBatch, thirdBatchKey, err := gamesTable.QueryGames(userId, secondBatchKey, 1)
+	thirdBatch, thirdBatchKey, err := gamesTable.QueryGames(context.Background(), userId, GameFilter{}, secondBatchKey, 1)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 0, len(thirdBatch))
 	assert.Nil(t, thirdBatchKey)
 
 }
+
+func Test_GamesTable_should_query_and_count_only_the_games_passing_the_filter(t *testing.T) {
+	userId := uuid.New().String()
+	archiveId := uuid.New().String()
+
+	newGame := func(gameId string, endTimestamp int64, color string, result string, timeClass string, opponent string) GameRecord {
+		return GameRecord{
+			UserId:       userId,
+			ArchiveId:    archiveId,
+			GameId:       gameId,
+			Resource:     gameId,
+			Pgn:          "1. e4 e5 *",
+			EndTimestamp: endTimestamp,
+			TimeClass:    timeClass,
+			UserColor:    color,
+			UserResult:   result,
+			Opponent:     opponent,
+		}
+	}
+
+	whiteBlitzWin := newGame("https://www.chess.com/game/live/1", 1659429921, White, Win, "blitz", "vincentveganin")
+	blackBlitzLoss := newGame("https://www.chess.com/game/live/2", 1659430140, Black, Loss, "blitz", "n-60")
+	whiteRapidDraw := newGame("https://www.chess.com/game/live/3", 1659430643, White, Draw, "rapid", "n-60")
+	legacyGame := GameRecord{
+		UserId:       userId,
+		ArchiveId:    archiveId,
+		GameId:       "https://www.chess.com/game/live/4",
+		Resource:     "https://www.chess.com/game/live/4",
+		Pgn:          "1. d4 d5 *",
+		EndTimestamp: 1659431044,
+	}
+
+	err := gamesTable.PutGameRecords(context.Background(), []GameRecord{whiteBlitzWin, blackBlitzLoss, whiteRapidDraw, legacyGame})
+	assert.NoError(t, err)
+
+	var noKey PageKey
+
+	actualGames, _, err := gamesTable.QueryGames(context.Background(), userId, GameFilter{Color: White}, noKey, 100)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []GameRecord{whiteBlitzWin, whiteRapidDraw}, actualGames)
+
+	actualGames, _, err = gamesTable.QueryGames(context.Background(), userId, GameFilter{TimeClass: "blitz", Opponent: "n-60"}, noKey, 100)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []GameRecord{blackBlitzLoss}, actualGames)
+
+	actualGames, _, err = gamesTable.QueryGames(context.Background(), userId, GameFilter{EndedFrom: 1659430140, EndedTo: 1659431044}, noKey, 100)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []GameRecord{blackBlitzLoss, whiteRapidDraw, legacyGame}, actualGames)
+
+	count, err := gamesTable.CountGames(context.Background(), userId, GameFilter{Result: Draw})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = gamesTable.CountGames(context.Background(), userId, GameFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
+
+func Test_GamesTable_should_offload_an_oversized_pgn_to_the_blob_store_and_resolve_it_on_query(t *testing.T) {
+	userId := uuid.New().String()
+	archiveId := uuid.New().String()
+	gamesTableWithBlobs := GamesTable{
+		Name:           gamesTableName,
+		DynamodbClient: dynamodbClient,
+		PgnBlobs:       blobs.DirectoryBlobStore{Root: t.TempDir()},
+	}
+
+	longGame := GameRecord{
+		UserId:       userId,
+		ArchiveId:    archiveId,
+		GameId:       "https://www.chess.com/game/daily/1",
+		Resource:     "https://www.chess.com/game/daily/1",
+		Pgn:          strings.Repeat("1. Nf3 {[%clk 71:59:59.9]} 1... Nf6 {[%clk 71:59:59.9]} 2. Ng1 {[%clk 71:59:59.9]} 2... Ng8 {[%clk 71:59:59.9]} ", 5000) + "1/2-1/2",
+		EndTimestamp: 1659431044,
+	}
+	shortGame := GameRecord{
+		UserId:       userId,
+		ArchiveId:    archiveId,
+		GameId:       "https://www.chess.com/game/daily/2",
+		Resource:     "https://www.chess.com/game/daily/2",
+		Pgn:          "1. e4 e5 *",
+		EndTimestamp: 1659431342,
+	}
+
+	err := gamesTableWithBlobs.PutGameRecords(context.Background(), []GameRecord{longGame, shortGame})
+	assert.NoError(t, err)
+
+	getGameOutput, err := dynamodbClient.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(gamesTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"user_id": {S: aws.String(userId)},
+			"game_id": {S: aws.String(longGame.GameId)},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, getGameOutput.Item["pgn"])
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String(PgnBlobKey(userId, longGame.GameId))}, getGameOutput.Item["pgn_blob"])
+
+	var noKey PageKey
+	actualGames, _, err := gamesTableWithBlobs.QueryGames(context.Background(), userId, GameFilter{}, noKey, 100)
+	assert.NoError(t, err)
+
+	expectedLongGame := longGame
+	expectedLongGame.PgnBlob = PgnBlobKey(userId, longGame.GameId)
+	assert.Equal(t, []GameRecord{expectedLongGame, shortGame}, actualGames)
+
+	_, _, err = gamesTable.QueryGames(context.Background(), userId, GameFilter{}, noKey, 100)
+	assert.Error(t, err, "an offloaded pgn cannot be resolved without the blob store")
+}
+
+func Test_PageKey_should_be_converted_to_the_key_of_the_games_table_and_back(t *testing.T) {
+	pageKey := PageKey{"user_id": "https://api.chess.com/pub/player/tigran-c-137", "game_id": "https://www.chess.com/game/live/53169604577"}
+
+	actualItem := pageKey.toDynamodb()
+	assert.Equal(t, map[string]*dynamodb.AttributeValue{
+		"user_id": {S: aws.String("https://api.chess.com/pub/player/tigran-c-137")},
+		"game_id": {S: aws.String("https://www.chess.com/game/live/53169604577")},
+	}, actualItem)
+	assert.Equal(t, pageKey, pageKeyFromDynamodb(actualItem))
+
+	var noKey PageKey
+	assert.Nil(t, noKey.toDynamodb())
+	assert.Nil(t, pageKeyFromDynamodb(map[string]*dynamodb.AttributeValue{}))
+}
+
+func Test_GamesTable_should_not_query_the_games_once_the_context_is_cancelled(t *testing.T) {
+	requested := false
+	dynamodbStandIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer dynamodbStandIn.Close()
+
+	standInTable := GamesTable{
+		Name: gamesTableName,
+		DynamodbClient: dynamodb.New(session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String("us-east-1"),
+			Endpoint:    aws.String(dynamodbStandIn.URL),
+			Credentials: credentials.NewStaticCredentials("test", "test", ""),
+		}))),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var noKey PageKey
+	_, _, err := standInTable.QueryGames(ctx, uuid.New().String(), GameFilter{}, noKey, 10)
+	assert.ErrorContains(t, err, request.CanceledErrorCode)
+	assert.False(t, requested)
+}
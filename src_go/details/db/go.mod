@@ -4,6 +4,8 @@ go 1.21.1
 
 require (
 	github.com/aws/aws-sdk-go v1.45.24
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/stretchr/testify v1.8.4
 )
 
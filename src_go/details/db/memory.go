@@ -0,0 +1,41 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+var ErrDuplicatesInStringSet = errors.New("string set contains duplicates")
+
+// RoundTrip marshals the record the way it is written into DynamoDB and reads it back,
+// so that the in-memory tables return what the DynamoDB tables would: empty fields are omitted,
+// empty string sets come back as nil and datetimes keep their milliseconds only.
+// Like DynamoDB it rejects a string set with duplicates.
+func RoundTrip[T any](record T) (stored T, err error) {
+	items, err := dynamodbattribute.MarshalMap(record)
+	if err != nil {
+		return
+	}
+	for name, item := range items {
+		err = checkStringSet(name, item)
+		if err != nil {
+			return
+		}
+	}
+	err = dynamodbattribute.UnmarshalMap(items, &stored)
+	return
+}
+
+func checkStringSet(name string, item *dynamodb.AttributeValue) error {
+	seen := make(map[string]bool, len(item.SS))
+	for _, value := range item.SS {
+		if seen[*value] {
+			return fmt.Errorf("%w: %s has %s twice", ErrDuplicatesInStringSet, name, *value)
+		}
+		seen[*value] = true
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type setRecord struct {
+	Id        string       `dynamodbav:"id"`
+	Note      string       `dynamodbav:"note,omitempty"`
+	Resources []string     `dynamodbav:"resources,stringset"`
+	At        ZuluDateTime `dynamodbav:"at"`
+}
+
+func Test_RoundTrip_should_return_the_record_as_DynamoDB_would(t *testing.T) {
+	record := setRecord{
+		Id:        "id",
+		Resources: []string{},
+		At:        Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123456789, time.UTC)),
+	}
+
+	actualRecord, err := RoundTrip(record)
+	assert.NoError(t, err)
+	assert.Equal(t, setRecord{
+		Id:        "id",
+		Resources: nil,
+		At:        Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC)),
+	}, actualRecord)
+}
+
+func Test_RoundTrip_should_reject_duplicates_in_a_string_set(t *testing.T) {
+	_, err := RoundTrip(setRecord{Id: "id", Resources: []string{"a", "b", "a"}})
+	assert.ErrorIs(t, err, ErrDuplicatesInStringSet)
+}
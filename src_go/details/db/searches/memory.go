@@ -0,0 +1,88 @@
+package searches
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ SearchesRepository = &InMemorySearchesTable{}
+
+// InMemorySearchesTable behaves as SearchesTable does, including the updates creating a missing search.
+// It keeps the expired records until ExpireRecords is called, as DynamoDB deletes them some time later.
+type InMemorySearchesTable struct {
+	mutex   sync.Mutex
+	records map[string]SearchRecord
+}
+
+func NewInMemorySearchesTable() *InMemorySearchesTable {
+	return &InMemorySearchesTable{records: map[string]SearchRecord{}}
+}
+
+func (table *InMemorySearchesTable) PutSearchRecord(ctx context.Context, searchRecord SearchRecord) (err error) {
+	stored, err := db.RoundTrip(searchRecord)
+	if err != nil {
+		return
+	}
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.records[searchRecord.SearchId.String()] = stored
+	return
+}
+
+func (table *InMemorySearchesTable) GetSearchRecord(ctx context.Context, searchId string) (searchRecord *SearchRecord, err error) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	stored, exists := table.records[searchId]
+	if !exists {
+		return
+	}
+	searchRecord = &stored
+	return
+}
+
+func (table *InMemorySearchesTable) UpdateMatchings(ctx context.Context, searchId string, examined int, matched []Match, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	return table.update(searchId, func(stored *SearchRecord) {
+		stored.Examined = examined
+		stored.LastExaminedAt = now
+		stored.Matched = Resources(matched)
+		stored.Matches = matched
+		stored.ExpiresAt = dynamodbattribute.UnixTime(now.ToTime().Add(expiresIn))
+	})
+}
+
+func (table *InMemorySearchesTable) UpdateStatus(ctx context.Context, searchId string, status SearchStatus) (err error) {
+	return table.update(searchId, func(stored *SearchRecord) {
+		stored.Status = status
+	})
+}
+
+func (table *InMemorySearchesTable) update(searchId string, change func(stored *SearchRecord)) (err error) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	stored, exists := table.records[searchId]
+	if !exists {
+		stored = SearchRecord{SearchId: SearchId{value: searchId}}
+	}
+	change(&stored)
+	stored, err = db.RoundTrip(stored)
+	if err != nil {
+		return
+	}
+	table.records[searchId] = stored
+	return
+}
+
+// ExpireRecords deletes the records whose time to live is over.
+func (table *InMemorySearchesTable) ExpireRecords(now time.Time) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	for searchId, stored := range table.records {
+		if time.Time(stored.ExpiresAt).Before(now) {
+			delete(table.records, searchId)
+		}
+	}
+}
@@ -3,12 +3,15 @@ package searches
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
 )
 
 type SearchRecord struct {
@@ -18,10 +21,35 @@ type SearchRecord struct {
 	Examined       int                        `dynamodbav:"examined"`
 	Total          int                        `dynamodbav:"total"`
 	Matched        []string                   `dynamodbav:"matched,stringset"`
+	Matches        []Match                    `dynamodbav:"matches,omitempty"`
 	Status         SearchStatus               `dynamodbav:"status"`
 	ExpiresAt      dynamodbattribute.UnixTime `dynamodbav:"expires_at"`
 }
 
+// Match tells where the board was found in a game. Records created before locations were tracked
+// have only the string set of resources in Matched, and matches without a location come from such searches.
+// A sequence search has the location of every step in Steps, the first one being the Location.
+type Match struct {
+	Resource string          `dynamodbav:"resource"`
+	Location *MatchLocation  `dynamodbav:"location"`
+	Steps    []MatchLocation `dynamodbav:"steps,omitempty"`
+}
+
+type MatchLocation struct {
+	Ply        int    `dynamodbav:"ply"`
+	MoveNumber int    `dynamodbav:"move_number"`
+	SideToMove string `dynamodbav:"side_to_move"`
+	Fen        string `dynamodbav:"fen"`
+}
+
+func Resources(matches []Match) []string {
+	resources := make([]string, len(matches))
+	for i, match := range matches {
+		resources[i] = match.Resource
+	}
+	return resources
+}
+
 type SearchStatus string
 
 const (
@@ -50,13 +78,37 @@ type SearchId struct {
 
 func (id SearchId) String() string { return id.value }
 
-func NewSearchId(userId string, downloadStartedAt *db.ZuluDateTime, board string) SearchId {
+// BoardConstraints are the optional side to move, castling rights and en passant square of a search.
+// An empty field is not constrained.
+type BoardConstraints struct {
+	SideToMove string
+	Castling   string
+	EnPassant  string
+}
+
+func (constraints BoardConstraints) IsEmpty() bool {
+	return constraints == BoardConstraints{}
+}
+
+// SequenceBoard stands for the board of a sequence search wherever a single board is expected, e.g. in NewSearchId.
+func SequenceBoard(boards []string, maxPlyGap int) string {
+	return strings.Join(boards, ">") + "~" + strconv.Itoa(maxPlyGap)
+}
+
+func NewSearchId(userId string, downloadStartedAt *db.ZuluDateTime, board string, constraints BoardConstraints, filter games.GameFilter) SearchId {
 	var data string
 	if downloadStartedAt == nil {
 		data = userId + "#_#" + board
 	} else {
 		data = userId + "#" + downloadStartedAt.String() + "#" + board
 	}
+	if !constraints.IsEmpty() {
+		data += "#" + constraints.SideToMove + "#" + constraints.Castling + "#" + constraints.EnPassant
+	}
+	if !filter.IsEmpty() {
+		data += "#" + strconv.FormatInt(filter.EndedFrom, 10) + "#" + strconv.FormatInt(filter.EndedTo, 10) +
+			"#" + filter.Color + "#" + filter.Result + "#" + filter.TimeClass + "#" + filter.Opponent
+	}
 	hash := sha256.Sum256([]byte(data))
 	id := hex.EncodeToString(hash[:])
 	return SearchId{value: id}
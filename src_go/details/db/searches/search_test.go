@@ -9,6 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,7 +19,7 @@ func Test_SearchRecord_should_be_stored_in_correct_form(t *testing.T) {
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	board := uuid.New().String()
-	searchId := NewSearchId(userId, &downloadStartedAt, board)
+	searchId := NewSearchId(userId, &downloadStartedAt, board, BoardConstraints{}, games.GameFilter{})
 
 	startAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	lastExaminedAt := db.Zuludatetime(time.Date(2023, time.September, 5, 19, 45, 17, 321000000, time.UTC))
@@ -114,7 +115,7 @@ func Test_SearchRecord_should_be_stored_in_correct_form_even_if_matched_field_is
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	board := uuid.New().String()
-	searchId := NewSearchId(userId, &downloadStartedAt, board)
+	searchId := NewSearchId(userId, &downloadStartedAt, board, BoardConstraints{}, games.GameFilter{})
 
 	startAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	lastExaminedAt := db.Zuludatetime(time.Date(2023, time.September, 5, 19, 45, 17, 321000000, time.UTC))
@@ -198,3 +199,93 @@ func Test_SearchRecord_should_be_stored_in_correct_form_even_if_matched_field_is
 	assert.Equal(t, expectedSearch.Status, actualSearch.Status)
 	assert.Equal(t, time.Time(expectedSearch.ExpiresAt).UTC(), time.Time(actualSearch.ExpiresAt).UTC())
 }
+
+func Test_SearchRecord_should_be_read_from_the_form_that_has_only_the_matched_string_set(t *testing.T) {
+	matchedGame1 := uuid.New().String()
+	matchedGame2 := uuid.New().String()
+
+	storedItems := map[string]*dynamodb.AttributeValue{
+		"search_id": {
+			S: aws.String(uuid.New().String()),
+		},
+		"start_at": {
+			S: aws.String("2023-10-01T11:30:17.123Z"),
+		},
+		"last_examined_at": {
+			S: aws.String("2023-09-05T19:45:17.321Z"),
+		},
+		"examined": {
+			N: aws.String("456"),
+		},
+		"status": {
+			S: aws.String(string(SearchedAll)),
+		},
+		"total": {
+			N: aws.String("789"),
+		},
+		"matched": {
+			SS: []*string{
+				aws.String(matchedGame1),
+				aws.String(matchedGame2),
+			},
+		},
+		"expires_at": {
+			N: aws.String("1694029517"),
+		},
+	}
+
+	actualSearch := SearchRecord{}
+	err := dynamodbattribute.UnmarshalMap(storedItems, &actualSearch)
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{matchedGame1, matchedGame2}, actualSearch.Matched)
+	assert.Nil(t, actualSearch.Matches)
+}
+
+func Test_SearchRecord_should_store_the_location_of_every_match(t *testing.T) {
+	search := SearchRecord{
+		Matched: []string{"game1", "game2"},
+		Matches: []Match{
+			{
+				Resource: "game1",
+				Location: &MatchLocation{Ply: 3, MoveNumber: 2, SideToMove: "black", Fen: "rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2"},
+			},
+			{
+				Resource: "game2",
+			},
+		},
+	}
+
+	actualMarshalledItems, err := dynamodbattribute.MarshalMap(search)
+	assert.NoError(t, err)
+
+	expectedMatches := &dynamodb.AttributeValue{
+		L: []*dynamodb.AttributeValue{
+			{
+				M: map[string]*dynamodb.AttributeValue{
+					"resource": {S: aws.String("game1")},
+					"location": {
+						M: map[string]*dynamodb.AttributeValue{
+							"ply":          {N: aws.String("3")},
+							"move_number":  {N: aws.String("2")},
+							"side_to_move": {S: aws.String("black")},
+							"fen":          {S: aws.String("rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2")},
+						},
+					},
+				},
+			},
+			{
+				M: map[string]*dynamodb.AttributeValue{
+					"resource": {S: aws.String("game2")},
+					"location": {NULL: aws.Bool(true)},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expectedMatches, actualMarshalledItems["matches"])
+
+	actualSearch := SearchRecord{}
+	err = dynamodbattribute.UnmarshalMap(actualMarshalledItems, &actualSearch)
+	assert.NoError(t, err)
+	assert.Equal(t, search.Matches, actualSearch.Matches)
+}
@@ -0,0 +1,151 @@
+package searches
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ SearchesRepository = SqlSearchesTable{}
+
+// SqlSearchesTable behaves as SearchesTable does, including the updates creating a missing search.
+// The matches are kept as json. The expired records stay until ExpireRecords is called.
+type SqlSearchesTable struct {
+	Database *db.SqlDatabase
+}
+
+var searchColumns = []string{"search_id", "start_at", "last_examined_at", "examined", "total", "matched", "matches", "status", "expires_at"}
+
+func (table SqlSearchesTable) PutSearchRecord(ctx context.Context, searchRecord SearchRecord) (err error) {
+	_, err = table.Database.Exec(
+		ctx,
+		db.Upsert("searches", searchColumns[:1], searchColumns),
+		searchRecord.SearchId,
+		searchRecord.StartAt,
+		searchRecord.LastExaminedAt,
+		searchRecord.Examined,
+		searchRecord.Total,
+		asJson(&searchRecord.Matched),
+		asJson(&searchRecord.Matches),
+		searchRecord.Status,
+		db.UnixSeconds{Time: &searchRecord.ExpiresAt},
+	)
+	return
+}
+
+func (table SqlSearchesTable) GetSearchRecord(ctx context.Context, searchId string) (searchRecord *SearchRecord, err error) {
+	searchRecordCadidate := SearchRecord{}
+	err = table.Database.QueryRow(
+		ctx,
+		`SELECT search_id, start_at, last_examined_at, examined, total, matched, matches, status, expires_at FROM searches WHERE search_id = ?`,
+		searchId,
+	).Scan(
+		&searchRecordCadidate.SearchId,
+		&searchRecordCadidate.StartAt,
+		&searchRecordCadidate.LastExaminedAt,
+		&searchRecordCadidate.Examined,
+		&searchRecordCadidate.Total,
+		asJson(&searchRecordCadidate.Matched),
+		asJson(&searchRecordCadidate.Matches),
+		&searchRecordCadidate.Status,
+		db.UnixSeconds{Time: &searchRecordCadidate.ExpiresAt},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	searchRecord = &searchRecordCadidate
+	return
+}
+
+func (table SqlSearchesTable) UpdateMatchings(ctx context.Context, searchId string, examined int, matched []Match, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+	resources := Resources(matched)
+	expiresAt := dynamodbattribute.UnixTime(now.ToTime().Add(expiresIn))
+	_, err = table.Database.Exec(
+		ctx,
+		`INSERT INTO searches (search_id, examined, last_examined_at, matched, matches, expires_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (search_id) DO UPDATE SET examined = excluded.examined, last_examined_at = excluded.last_examined_at,
+		matched = excluded.matched, matches = excluded.matches, expires_at = excluded.expires_at`,
+		searchId,
+		examined,
+		now,
+		asJson(&resources),
+		asJson(&matched),
+		db.UnixSeconds{Time: &expiresAt},
+	)
+	return
+}
+
+func (table SqlSearchesTable) UpdateStatus(ctx context.Context, searchId string, status SearchStatus) (err error) {
+	_, err = table.Database.Exec(
+		ctx,
+		`INSERT INTO searches (search_id, status) VALUES (?, ?) ON CONFLICT (search_id) DO UPDATE SET status = excluded.status`,
+		searchId,
+		status,
+	)
+	return
+}
+
+// ExpireRecords deletes the records whose time to live is over, as the time to live of DynamoDB does.
+func (table SqlSearchesTable) ExpireRecords(now time.Time) (err error) {
+	_, err = table.Database.Exec(context.Background(), `DELETE FROM searches WHERE expires_at < ?`, now.Unix())
+	return
+}
+
+// jsonColumn keeps a slice as json, an empty slice is NULL as an empty attribute is in DynamoDB.
+type jsonColumn[T any] struct {
+	slice *[]T
+}
+
+func asJson[T any](slice *[]T) jsonColumn[T] {
+	return jsonColumn[T]{slice: slice}
+}
+
+func (column jsonColumn[T]) Value() (driver.Value, error) {
+	if len(*column.slice) == 0 {
+		return nil, nil
+	}
+	value, err := json.Marshal(*column.slice)
+	return string(value), err
+}
+
+func (column jsonColumn[T]) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*column.slice = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(value), column.slice)
+	case []byte:
+		return json.Unmarshal(value, column.slice)
+	default:
+		return fmt.Errorf("impossible to scan %T into a json column", src)
+	}
+}
+
+func (id SearchId) Value() (driver.Value, error) {
+	return id.value, nil
+}
+
+func (id *SearchId) Scan(src any) error {
+	switch value := src.(type) {
+	case string:
+		id.value = value
+	case []byte:
+		id.value = string(value)
+	default:
+		return fmt.Errorf("impossible to scan %T into a search id", src)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package searches
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/sqltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SqlSearchesTable_should_keep_the_matches_of_the_search_as_DynamoDB_does(t *testing.T) {
+	table := SqlSearchesTable{Database: sqltest.OpenDatabase(t)}
+	startAt := time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC)
+	searchId := NewSearchId("https://api.chess.com/pub/player/tigran-c-137", nil, "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????", BoardConstraints{}, games.GameFilter{})
+
+	err := table.PutSearchRecord(context.Background(), NewSearchRecord(searchId, startAt, 100, time.Hour))
+	assert.NoError(t, err)
+
+	actualSearch, err := table.GetSearchRecord(context.Background(), searchId.String())
+	assert.NoError(t, err)
+	assert.Nil(t, actualSearch.Matched, "an empty string set is not stored")
+	assert.Equal(t, db.Zuludatetime(startAt), actualSearch.StartAt)
+	assert.Equal(t, startAt.Add(time.Hour).Unix(), time.Time(actualSearch.ExpiresAt).Unix())
+	assert.Equal(t, InProgress, actualSearch.Status)
+
+	matched := []Match{
+		{
+			Resource: "https://www.chess.com/game/live/63025767719",
+			Location: &MatchLocation{Ply: 79, MoveNumber: 40, SideToMove: "black", Fen: "4R1r1/1p3kq1/p3Q3/3p1p2/BP6/P7/6PP/7K b - - 6 40"},
+		},
+	}
+	now := db.Zuludatetime(startAt.Add(time.Minute))
+	err = table.UpdateMatchings(context.Background(), searchId.String(), 50, matched, now, time.Hour)
+	assert.NoError(t, err)
+	err = table.UpdateStatus(context.Background(), searchId.String(), SearchedAll)
+	assert.NoError(t, err)
+
+	actualSearch, err = table.GetSearchRecord(context.Background(), searchId.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 50, actualSearch.Examined)
+	assert.Equal(t, 100, actualSearch.Total)
+	assert.Equal(t, []string{"https://www.chess.com/game/live/63025767719"}, actualSearch.Matched)
+	assert.Equal(t, matched, actualSearch.Matches)
+	assert.Equal(t, now, actualSearch.LastExaminedAt)
+	assert.Equal(t, SearchedAll, actualSearch.Status)
+
+	err = table.ExpireRecords(startAt.Add(2 * time.Hour))
+	assert.NoError(t, err)
+	actualSearch, err = table.GetSearchRecord(context.Background(), searchId.String())
+	assert.NoError(t, err)
+	assert.Nil(t, actualSearch)
+}
+
+func Test_SqlSearchesTable_should_create_a_missing_search_on_update_as_DynamoDB_does(t *testing.T) {
+	table := SqlSearchesTable{Database: sqltest.OpenDatabase(t)}
+
+	err := table.UpdateStatus(context.Background(), "missing-search", SearchedPartially)
+	assert.NoError(t, err)
+
+	actualSearch, err := table.GetSearchRecord(context.Background(), "missing-search")
+	assert.NoError(t, err)
+	assert.Equal(t, "missing-search", actualSearch.SearchId.String())
+	assert.Equal(t, SearchedPartially, actualSearch.Status)
+	assert.Nil(t, actualSearch.Matches)
+}
@@ -1,6 +1,7 @@
 package searches
 
 import (
+	"context"
 	"strconv"
 	"time"
 
@@ -10,18 +11,27 @@ import (
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
 )
 
+type SearchesRepository interface {
+	PutSearchRecord(ctx context.Context, searchRecord SearchRecord) error
+	GetSearchRecord(ctx context.Context, searchId string) (*SearchRecord, error)
+	UpdateMatchings(ctx context.Context, searchId string, examined int, matched []Match, now db.ZuluDateTime, expiresIn time.Duration) error
+	UpdateStatus(ctx context.Context, searchId string, status SearchStatus) error
+}
+
+var _ SearchesRepository = SearchesTable{}
+
 type SearchesTable struct {
 	Name           string
 	DynamodbClient *dynamodb.DynamoDB
 }
 
-func (table SearchesTable) PutSearchRecord(searchRecord SearchRecord) (err error) {
+func (table SearchesTable) PutSearchRecord(ctx context.Context, searchRecord SearchRecord) (err error) {
 	items, err := dynamodbattribute.MarshalMap(searchRecord)
 	if err != nil {
 		return
 	}
 
-	_, err = table.DynamodbClient.PutItem(&dynamodb.PutItemInput{
+	_, err = table.DynamodbClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(table.Name),
 		Item:      items,
 	})
@@ -29,8 +39,8 @@ func (table SearchesTable) PutSearchRecord(searchRecord SearchRecord) (err error
 	return
 }
 
-func (table SearchesTable) GetSearchRecord(searchId string) (searchRecord *SearchRecord, err error) {
-	items, err := table.DynamodbClient.GetItem(&dynamodb.GetItemInput{
+func (table SearchesTable) GetSearchRecord(ctx context.Context, searchId string) (searchRecord *SearchRecord, err error) {
+	items, err := table.DynamodbClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"search_id": {
@@ -56,18 +66,26 @@ func (table SearchesTable) GetSearchRecord(searchId string) (searchRecord *Searc
 	return
 }
 
-func (table SearchesTable) UpdateMatchings(searchId string, examined int, matched []string, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
+func (table SearchesTable) UpdateMatchings(ctx context.Context, searchId string, examined int, matched []Match, now db.ZuluDateTime, expiresIn time.Duration) (err error) {
 	var matchedAttributes *dynamodb.AttributeValue
+	var matchesAttributes *dynamodb.AttributeValue
 	if len(matched) > 0 {
 		matchedAttributes = &dynamodb.AttributeValue{
-			SS: aws.StringSlice(matched),
+			SS: aws.StringSlice(Resources(matched)),
+		}
+		matchesAttributes, err = dynamodbattribute.Marshal(matched)
+		if err != nil {
+			return
 		}
 	} else {
 		matchedAttributes = &dynamodb.AttributeValue{
 			NULL: aws.Bool(true),
 		}
+		matchesAttributes = &dynamodb.AttributeValue{
+			NULL: aws.Bool(true),
+		}
 	}
-	_, err = table.DynamodbClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err = table.DynamodbClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"search_id": {
@@ -85,15 +103,16 @@ func (table SearchesTable) UpdateMatchings(searchId string, examined int, matche
 				N: aws.String(strconv.FormatInt(now.ToTime().Add(expiresIn).Unix(), 10)),
 			},
 			":matched": matchedAttributes,
+			":matches": matchesAttributes,
 		},
-		UpdateExpression: aws.String("SET examined = :examined, last_examined_at = :lastExaminedAt, matched = :matched, expires_at = :expiresAt"),
+		UpdateExpression: aws.String("SET examined = :examined, last_examined_at = :lastExaminedAt, matched = :matched, matches = :matches, expires_at = :expiresAt"),
 	})
 
 	return
 }
 
-func (table SearchesTable) UpdateStatus(searchId string, status SearchStatus) (err error) {
-	_, err = table.DynamodbClient.UpdateItem(&dynamodb.UpdateItemInput{
+func (table SearchesTable) UpdateStatus(ctx context.Context, searchId string, status SearchStatus) (err error) {
+	_, err = table.DynamodbClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"search_id": {
@@ -1,6 +1,7 @@
 package searches
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -17,7 +19,7 @@ func Test_SearchTable_should_persist_search_record_into_the_table(t *testing.T)
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	board := uuid.New().String()
-	seachId := NewSearchId(userId, &downloadStartedAt, board)
+	seachId := NewSearchId(userId, &downloadStartedAt, board, BoardConstraints{}, games.GameFilter{})
 
 	startAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	lastExaminedAt := db.Zuludatetime(time.Date(2023, time.September, 5, 19, 45, 17, 321000000, time.UTC))
@@ -39,7 +41,7 @@ func Test_SearchTable_should_persist_search_record_into_the_table(t *testing.T)
 		ExpiresAt:      dynamodbattribute.UnixTime(expiresAt),
 	}
 
-	err = searchesTable.PutSearchRecord(search)
+	err = searchesTable.PutSearchRecord(context.Background(), search)
 	assert.NoError(t, err)
 
 	getArchiveOutput, err := dynamodbClient.GetItem(
@@ -75,7 +77,7 @@ func Test_SearchTable_should_get_search_record_from_the_table(t *testing.T) {
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	board := uuid.New().String()
-	seachId := NewSearchId(userId, &downloadStartedAt, board)
+	seachId := NewSearchId(userId, &downloadStartedAt, board, BoardConstraints{}, games.GameFilter{})
 
 	startAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	lastExaminedAt := db.Zuludatetime(time.Date(2023, time.September, 5, 19, 45, 17, 321000000, time.UTC))
@@ -107,7 +109,7 @@ func Test_SearchTable_should_get_search_record_from_the_table(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	actualSearch, err := searchesTable.GetSearchRecord(seachId.String())
+	actualSearch, err := searchesTable.GetSearchRecord(context.Background(), seachId.String())
 	assert.NoError(t, err)
 	assert.NotNil(t, actualSearch)
 
@@ -127,7 +129,7 @@ func Test_SearchTable_should_return_nil_if_the_search_is_missing_from_the_table(
 
 	seachId := uuid.New().String()
 
-	actualSearch, err := searchesTable.GetSearchRecord(seachId)
+	actualSearch, err := searchesTable.GetSearchRecord(context.Background(), seachId)
 	assert.NoError(t, err)
 	assert.Nil(t, actualSearch)
 }
@@ -139,7 +141,7 @@ func Test_SearchTable_should_update_the_search_record_matchings_in_the_table(t *
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	board := uuid.New().String()
-	seachId := NewSearchId(userId, &downloadStartedAt, board)
+	seachId := NewSearchId(userId, &downloadStartedAt, board, BoardConstraints{}, games.GameFilter{})
 
 	startAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	lastExaminedAt := db.Zuludatetime(time.Date(2023, time.September, 5, 19, 45, 17, 321000000, time.UTC))
@@ -174,18 +176,28 @@ func Test_SearchTable_should_update_the_search_record_matchings_in_the_table(t *
 	newExamined := 789
 	newMatchedGame1 := uuid.New().String()
 	newMatchedGame2 := uuid.New().String()
-	newMatched := []string{newMatchedGame1, newMatchedGame2}
+	newMatched := []Match{
+		{
+			Resource: newMatchedGame1,
+			Location: &MatchLocation{Ply: 0, MoveNumber: 1, SideToMove: "white", Fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		},
+		{
+			Resource: newMatchedGame2,
+			Location: &MatchLocation{Ply: 3, MoveNumber: 2, SideToMove: "black", Fen: "rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2"},
+		},
+	}
 	newExpiresAt := time.Date(2023, time.September, 7, 19, 45, 17, 0, time.UTC)
 
-	err = searchesTable.UpdateMatchings(seachId.String(), newExamined, newMatched, newLastExaminedAt, expiresIn)
+	err = searchesTable.UpdateMatchings(context.Background(), seachId.String(), newExamined, newMatched, newLastExaminedAt, expiresIn)
 	assert.NoError(t, err)
 
-	actualSearch, err := searchesTable.GetSearchRecord(seachId.String())
+	actualSearch, err := searchesTable.GetSearchRecord(context.Background(), seachId.String())
 	assert.NoError(t, err)
 
 	assert.Equal(t, newExamined, actualSearch.Examined)
 	assert.Equal(t, newLastExaminedAt, actualSearch.LastExaminedAt)
-	assert.ElementsMatch(t, newMatched, actualSearch.Matched)
+	assert.ElementsMatch(t, []string{newMatchedGame1, newMatchedGame2}, actualSearch.Matched)
+	assert.Equal(t, newMatched, actualSearch.Matches)
 	assert.Equal(t, time.Time(newExpiresAt).UTC(), time.Time(actualSearch.ExpiresAt).UTC())
 
 }
@@ -196,7 +208,7 @@ func Test_SearchTable_should_update_the_search_record_status_in_the_table(t *tes
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	board := uuid.New().String()
-	seachId := NewSearchId(userId, &downloadStartedAt, board)
+	seachId := NewSearchId(userId, &downloadStartedAt, board, BoardConstraints{}, games.GameFilter{})
 
 	startAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	lastExaminedAt := db.Zuludatetime(time.Date(2023, time.September, 5, 19, 45, 17, 321000000, time.UTC))
@@ -229,10 +241,10 @@ func Test_SearchTable_should_update_the_search_record_status_in_the_table(t *tes
 
 	newStatus := SearchedAll
 
-	err = searchesTable.UpdateStatus(seachId.String(), newStatus)
+	err = searchesTable.UpdateStatus(context.Background(), seachId.String(), newStatus)
 	assert.NoError(t, err)
 
-	actualSearch, err := searchesTable.GetSearchRecord(seachId.String())
+	actualSearch, err := searchesTable.GetSearchRecord(context.Background(), seachId.String())
 	assert.NoError(t, err)
 
 	assert.Equal(t, newStatus, actualSearch.Status)
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// SqlDatabase keeps the tables of a self-hosted installation in SQLite or Postgres.
+// The driver is registered by the binary, e.g. by importing github.com/mattn/go-sqlite3 or github.com/lib/pq.
+// The queries are written with ? placeholders and rebound to $1, $2, ... for Postgres.
+type SqlDatabase struct {
+	DB               *sql.DB
+	numberedBindVars bool
+}
+
+func OpenSqlDatabase(driverName string, dataSource string) (database *SqlDatabase, err error) {
+	sqlDB, err := sql.Open(driverName, dataSource)
+	if err != nil {
+		return
+	}
+	database = &SqlDatabase{DB: sqlDB}
+	switch driverName {
+	case "postgres", "pgx":
+		database.numberedBindVars = true
+	case "sqlite3", "sqlite":
+		// SQLite has a single writer, concurrent writes would fail with "database is locked" instead of waiting.
+		sqlDB.SetMaxOpenConns(1)
+	default:
+		sqlDB.Close()
+		database = nil
+		err = fmt.Errorf("unsupported sql driver %s", driverName)
+	}
+	return
+}
+
+func (database *SqlDatabase) Close() error {
+	return database.DB.Close()
+}
+
+func (database *SqlDatabase) Rebind(query string) string {
+	if !database.numberedBindVars {
+		return query
+	}
+	rebound := strings.Builder{}
+	bindVar := 0
+	for _, char := range query {
+		if char == '?' {
+			bindVar++
+			rebound.WriteString("$" + strconv.Itoa(bindVar))
+			continue
+		}
+		rebound.WriteRune(char)
+	}
+	return rebound.String()
+}
+
+func (database *SqlDatabase) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return database.DB.ExecContext(ctx, database.Rebind(query), args...)
+}
+
+func (database *SqlDatabase) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return database.DB.QueryContext(ctx, database.Rebind(query), args...)
+}
+
+func (database *SqlDatabase) QueryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	return database.DB.QueryRowContext(ctx, database.Rebind(query), args...)
+}
+
+// InTransaction runs the statements of the query once per argument list within a single transaction.
+func (database *SqlDatabase) InTransaction(ctx context.Context, query string, argLists [][]any) (err error) {
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	statement, err := tx.PrepareContext(ctx, database.Rebind(query))
+	if err != nil {
+		return
+	}
+	defer statement.Close()
+	for _, args := range argLists {
+		_, err = statement.ExecContext(ctx, args...)
+		if err != nil {
+			return
+		}
+	}
+	err = tx.Commit()
+	return
+}
+
+// Upsert builds the statement replacing the whole row with the given key, as PutItem does.
+func Upsert(table string, keyColumns []string, columns []string) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	updates := []string{}
+	for _, column := range columns[len(keyColumns):] {
+		updates = append(updates, column+" = excluded."+column)
+	}
+	return "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES (" + placeholders + ")" +
+		" ON CONFLICT (" + strings.Join(keyColumns, ", ") + ") DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+func (zulu ZuluDateTime) Value() (driver.Value, error) {
+	return zulu.String(), nil
+}
+
+func (zulu *ZuluDateTime) Scan(src any) (err error) {
+	var s string
+	switch value := src.(type) {
+	case nil:
+		return
+	case string:
+		s = value
+	case []byte:
+		s = string(value)
+	default:
+		return fmt.Errorf("impossible to scan %T into a zulu datetime", src)
+	}
+	if s == "" {
+		return
+	}
+	*zulu, err = ZuluDateTimeFromString(s)
+	return
+}
+
+// UnixSeconds is the column of a time to live, which is kept as DynamoDB keeps it.
+type UnixSeconds struct {
+	Time *dynamodbattribute.UnixTime
+}
+
+func (seconds UnixSeconds) Value() (driver.Value, error) {
+	return time.Time(*seconds.Time).Unix(), nil
+}
+
+func (seconds UnixSeconds) Scan(src any) (err error) {
+	value, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("impossible to scan %T into unix seconds", src)
+	}
+	*seconds.Time = dynamodbattribute.UnixTime(time.Unix(value, 0))
+	return
+}
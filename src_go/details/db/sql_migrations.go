@@ -0,0 +1,128 @@
+package db
+
+import "context"
+
+// SqlMigrations are applied in order by Migrate and must never change once released, a change of the schema is a new migration.
+// The types are the ones SQLite and Postgres have in common, BYTEA keeps the blobs as they are in SQLite too.
+var SqlMigrations = [][]string{
+	{
+		`CREATE TABLE users (
+			username TEXT NOT NULL,
+			platform TEXT NOT NULL,
+			user_id TEXT NOT NULL DEFAULT '',
+			download_from_scratch BOOLEAN NOT NULL DEFAULT FALSE,
+			PRIMARY KEY (username, platform)
+		)`,
+		`CREATE TABLE archives (
+			user_id TEXT NOT NULL,
+			archive_id TEXT NOT NULL,
+			resource TEXT NOT NULL,
+			year INTEGER NOT NULL,
+			month INTEGER NOT NULL,
+			downloaded INTEGER NOT NULL,
+			downloaded_at TEXT,
+			uploaded BOOLEAN NOT NULL DEFAULT FALSE,
+			etag TEXT NOT NULL DEFAULT '',
+			last_modified TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (user_id, archive_id)
+		)`,
+		`CREATE TABLE downloads (
+			download_id TEXT NOT NULL PRIMARY KEY,
+			start_at TEXT NOT NULL,
+			last_downloaded_at TEXT NOT NULL,
+			expires_at BIGINT NOT NULL,
+			succeed INTEGER NOT NULL,
+			failed INTEGER NOT NULL,
+			done INTEGER NOT NULL,
+			pending INTEGER NOT NULL,
+			total INTEGER NOT NULL
+		)`,
+		`CREATE INDEX downloads_by_expires_at ON downloads (expires_at)`,
+		`CREATE TABLE searches (
+			search_id TEXT NOT NULL PRIMARY KEY,
+			start_at TEXT NOT NULL DEFAULT '',
+			last_examined_at TEXT NOT NULL DEFAULT '',
+			examined INTEGER NOT NULL DEFAULT 0,
+			total INTEGER NOT NULL DEFAULT 0,
+			matched TEXT,
+			matches TEXT,
+			status TEXT NOT NULL DEFAULT '',
+			expires_at BIGINT NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX searches_by_expires_at ON searches (expires_at)`,
+		`CREATE TABLE games (
+			user_id TEXT NOT NULL,
+			archive_id TEXT NOT NULL,
+			game_id TEXT NOT NULL,
+			resource TEXT NOT NULL,
+			pgn TEXT NOT NULL DEFAULT '',
+			end_timestamp BIGINT NOT NULL,
+			white TEXT NOT NULL DEFAULT '',
+			white_rating INTEGER NOT NULL DEFAULT 0,
+			white_result TEXT NOT NULL DEFAULT '',
+			black TEXT NOT NULL DEFAULT '',
+			black_rating INTEGER NOT NULL DEFAULT 0,
+			black_result TEXT NOT NULL DEFAULT '',
+			result TEXT NOT NULL DEFAULT '',
+			time_class TEXT NOT NULL DEFAULT '',
+			time_control TEXT NOT NULL DEFAULT '',
+			rated BOOLEAN NOT NULL DEFAULT FALSE,
+			eco TEXT NOT NULL DEFAULT '',
+			rules TEXT NOT NULL DEFAULT '',
+			user_color TEXT NOT NULL DEFAULT '',
+			user_result TEXT NOT NULL DEFAULT '',
+			opponent TEXT NOT NULL DEFAULT '',
+			moves BYTEA,
+			PRIMARY KEY (user_id, game_id)
+		)`,
+		// the counterpart of the games_by_end_timestamp global secondary index
+		`CREATE INDEX games_by_end_timestamp ON games (archive_id, end_timestamp)`,
+	},
+	{
+		`ALTER TABLE archives ADD COLUMN last_game_end_timestamp BIGINT NOT NULL DEFAULT 0`,
+	},
+}
+
+// Migrate applies the migrations the database does not have yet, each of them within its own transaction.
+func (database *SqlDatabase) Migrate() (err error) {
+	_, err = database.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY)`)
+	if err != nil {
+		return
+	}
+	var appliedVersion int
+	err = database.QueryRow(context.Background(), `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&appliedVersion)
+	if err != nil {
+		return
+	}
+	for version := appliedVersion + 1; version <= len(SqlMigrations); version++ {
+		err = database.migrate(version, SqlMigrations[version-1])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (database *SqlDatabase) migrate(version int, statements []string) (err error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	for _, statement := range statements {
+		_, err = tx.Exec(statement)
+		if err != nil {
+			return
+		}
+	}
+	_, err = tx.Exec(database.Rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), version)
+	if err != nil {
+		return
+	}
+	err = tx.Commit()
+	return
+}
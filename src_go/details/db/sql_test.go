@@ -0,0 +1,27 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Rebind_should_number_the_bind_variables_for_postgres(t *testing.T) {
+	query := `UPDATE downloads SET done = done + 1 WHERE download_id = ? AND done < ?`
+
+	assert.Equal(t, query, (&SqlDatabase{}).Rebind(query))
+	assert.Equal(t, `UPDATE downloads SET done = done + 1 WHERE download_id = $1 AND done < $2`, (&SqlDatabase{numberedBindVars: true}).Rebind(query))
+}
+
+func Test_Upsert_should_replace_all_the_columns_except_the_key(t *testing.T) {
+	actual := Upsert("users", []string{"username", "platform"}, []string{"username", "platform", "user_id", "download_from_scratch"})
+
+	expected := "INSERT INTO users (username, platform, user_id, download_from_scratch) VALUES (?, ?, ?, ?)" +
+		" ON CONFLICT (username, platform) DO UPDATE SET user_id = excluded.user_id, download_from_scratch = excluded.download_from_scratch"
+	assert.Equal(t, expected, actual)
+}
+
+func Test_OpenSqlDatabase_should_reject_an_unsupported_driver(t *testing.T) {
+	_, err := OpenSqlDatabase("mysql", "chessfinder:chessfinder@/chessfinder")
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package users
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ UsersRepository = &InMemoryUsersTable{}
+
+type userKey struct {
+	username string
+	platform Platform
+}
+
+// InMemoryUsersTable behaves as UsersTable does, including the updates creating a missing user.
+type InMemoryUsersTable struct {
+	mutex   sync.Mutex
+	records map[userKey]UserRecord
+}
+
+func NewInMemoryUsersTable() *InMemoryUsersTable {
+	return &InMemoryUsersTable{records: map[userKey]UserRecord{}}
+}
+
+func (table *InMemoryUsersTable) PutUserRecord(ctx context.Context, userRecord UserRecord) (err error) {
+	stored, err := db.RoundTrip(userRecord)
+	if err != nil {
+		return
+	}
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.records[userKey{userRecord.Username, userRecord.Platform}] = stored
+	return
+}
+
+func (table *InMemoryUsersTable) GetUserRecord(ctx context.Context, username string, platform Platform) (userRecord *UserRecord, err error) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	stored, exists := table.records[userKey{username, platform}]
+	if !exists {
+		return
+	}
+	userRecord = &stored
+	return
+}
+
+func (table *InMemoryUsersTable) DownloadFromScratch(ctx context.Context, username string, platform Platform) (err error) {
+	table.setDownloadFromScratch(username, platform, true)
+	return
+}
+
+func (table *InMemoryUsersTable) DownloadInitiated(ctx context.Context, username string, platform Platform) (err error) {
+	table.setDownloadFromScratch(username, platform, false)
+	return
+}
+
+func (table *InMemoryUsersTable) setDownloadFromScratch(username string, platform Platform, downloadFromScratch bool) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	key := userKey{username, platform}
+	stored, exists := table.records[key]
+	if !exists {
+		stored = UserRecord{Username: username, Platform: platform}
+	}
+	stored.DownloadFromScratch = downloadFromScratch
+	table.records[key] = stored
+}
@@ -0,0 +1,48 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InMemoryUsersTable_should_get_the_user_record_it_has_put(t *testing.T) {
+	table := NewInMemoryUsersTable()
+	userRecord := UserRecord{
+		Username:            "tigran-c-137",
+		Platform:            ChessDotCom,
+		UserId:              "https://api.chess.com/pub/player/tigran-c-137",
+		DownloadFromScratch: true,
+	}
+
+	err := table.PutUserRecord(context.Background(), userRecord)
+	assert.NoError(t, err)
+
+	actualUserRecord, err := table.GetUserRecord(context.Background(), "tigran-c-137", ChessDotCom)
+	assert.NoError(t, err)
+	assert.Equal(t, &userRecord, actualUserRecord)
+
+	actualUserRecord, err = table.GetUserRecord(context.Background(), "tigran-c-137", Lichess)
+	assert.NoError(t, err)
+	assert.Nil(t, actualUserRecord)
+
+	err = table.DownloadInitiated(context.Background(), "tigran-c-137", ChessDotCom)
+	assert.NoError(t, err)
+
+	actualUserRecord, err = table.GetUserRecord(context.Background(), "tigran-c-137", ChessDotCom)
+	assert.NoError(t, err)
+	assert.False(t, actualUserRecord.DownloadFromScratch)
+	assert.Equal(t, userRecord.UserId, actualUserRecord.UserId)
+}
+
+func Test_InMemoryUsersTable_should_create_a_missing_user_on_update_as_DynamoDB_does(t *testing.T) {
+	table := NewInMemoryUsersTable()
+
+	err := table.DownloadFromScratch(context.Background(), "tigran-c-137", Lichess)
+	assert.NoError(t, err)
+
+	actualUserRecord, err := table.GetUserRecord(context.Background(), "tigran-c-137", Lichess)
+	assert.NoError(t, err)
+	assert.Equal(t, &UserRecord{Username: "tigran-c-137", Platform: Lichess, DownloadFromScratch: true}, actualUserRecord)
+}
@@ -0,0 +1,75 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+)
+
+var _ UsersRepository = SqlUsersTable{}
+
+// SqlUsersTable behaves as UsersTable does, including the updates creating a missing user.
+type SqlUsersTable struct {
+	Database *db.SqlDatabase
+}
+
+var userColumns = []string{"username", "platform", "user_id", "download_from_scratch"}
+
+func (table SqlUsersTable) PutUserRecord(ctx context.Context, userRecord UserRecord) (err error) {
+	_, err = table.Database.Exec(
+		ctx,
+		db.Upsert("users", userColumns[:2], userColumns),
+		userRecord.Username,
+		userRecord.Platform,
+		userRecord.UserId,
+		userRecord.DownloadFromScratch,
+	)
+	return
+}
+
+func (table SqlUsersTable) GetUserRecord(ctx context.Context, username string, platform Platform) (userRecord *UserRecord, err error) {
+	userRecordCadidate := UserRecord{}
+	err = table.Database.QueryRow(
+		ctx,
+		`SELECT username, platform, user_id, download_from_scratch FROM users WHERE username = ? AND platform = ?`,
+		username,
+		platform,
+	).Scan(
+		&userRecordCadidate.Username,
+		&userRecordCadidate.Platform,
+		&userRecordCadidate.UserId,
+		&userRecordCadidate.DownloadFromScratch,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	userRecord = &userRecordCadidate
+	return
+}
+
+func (table SqlUsersTable) DownloadFromScratch(ctx context.Context, username string, platform Platform) (err error) {
+	return table.setDownloadFromScratch(ctx, username, platform, true)
+}
+
+func (table SqlUsersTable) DownloadInitiated(ctx context.Context, username string, platform Platform) (err error) {
+	return table.setDownloadFromScratch(ctx, username, platform, false)
+}
+
+func (table SqlUsersTable) setDownloadFromScratch(ctx context.Context, username string, platform Platform, downloadFromScratch bool) (err error) {
+	_, err = table.Database.Exec(
+		ctx,
+		`INSERT INTO users (username, platform, download_from_scratch) VALUES (?, ?, ?)
+		ON CONFLICT (username, platform) DO UPDATE SET download_from_scratch = excluded.download_from_scratch`,
+		username,
+		platform,
+		downloadFromScratch,
+	)
+	return
+}
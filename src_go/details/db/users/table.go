@@ -1,23 +1,33 @@
 package users
 
 import (
+	"context"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 )
 
+type UsersRepository interface {
+	PutUserRecord(ctx context.Context, userRecord UserRecord) error
+	GetUserRecord(ctx context.Context, username string, platform Platform) (*UserRecord, error)
+	DownloadFromScratch(ctx context.Context, username string, platform Platform) error
+	DownloadInitiated(ctx context.Context, username string, platform Platform) error
+}
+
+var _ UsersRepository = UsersTable{}
+
 type UsersTable struct {
 	Name           string
 	DynamodbClient *dynamodb.DynamoDB
 }
 
-func (table UsersTable) PutUserRecord(userRecord UserRecord) (err error) {
+func (table UsersTable) PutUserRecord(ctx context.Context, userRecord UserRecord) (err error) {
 	items, err := dynamodbattribute.MarshalMap(userRecord)
 	if err != nil {
 		return
 	}
 
-	_, err = table.DynamodbClient.PutItem(&dynamodb.PutItemInput{
+	_, err = table.DynamodbClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(table.Name),
 		Item:      items,
 	})
@@ -25,8 +35,8 @@ func (table UsersTable) PutUserRecord(userRecord UserRecord) (err error) {
 	return
 }
 
-func (table UsersTable) GetUserRecord(username string, platform Platform) (userRecord *UserRecord, err error) {
-	items, err := table.DynamodbClient.GetItem(&dynamodb.GetItemInput{
+func (table UsersTable) GetUserRecord(ctx context.Context, username string, platform Platform) (userRecord *UserRecord, err error) {
+	items, err := table.DynamodbClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"username": {
@@ -55,9 +65,9 @@ func (table UsersTable) GetUserRecord(username string, platform Platform) (userR
 	return
 }
 
-func (table UsersTable) DownloadFromScratch(username string, platform Platform) (err error) {
+func (table UsersTable) DownloadFromScratch(ctx context.Context, username string, platform Platform) (err error) {
 	// update user record
-	_, err = table.DynamodbClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err = table.DynamodbClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"username": {
@@ -78,9 +88,9 @@ func (table UsersTable) DownloadFromScratch(username string, platform Platform)
 	return
 }
 
-func (table UsersTable) DownloadInitiated(username string, platform Platform) (err error) {
+func (table UsersTable) DownloadInitiated(ctx context.Context, username string, platform Platform) (err error) {
 	// update user record
-	_, err = table.DynamodbClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err = table.DynamodbClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(table.Name),
 		Key: map[string]*dynamodb.AttributeValue{
 			"username": {
@@ -1,6 +1,7 @@
 package users
 
 import (
+	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -24,7 +25,7 @@ func Test_UserTable_should_persist_user_into_the_table(t *testing.T) {
 		DownloadFromScratch: true,
 	}
 
-	err = usersTable.PutUserRecord(user)
+	err = usersTable.PutUserRecord(context.Background(), user)
 
 	assert.NoError(t, err)
 
@@ -76,7 +77,7 @@ func Test_UserTable_should_get_the_user_from_the_table(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	actualUserRecord, err := usersTable.GetUserRecord(username, platform)
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, platform)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualUserRecord)
 
@@ -111,7 +112,7 @@ func Test_UserTable_should_get_the_user_from_the_table_even_if_it_was_stored_wit
 
 	assert.NoError(t, err)
 
-	actualUserRecord, err := usersTable.GetUserRecord(username, platform)
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, platform)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualUserRecord)
 
@@ -130,7 +131,7 @@ func Test_UserTable_should_return_nil_if_the_user_is_not_found_in_the_table(t *t
 	username := uuid.New().String()
 	platform := ChessDotCom
 
-	actualUserRecord, err := usersTable.GetUserRecord(username, platform)
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, platform)
 	assert.NoError(t, err)
 	assert.Nil(t, actualUserRecord)
 }
@@ -159,7 +160,7 @@ func Test_UserTable_should_put_DownloadFromScratch_flag_to_true_when_DownloadFro
 
 	assert.NoError(t, err)
 
-	err = usersTable.DownloadFromScratch(username, platform)
+	err = usersTable.DownloadFromScratch(context.Background(), username, platform)
 	assert.NoError(t, err)
 
 	getArchiveOutput, err := dynamodbClient.GetItem(
@@ -212,7 +213,7 @@ func Test_UserTable_should_put_DownloadFromScratch_flag_to_false_when_DownloadIn
 
 	assert.NoError(t, err)
 
-	err = usersTable.DownloadInitiated(username, platform)
+	err = usersTable.DownloadInitiated(context.Background(), username, platform)
 	assert.NoError(t, err)
 
 	getArchiveOutput, err := dynamodbClient.GetItem(
@@ -21,6 +21,10 @@ const (
 )
 
 func (meter *ChessDotComMeter) ChessDotComStatistics(action ChessDotComAction, statusCode int) (err error) {
+	// nothing is published without CloudWatch, as in the standalone server
+	if meter.CloudWatchClient == nil {
+		return
+	}
 
 	actionDimension := &cloudwatch.Dimension{
 		Name:  aws.String("Action"),
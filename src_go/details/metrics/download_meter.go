@@ -11,6 +11,9 @@ type DownloadMeter struct {
 }
 
 func (meter *DownloadMeter) SearchStatistics(amountOfGames int) (err error) {
+	if meter.CloudWatchClient == nil {
+		return
+	}
 
 	chessDotComDatum := &cloudwatch.MetricDatum{
 		MetricName: aws.String("DownloadMeter"),
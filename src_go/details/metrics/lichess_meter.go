@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+type LichessMeter struct {
+	Namespace        string
+	CloudWatchClient *cloudwatch.CloudWatch
+}
+
+type LichessAction string
+
+const (
+	GetAccount  LichessAction = "GetAccount"
+	ExportGames LichessAction = "ExportGames"
+)
+
+func (meter *LichessMeter) LichessStatistics(action LichessAction, statusCode int) (err error) {
+	if meter.CloudWatchClient == nil {
+		return
+	}
+
+	actionDimension := &cloudwatch.Dimension{
+		Name:  aws.String("Action"),
+		Value: aws.String(string(action)),
+	}
+
+	statusCodeDimension := &cloudwatch.Dimension{
+		Name:  aws.String("StatusCode"),
+		Value: aws.String(strconv.Itoa(statusCode)),
+	}
+
+	lichessDatum := &cloudwatch.MetricDatum{
+		MetricName: aws.String("LichessMeter"),
+		Unit:       aws.String("Count"),
+		Value:      aws.Float64(1.0),
+		Dimensions: []*cloudwatch.Dimension{
+			actionDimension,
+			statusCodeDimension,
+		},
+	}
+
+	_, err = meter.CloudWatchClient.PutMetricData(&cloudwatch.PutMetricDataInput{
+		Namespace: aws.String(string(meter.Namespace)),
+		MetricData: []*cloudwatch.MetricDatum{
+			lichessDatum,
+		},
+	})
+	return
+
+}
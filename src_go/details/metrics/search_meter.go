@@ -20,6 +20,9 @@ const (
 )
 
 func (meter *SearchAttemtMeter) SearchStatistics(stat StatisticsType) (err error) {
+	if meter.CloudWatchClient == nil {
+		return
+	}
 
 	statDimension := &cloudwatch.Dimension{
 		Name:  aws.String("StatisticsType"),
@@ -50,12 +50,21 @@ func main() {
 		panic(err)
 	}
 
+	concurrency := 1
+	if concurrencyCandidate := os.Getenv("PROCESSING_CONCURRENCY"); concurrencyCandidate != "" {
+		concurrency, err = strconv.Atoi(concurrencyCandidate)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	notifier := Notifier{
 		telegramUrl:           telegramUrl,
 		telegramBotApiKey:     telegramBotApiKey,
 		telegramChatId:        telegramChatId,
 		telegramReportTopicId: telegramReportTopicId,
 		telegramAlarmTopicId:  telegramAlarmTopicId,
+		concurrency:           concurrency,
 	}
 
 	lambda.Start(notifier.Notify)
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 
@@ -19,23 +21,24 @@ type Notifier struct {
 	telegramChatId        int64
 	telegramReportTopicId int64
 	telegramAlarmTopicId  int64
+	concurrency           int
 }
 
 func (notifier *Notifier) Notify(commands events.SQSEvent) (events.SQSEventResponse, error) {
 	logger := logging.MustCreateZuluTimeLogger()
 	defer logger.Sync()
-	failedEvents := queue.ProcessMultiple(commands, notifier, logger)
+	failedEvents := queue.BatchProcessor{Concurrency: notifier.concurrency}.Process(commands, notifier, logger)
 	return failedEvents, nil
 }
 
 func (notifier *Notifier) ProcessSingle(
+	ctx context.Context,
 	message *events.SQSMessage,
 	logger *zap.Logger,
-) (commandProcessed *events.SQSBatchItemFailure, err error) {
+) (err error) {
 	notificationType, ok := message.MessageAttributes["NotificationType"]
 	if !ok {
-		notifier.handleAlarm(message, logger)
-		return
+		return notifier.handleAlarm(message, logger)
 	}
 
 	if notificationType.StringValue == nil {
@@ -44,18 +47,18 @@ func (notifier *Notifier) ProcessSingle(
 	}
 
 	if *notificationType.StringValue == "Job" {
-		notifier.handleNotification(message, logger)
-		return
+		return notifier.handleNotification(message, logger)
 	}
 	return
 }
 
-func (notifier *Notifier) handleNotification(message *events.SQSMessage, logger *zap.Logger) (commandProcessed *events.SQSBatchItemFailure, err error) {
+func (notifier *Notifier) handleNotification(message *events.SQSMessage, logger *zap.Logger) (err error) {
 	telegramClient := &http.Client{}
 	businessNotification := notification.BusinessNotification{}
 	err = json.Unmarshal([]byte(message.Body), &businessNotification)
 	if err != nil {
 		logger.Error("impossible to unmarshal the SNS notification!")
+		err = queue.Permanent(err)
 		return
 	}
 
@@ -73,6 +76,7 @@ func (notifier *Notifier) handleNotification(message *events.SQSMessage, logger
 	notificationJson, err := json.Marshal(telegramMessage)
 	if err != nil {
 		logger.Error("impossible to marshal the notification!")
+		err = queue.Permanent(err)
 		return
 	}
 
@@ -98,6 +102,7 @@ func (notifier *Notifier) handleNotification(message *events.SQSMessage, logger
 
 	if notificationResponse.StatusCode != 200 {
 		logger.Error("unexpected status code from telegram", zap.Int("statusCode", notificationResponse.StatusCode))
+		err = fmt.Errorf("unexpected status code %d from telegram", notificationResponse.StatusCode)
 		return
 	}
 
@@ -124,12 +129,13 @@ func (notifier *Notifier) handleNotification(message *events.SQSMessage, logger
 	return
 }
 
-func (notifier *Notifier) handleAlarm(message *events.SQSMessage, logger *zap.Logger) (commandProcessed *events.SQSBatchItemFailure, err error) {
+func (notifier *Notifier) handleAlarm(message *events.SQSMessage, logger *zap.Logger) (err error) {
 	telegramClient := &http.Client{}
 	snsNotification := events.CloudWatchAlarmSNSPayload{}
 	err = json.Unmarshal([]byte(message.Body), &snsNotification)
 	if err != nil {
 		logger.Error("impossible to unmarshal the SNS notification!")
+		err = queue.Permanent(err)
 		return
 	}
 
@@ -164,6 +170,7 @@ func (notifier *Notifier) handleAlarm(message *events.SQSMessage, logger *zap.Lo
 	notificationJson, err := json.Marshal(telegramMessage)
 	if err != nil {
 		logger.Error("impossible to marshal the notification!")
+		err = queue.Permanent(err)
 		return
 	}
 
@@ -189,6 +196,7 @@ func (notifier *Notifier) handleAlarm(message *events.SQSMessage, logger *zap.Lo
 
 	if notificationResponse.StatusCode != 200 {
 		logger.Error("unexpected status code from telegram", zap.Int("statusCode", notificationResponse.StatusCode))
+		err = fmt.Errorf("unexpected status code %d from telegram", notificationResponse.StatusCode)
 		return
 	}
 
@@ -0,0 +1,65 @@
+package pgn
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Color int
+
+const (
+	White Color = iota
+	Black
+)
+
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// Move is a SAN move of the movetext with its annotations and the variations that replace it.
+type Move struct {
+	Number     int
+	Color      Color
+	San        string
+	Nags       []int
+	Comments   []string
+	Variations [][]Move
+}
+
+// Game is a parsed PGN game. Comments are the comments before the first move,
+// Result is the game termination marker of the movetext.
+type Game struct {
+	Tags     []Tag
+	Comments []string
+	Moves    []Move
+	Result   string
+}
+
+func (game Game) Tag(name string) (value string) {
+	for _, tag := range game.Tags {
+		if tag.Name == name {
+			return tag.Value
+		}
+	}
+	return
+}
+
+// Mainline renders the mainline SAN moves with their move numbers and the result, without tags, comments, NAGs or variations.
+func (game Game) Mainline() string {
+	tokens := make([]string, 0, len(game.Moves)*3/2+1)
+	for i, move := range game.Moves {
+		switch {
+		case move.Color == White:
+			tokens = append(tokens, strconv.Itoa(move.Number)+".", move.San)
+		case i == 0:
+			tokens = append(tokens, strconv.Itoa(move.Number)+"...", move.San)
+		default:
+			tokens = append(tokens, move.San)
+		}
+	}
+	if game.Result != "" {
+		tokens = append(tokens, game.Result)
+	}
+	return strings.Join(tokens, " ")
+}
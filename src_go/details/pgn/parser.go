@@ -0,0 +1,263 @@
+package pgn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse parses all the games of the PGN.
+func Parse(input string) (games []Game, err error) {
+	parser, err := newParser(input)
+	if err != nil {
+		return
+	}
+	for parser.token.Kind != EndOfInput {
+		var game Game
+		game, err = parser.game()
+		if err != nil {
+			return
+		}
+		games = append(games, game)
+	}
+	return
+}
+
+// ParseGame parses the PGN of a single game, anything after the first game is an error.
+func ParseGame(input string) (game Game, err error) {
+	parser, err := newParser(input)
+	if err != nil {
+		return
+	}
+	game, err = parser.game()
+	if err != nil {
+		return
+	}
+	if parser.token.Kind != EndOfInput {
+		err = parser.errorf("unexpected %s after the end of the game", parser.token.Kind)
+	}
+	return
+}
+
+type gameParser struct {
+	tokenizer *Tokenizer
+	token     Token
+}
+
+// moveCounter is the number and the color of the next move.
+type moveCounter struct {
+	number int
+	color  Color
+}
+
+func (counter moveCounter) next() moveCounter {
+	if counter.color == White {
+		return moveCounter{number: counter.number, color: Black}
+	}
+	return moveCounter{number: counter.number + 1, color: White}
+}
+
+func newParser(input string) (parser *gameParser, err error) {
+	parser = &gameParser{tokenizer: NewTokenizer(input)}
+	err = parser.advance()
+	return
+}
+
+func (parser *gameParser) advance() (err error) {
+	parser.token, err = parser.tokenizer.Next()
+	return
+}
+
+func (parser *gameParser) expect(kind TokenKind) (token Token, err error) {
+	token = parser.token
+	if token.Kind != kind {
+		err = parser.errorf("expected %s, got %s", kind, token.Kind)
+		return
+	}
+	err = parser.advance()
+	return
+}
+
+func (parser *gameParser) errorf(format string, arguments ...any) error {
+	return SyntaxError{Line: parser.token.Line, Column: parser.token.Column, Message: fmt.Sprintf(format, arguments...)}
+}
+
+func (parser *gameParser) game() (game Game, err error) {
+	for parser.token.Kind == LeftBracket {
+		var tag Tag
+		tag, err = parser.tag()
+		if err != nil {
+			return
+		}
+		game.Tags = append(game.Tags, tag)
+	}
+
+	for parser.token.Kind == Comment {
+		game.Comments = append(game.Comments, parser.token.Value)
+		err = parser.advance()
+		if err != nil {
+			return
+		}
+	}
+
+	game.Moves, game.Result, err = parser.moves(startingCounter(game.Tag("FEN")), false)
+	return
+}
+
+func (parser *gameParser) tag() (tag Tag, err error) {
+	_, err = parser.expect(LeftBracket)
+	if err != nil {
+		return
+	}
+	name, err := parser.expect(Symbol)
+	if err != nil {
+		return
+	}
+	value, err := parser.expect(String)
+	if err != nil {
+		return
+	}
+	_, err = parser.expect(RightBracket)
+	if err != nil {
+		return
+	}
+	tag = Tag{Name: name.Value, Value: value.Value}
+	return
+}
+
+// moves parses a movetext, the main one or a variation. The main one ends with a result, the tags of the next game or the end of the input,
+// a variation ends with its closing parenthesis.
+// The comments before the first move of a variation are kept with that move.
+func (parser *gameParser) moves(counter moveCounter, isVariation bool) (moves []Move, result string, err error) {
+	leadingComments := []string{}
+	for {
+		token := parser.token
+		switch token.Kind {
+		case EndOfInput, LeftBracket:
+			if isVariation {
+				err = parser.errorf("unterminated variation")
+			}
+			return
+		case RightParenthesis:
+			if !isVariation {
+				err = parser.errorf("unexpected ')'")
+				return
+			}
+			err = parser.advance()
+			return
+		case Asterisk:
+			result = "*"
+			err = parser.advance()
+			if err != nil || !isVariation {
+				return
+			}
+		case Period:
+			err = parser.advance()
+		case Symbol:
+			switch {
+			case isResult(token.Value):
+				result = token.Value
+				err = parser.advance()
+				if err != nil || !isVariation {
+					return
+				}
+			case isMoveNumber(token.Value):
+				counter, err = parser.moveNumber()
+			default:
+				move := Move{Number: counter.number, Color: counter.color, San: token.Value}
+				if len(moves) == 0 && len(leadingComments) > 0 {
+					move.Comments = leadingComments
+				}
+				moves = append(moves, move)
+				counter = counter.next()
+				err = parser.advance()
+			}
+		case Nag, Comment:
+			if len(moves) == 0 && token.Kind == Comment {
+				leadingComments = append(leadingComments, token.Value)
+				err = parser.advance()
+				break
+			}
+			if len(moves) == 0 {
+				err = parser.errorf("%s before the first move", token.Kind)
+				return
+			}
+			lastMove := &moves[len(moves)-1]
+			if token.Kind == Nag {
+				nag, _ := strconv.Atoi(token.Value)
+				lastMove.Nags = append(lastMove.Nags, nag)
+			} else {
+				lastMove.Comments = append(lastMove.Comments, token.Value)
+			}
+			err = parser.advance()
+		case LeftParenthesis:
+			if len(moves) == 0 {
+				err = parser.errorf("variation before the first move")
+				return
+			}
+			lastMove := &moves[len(moves)-1]
+			err = parser.advance()
+			if err != nil {
+				return
+			}
+			var variation []Move
+			variation, _, err = parser.moves(moveCounter{number: lastMove.Number, color: lastMove.Color}, true)
+			lastMove.Variations = append(lastMove.Variations, variation)
+		default:
+			err = parser.errorf("unexpected %s in the movetext", token.Kind)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// moveNumber reads a move number indication, "12." announces a move of white and "12..." a move of black.
+func (parser *gameParser) moveNumber() (counter moveCounter, err error) {
+	counter.number, _ = strconv.Atoi(parser.token.Value)
+	err = parser.advance()
+	if err != nil {
+		return
+	}
+	periods := 0
+	for parser.token.Kind == Period {
+		periods++
+		err = parser.advance()
+		if err != nil {
+			return
+		}
+	}
+	if periods >= 2 {
+		counter.color = Black
+	}
+	return
+}
+
+// startingCounter reads the side to move and the move number from the FEN of the game, a game without FEN starts with 1. of white.
+func startingCounter(fen string) (counter moveCounter) {
+	counter = moveCounter{number: 1, color: White}
+	fields := strings.Fields(fen)
+	if len(fields) < 6 {
+		return
+	}
+	if fields[1] == "b" {
+		counter.color = Black
+	}
+	if number, err := strconv.Atoi(fields[5]); err == nil && number > 0 {
+		counter.number = number
+	}
+	return
+}
+
+func isResult(symbol string) bool {
+	return symbol == "1-0" || symbol == "0-1" || symbol == "1/2-1/2"
+}
+
+func isMoveNumber(symbol string) bool {
+	for i := 0; i < len(symbol); i++ {
+		if !isDigit(symbol[i]) {
+			return false
+		}
+	}
+	return true
+}
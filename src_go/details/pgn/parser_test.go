@@ -0,0 +1,103 @@
+package pgn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseGame_should_keep_the_annotations_and_variations_apart_from_the_mainline(t *testing.T) {
+	input := `[Event "Live Chess"]
+[White "N-60"]
+[Black "tigran-c-137"]
+[Result "0-1"]
+
+{Opening comment} 1. e4 {[%clk 0:04:59.9]} 1... e5 $1 2. Nf3 (2. f4 exf4 (2... d5 {the counter gambit}) 3. Nf3) 2... Nc6?! ; a line comment
+3. Bb5 a6 0-1`
+
+	actualGame, err := ParseGame(input)
+	assert.NoError(t, err)
+
+	expectedGame := Game{
+		Tags: []Tag{
+			{Name: "Event", Value: "Live Chess"},
+			{Name: "White", Value: "N-60"},
+			{Name: "Black", Value: "tigran-c-137"},
+			{Name: "Result", Value: "0-1"},
+		},
+		Comments: []string{"Opening comment"},
+		Moves: []Move{
+			{Number: 1, Color: White, San: "e4", Comments: []string{"[%clk 0:04:59.9]"}},
+			{Number: 1, Color: Black, San: "e5", Nags: []int{1}},
+			{
+				Number: 2, Color: White, San: "Nf3",
+				Variations: [][]Move{{
+					{Number: 2, Color: White, San: "f4"},
+					{
+						Number: 2, Color: Black, San: "exf4",
+						Variations: [][]Move{{
+							{Number: 2, Color: Black, San: "d5", Comments: []string{"the counter gambit"}},
+						}},
+					},
+					{Number: 3, Color: White, San: "Nf3"},
+				}},
+			},
+			{Number: 2, Color: Black, San: "Nc6", Nags: []int{6}, Comments: []string{"a line comment"}},
+			{Number: 3, Color: White, San: "Bb5"},
+			{Number: 3, Color: Black, San: "a6"},
+		},
+		Result: "0-1",
+	}
+	assert.Equal(t, expectedGame, actualGame)
+	assert.Equal(t, "tigran-c-137", actualGame.Tag("Black"))
+	assert.Equal(t, "1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 0-1", actualGame.Mainline())
+}
+
+func Test_ParseGame_should_count_the_moves_from_the_fen(t *testing.T) {
+	input := `[FEN "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3"]
+[SetUp "1"]
+
+3. Bb5 a6 4. Ba4 *`
+
+	actualGame, err := ParseGame(input)
+	assert.NoError(t, err)
+	assert.Equal(t, "3. Bb5 a6 4. Ba4 *", actualGame.Mainline())
+
+	input = `[FEN "r1bqkbnr/pppp1ppp/2n5/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R b KQkq - 3 3"]
+
+a6 Ba4 Nf6 *`
+
+	actualGame, err = ParseGame(input)
+	assert.NoError(t, err)
+	assert.Equal(t, "3... a6 4. Ba4 Nf6 *", actualGame.Mainline())
+}
+
+func Test_Parse_should_split_a_file_into_games(t *testing.T) {
+	input := `[Event "First"]
+
+1. e4 e5 1-0
+
+[Event "Second"]
+
+1. d4 d5 1/2-1/2
+[Event "Third"]
+
+1. c4`
+
+	actualGames, err := Parse(input)
+	assert.NoError(t, err)
+
+	actualMainlines := []string{}
+	for _, game := range actualGames {
+		actualMainlines = append(actualMainlines, game.Tag("Event")+": "+game.Mainline())
+	}
+	assert.Equal(t, []string{"First: 1. e4 e5 1-0", "Second: 1. d4 d5 1/2-1/2", "Third: 1. c4"}, actualMainlines)
+}
+
+func Test_ParseGame_should_fail_on_unbalanced_variations(t *testing.T) {
+	_, err := ParseGame("1. e4 e5 (1... c5 2. Nf3 1-0")
+	assert.Equal(t, SyntaxError{Line: 1, Column: 29, Message: "unterminated variation"}, err)
+
+	_, err = ParseGame("1. e4 e5) 1-0")
+	assert.Equal(t, SyntaxError{Line: 1, Column: 9, Message: "unexpected ')'"}, err)
+}
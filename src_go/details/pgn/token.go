@@ -0,0 +1,292 @@
+package pgn
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TokenKind int
+
+const (
+	EndOfInput TokenKind = iota
+	LeftBracket
+	RightBracket
+	LeftParenthesis
+	RightParenthesis
+	Period
+	Asterisk
+	String
+	Symbol
+	Nag
+	Comment
+)
+
+func (kind TokenKind) String() string {
+	switch kind {
+	case EndOfInput:
+		return "end of input"
+	case LeftBracket:
+		return "'['"
+	case RightBracket:
+		return "']'"
+	case LeftParenthesis:
+		return "'('"
+	case RightParenthesis:
+		return "')'"
+	case Period:
+		return "'.'"
+	case Asterisk:
+		return "'*'"
+	case String:
+		return "string"
+	case Symbol:
+		return "symbol"
+	case Nag:
+		return "NAG"
+	case Comment:
+		return "comment"
+	}
+	return fmt.Sprintf("token %d", int(kind))
+}
+
+// Token is a lexical unit of PGN. Value is the unescaped content of strings and comments,
+// the symbol itself and the number of NAGs, suffix annotations like "!?" being turned into their NAGs.
+type Token struct {
+	Kind   TokenKind
+	Value  string
+	Line   int
+	Column int
+}
+
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err SyntaxError) Error() string {
+	return fmt.Sprintf("pgn syntax error at %d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+var suffixAnnotations = map[string]string{
+	"!":  "1",
+	"?":  "2",
+	"!!": "3",
+	"??": "4",
+	"!?": "5",
+	"?!": "6",
+}
+
+// Tokenizer splits PGN into tokens. Escape lines, the lines starting with '%', are skipped.
+type Tokenizer struct {
+	input  string
+	offset int
+	line   int
+	column int
+}
+
+func NewTokenizer(input string) *Tokenizer {
+	return &Tokenizer{input: input, line: 1, column: 1}
+}
+
+// Tokenize returns all the tokens of the input, the last one being EndOfInput.
+func Tokenize(input string) (tokens []Token, err error) {
+	tokenizer := NewTokenizer(input)
+	for {
+		var token Token
+		token, err = tokenizer.Next()
+		if err != nil {
+			return
+		}
+		tokens = append(tokens, token)
+		if token.Kind == EndOfInput {
+			return
+		}
+	}
+}
+
+func (tokenizer *Tokenizer) Next() (token Token, err error) {
+	tokenizer.skipWhitespaceAndEscapes()
+
+	token = Token{Line: tokenizer.line, Column: tokenizer.column}
+	if tokenizer.offset >= len(tokenizer.input) {
+		token.Kind = EndOfInput
+		return
+	}
+
+	symbol := tokenizer.input[tokenizer.offset]
+	switch {
+	case symbol == '[':
+		token.Kind = LeftBracket
+		tokenizer.advance(1)
+	case symbol == ']':
+		token.Kind = RightBracket
+		tokenizer.advance(1)
+	case symbol == '(':
+		token.Kind = LeftParenthesis
+		tokenizer.advance(1)
+	case symbol == ')':
+		token.Kind = RightParenthesis
+		tokenizer.advance(1)
+	case symbol == '.':
+		token.Kind = Period
+		tokenizer.advance(1)
+	case symbol == '*':
+		token.Kind = Asterisk
+		tokenizer.advance(1)
+	case symbol == '"':
+		token.Kind = String
+		token.Value, err = tokenizer.readString()
+	case symbol == '{':
+		token.Kind = Comment
+		token.Value, err = tokenizer.readBraceComment()
+	case symbol == ';':
+		token.Kind = Comment
+		token.Value = tokenizer.readLineComment()
+	case symbol == '$':
+		token.Kind = Nag
+		token.Value, err = tokenizer.readNag()
+	case symbol == '!' || symbol == '?':
+		token.Kind = Nag
+		token.Value, err = tokenizer.readSuffixAnnotation()
+	case isSymbolStart(symbol):
+		token.Kind = Symbol
+		token.Value = tokenizer.readSymbol()
+	default:
+		err = tokenizer.errorf("unexpected character %q", symbol)
+	}
+	return
+}
+
+func (tokenizer *Tokenizer) skipWhitespaceAndEscapes() {
+	for tokenizer.offset < len(tokenizer.input) {
+		symbol := tokenizer.input[tokenizer.offset]
+		switch {
+		case symbol == '%' && tokenizer.column == 1:
+			end := strings.IndexByte(tokenizer.input[tokenizer.offset:], '\n')
+			if end < 0 {
+				end = len(tokenizer.input) - tokenizer.offset
+			}
+			tokenizer.advance(end)
+		case symbol == ' ' || symbol == '\t' || symbol == '\r' || symbol == '\n' || symbol == '\v' || symbol == '\f':
+			tokenizer.advance(1)
+		default:
+			return
+		}
+	}
+}
+
+func (tokenizer *Tokenizer) readString() (value string, err error) {
+	line, column := tokenizer.line, tokenizer.column
+	tokenizer.advance(1)
+	builder := strings.Builder{}
+	for tokenizer.offset < len(tokenizer.input) {
+		symbol := tokenizer.input[tokenizer.offset]
+		switch {
+		case symbol == '"':
+			tokenizer.advance(1)
+			value = builder.String()
+			return
+		case symbol == '\\' && tokenizer.offset+1 < len(tokenizer.input) && strings.IndexByte(`"\`, tokenizer.input[tokenizer.offset+1]) >= 0:
+			builder.WriteByte(tokenizer.input[tokenizer.offset+1])
+			tokenizer.advance(2)
+		case symbol == '\n':
+			err = SyntaxError{Line: line, Column: column, Message: "unterminated string"}
+			return
+		default:
+			builder.WriteByte(symbol)
+			tokenizer.advance(1)
+		}
+	}
+	err = SyntaxError{Line: line, Column: column, Message: "unterminated string"}
+	return
+}
+
+func (tokenizer *Tokenizer) readBraceComment() (value string, err error) {
+	line, column := tokenizer.line, tokenizer.column
+	end := strings.IndexByte(tokenizer.input[tokenizer.offset:], '}')
+	if end < 0 {
+		err = SyntaxError{Line: line, Column: column, Message: "unterminated comment"}
+		return
+	}
+	value = strings.TrimSpace(tokenizer.input[tokenizer.offset+1 : tokenizer.offset+end])
+	tokenizer.advance(end + 1)
+	return
+}
+
+func (tokenizer *Tokenizer) readLineComment() (value string) {
+	end := strings.IndexByte(tokenizer.input[tokenizer.offset:], '\n')
+	if end < 0 {
+		end = len(tokenizer.input) - tokenizer.offset
+	}
+	value = strings.TrimSpace(tokenizer.input[tokenizer.offset+1 : tokenizer.offset+end])
+	tokenizer.advance(end)
+	return
+}
+
+func (tokenizer *Tokenizer) readNag() (value string, err error) {
+	end := tokenizer.offset + 1
+	for end < len(tokenizer.input) && isDigit(tokenizer.input[end]) {
+		end++
+	}
+	if end == tokenizer.offset+1 {
+		err = tokenizer.errorf("NAG without a number")
+		return
+	}
+	value = tokenizer.input[tokenizer.offset+1 : end]
+	tokenizer.advance(end - tokenizer.offset)
+	return
+}
+
+func (tokenizer *Tokenizer) readSuffixAnnotation() (value string, err error) {
+	end := tokenizer.offset
+	for end < len(tokenizer.input) && (tokenizer.input[end] == '!' || tokenizer.input[end] == '?') {
+		end++
+	}
+	annotation := tokenizer.input[tokenizer.offset:end]
+	value, isKnown := suffixAnnotations[annotation]
+	if !isKnown {
+		err = tokenizer.errorf("unknown suffix annotation %q", annotation)
+		return
+	}
+	tokenizer.advance(end - tokenizer.offset)
+	return
+}
+
+func (tokenizer *Tokenizer) readSymbol() (value string) {
+	end := tokenizer.offset + 1
+	for end < len(tokenizer.input) && isSymbolContinuation(tokenizer.input[end]) {
+		end++
+	}
+	value = tokenizer.input[tokenizer.offset:end]
+	tokenizer.advance(end - tokenizer.offset)
+	return
+}
+
+func (tokenizer *Tokenizer) advance(bytes int) {
+	for _, symbol := range []byte(tokenizer.input[tokenizer.offset : tokenizer.offset+bytes]) {
+		if symbol == '\n' {
+			tokenizer.line++
+			tokenizer.column = 1
+		} else {
+			tokenizer.column++
+		}
+	}
+	tokenizer.offset += bytes
+}
+
+func (tokenizer *Tokenizer) errorf(format string, arguments ...any) error {
+	return SyntaxError{Line: tokenizer.line, Column: tokenizer.column, Message: fmt.Sprintf(format, arguments...)}
+}
+
+func isDigit(symbol byte) bool {
+	return symbol >= '0' && symbol <= '9'
+}
+
+func isSymbolStart(symbol byte) bool {
+	return isDigit(symbol) || (symbol >= 'a' && symbol <= 'z') || (symbol >= 'A' && symbol <= 'Z')
+}
+
+func isSymbolContinuation(symbol byte) bool {
+	return isSymbolStart(symbol) || strings.IndexByte("_+#=:-/", symbol) >= 0
+}
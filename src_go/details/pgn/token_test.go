@@ -0,0 +1,35 @@
+package pgn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tokenize_should_split_pgn_into_tokens(t *testing.T) {
+	input := "[Event \"Live \\\"Chess\\\"\"]\n%escaped line\n1. e4!? {best by test} e5 $1 ; line comment\n2... Nf6 (2... Nc6) *"
+
+	actualTokens, err := Tokenize(input)
+	assert.NoError(t, err)
+
+	actualKindsAndValues := [][2]string{}
+	for _, token := range actualTokens {
+		actualKindsAndValues = append(actualKindsAndValues, [2]string{token.Kind.String(), token.Value})
+	}
+
+	expectedKindsAndValues := [][2]string{
+		{"'['", ""}, {"symbol", "Event"}, {"string", `Live "Chess"`}, {"']'", ""},
+		{"symbol", "1"}, {"'.'", ""}, {"symbol", "e4"}, {"NAG", "5"}, {"comment", "best by test"},
+		{"symbol", "e5"}, {"NAG", "1"}, {"comment", "line comment"},
+		{"symbol", "2"}, {"'.'", ""}, {"'.'", ""}, {"'.'", ""}, {"symbol", "Nf6"},
+		{"'('", ""}, {"symbol", "2"}, {"'.'", ""}, {"'.'", ""}, {"'.'", ""}, {"symbol", "Nc6"}, {"')'", ""},
+		{"'*'", ""}, {"end of input", ""},
+	}
+	assert.Equal(t, expectedKindsAndValues, actualKindsAndValues)
+}
+
+func Test_Tokenize_should_point_at_the_unterminated_comment(t *testing.T) {
+	_, err := Tokenize("1. e4 e5\n2. Nf3 {the comment never ends")
+
+	assert.Equal(t, SyntaxError{Line: 2, Column: 8, Message: "unterminated comment"}, err)
+}
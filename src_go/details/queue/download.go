@@ -1,11 +1,17 @@
 package queue
 
+var DownloadGamesCodec = Codec{Type: DownloadGames, SchemaVersion: 1}
+
 type DownloadGamesCommand struct {
 	Username   string   `json:"username"`
 	UserId     string   `json:"userId"`
 	Platform   Platform `json:"platform"`
 	ArchiveId  string   `json:"archiveId"`
 	DownloadId string   `json:"downloadId"`
+
+	// Pgn carries a chunk of the games of an uploaded archive, FirstGame being the number of games before the chunk.
+	Pgn       string `json:"pgn,omitempty"`
+	FirstGame int    `json:"firstGame,omitempty"`
 }
 
 type Platform string
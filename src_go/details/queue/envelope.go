@@ -0,0 +1,103 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type CommandType string
+
+const (
+	DownloadGames CommandType = "DOWNLOAD_GAMES"
+	SearchBoard   CommandType = "SEARCH_BOARD"
+)
+
+// LegacySchemaVersion is the version of the commands published before the envelope, as bare JSON.
+const LegacySchemaVersion = 1
+
+// Envelope is the body of every message, the command being its payload.
+// CorrelationId is the id of the request that published the command.
+type Envelope struct {
+	Type          CommandType     `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	CorrelationId string          `json:"correlationId,omitempty"`
+	EnqueuedAt    time.Time       `json:"enqueuedAt"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Upgrade turns the payload of a schema version into the payload of the next one.
+type Upgrade func(payload json.RawMessage) (upgraded json.RawMessage, err error)
+
+// Codec puts the commands of a type into envelopes of SchemaVersion.
+// Decode upgrades the payloads of older versions one version at a time, Upgrades[version] going from version to version + 1,
+// so the messages still in the queue across a deploy are read by the new code.
+type Codec struct {
+	Type          CommandType
+	SchemaVersion int
+	Upgrades      map[int]Upgrade
+}
+
+func (codec Codec) Encode(correlationId string, command any) (body string, err error) {
+	payload, err := json.Marshal(command)
+	if err != nil {
+		return
+	}
+	envelope, err := json.Marshal(Envelope{
+		Type:          codec.Type,
+		SchemaVersion: codec.SchemaVersion,
+		CorrelationId: correlationId,
+		EnqueuedAt:    time.Now().UTC(),
+		Payload:       payload,
+	})
+	if err != nil {
+		return
+	}
+	body = string(envelope)
+	return
+}
+
+// Decode reads the body into the command and returns its envelope.
+// A body without an envelope is taken for the payload of a command of LegacySchemaVersion.
+// A command of a version newer than the one of the codec is retryable, a consumer of the newer version may process it.
+// Any other error is permanent.
+func (codec Codec) Decode(body string, command any) (envelope Envelope, err error) {
+	err = json.Unmarshal([]byte(body), &envelope)
+	if err != nil {
+		err = Permanent(err)
+		return
+	}
+
+	if envelope.Type == "" && envelope.Payload == nil {
+		envelope = Envelope{Type: codec.Type, SchemaVersion: LegacySchemaVersion, Payload: json.RawMessage(body)}
+	}
+
+	if envelope.Type != codec.Type {
+		err = Permanent(fmt.Errorf("command of type %s instead of %s", envelope.Type, codec.Type))
+		return
+	}
+	if envelope.SchemaVersion > codec.SchemaVersion {
+		err = Retryable(fmt.Errorf("%s command of schema version %d newer than %d", envelope.Type, envelope.SchemaVersion, codec.SchemaVersion))
+		return
+	}
+
+	payload := envelope.Payload
+	for version := envelope.SchemaVersion; version < codec.SchemaVersion; version++ {
+		upgrade, upgradeExists := codec.Upgrades[version]
+		if !upgradeExists {
+			err = Permanent(fmt.Errorf("no upgrade of %s commands from schema version %d", envelope.Type, version))
+			return
+		}
+		payload, err = upgrade(payload)
+		if err != nil {
+			err = Permanent(fmt.Errorf("impossible to upgrade the %s command from schema version %d: %w", envelope.Type, version, err))
+			return
+		}
+	}
+
+	err = json.Unmarshal(payload, command)
+	if err != nil {
+		err = Permanent(err)
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type GreetCommandV2 struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+var greetCodec = Codec{
+	Type:          "GREET",
+	SchemaVersion: 2,
+	Upgrades: map[int]Upgrade{
+		1: func(payload json.RawMessage) (upgraded json.RawMessage, err error) {
+			commandV1 := struct {
+				Name string `json:"name"`
+			}{}
+			err = json.Unmarshal(payload, &commandV1)
+			if err != nil {
+				return
+			}
+			return json.Marshal(GreetCommandV2{FirstName: commandV1.Name})
+		},
+	},
+}
+
+func Test_Codec_should_decode_the_command_it_encoded(t *testing.T) {
+	command := DownloadGamesCommand{Username: "tigran-c-137", UserId: "1", Platform: ChessDotCom, ArchiveId: "2023-10", DownloadId: "42"}
+
+	body, err := DownloadGamesCodec.Encode("request-1", command)
+	assert.NoError(t, err)
+
+	actualCommand := DownloadGamesCommand{}
+	envelope, err := DownloadGamesCodec.Decode(body, &actualCommand)
+	assert.NoError(t, err)
+	assert.Equal(t, command, actualCommand)
+	assert.Equal(t, DownloadGames, envelope.Type)
+	assert.Equal(t, 1, envelope.SchemaVersion)
+	assert.Equal(t, "request-1", envelope.CorrelationId)
+	assert.WithinDuration(t, time.Now(), envelope.EnqueuedAt, time.Minute)
+}
+
+func Test_Codec_should_decode_the_commands_without_an_envelope_as_legacy_ones(t *testing.T) {
+	actualCommand := SearchBoardCommand{}
+	envelope, err := SearchBoardCodec.Decode(`{"searchId":"42","board":"????????/????????/????????/????????/????????/????????/????????/????????","userId":"1"}`, &actualCommand)
+
+	assert.NoError(t, err)
+	assert.Equal(t, SearchBoardCommand{SearchId: "42", Board: "????????/????????/????????/????????/????????/????????/????????/????????", UserId: "1"}, actualCommand)
+	assert.Equal(t, SearchBoard, envelope.Type)
+	assert.Equal(t, LegacySchemaVersion, envelope.SchemaVersion)
+	assert.Empty(t, envelope.CorrelationId)
+}
+
+func Test_Codec_should_upgrade_the_commands_of_older_schema_versions(t *testing.T) {
+	actualCommand := GreetCommandV2{}
+	_, err := greetCodec.Decode(`{"type":"GREET","schemaVersion":1,"payload":{"name":"rick"}}`, &actualCommand)
+	assert.NoError(t, err)
+	assert.Equal(t, GreetCommandV2{FirstName: "rick"}, actualCommand)
+
+	actualCommand = GreetCommandV2{}
+	_, err = greetCodec.Decode(`{"name":"morty"}`, &actualCommand)
+	assert.NoError(t, err)
+	assert.Equal(t, GreetCommandV2{FirstName: "morty"}, actualCommand)
+}
+
+func Test_Codec_should_retry_the_commands_of_newer_schema_versions(t *testing.T) {
+	_, err := greetCodec.Decode(`{"type":"GREET","schemaVersion":3,"payload":{"fullName":"rick sanchez"}}`, &GreetCommandV2{})
+
+	assert.True(t, errors.As(err, &RetryableError{}))
+}
+
+func Test_Codec_should_fail_permanently_on_the_commands_it_cannot_read(t *testing.T) {
+	_, err := greetCodec.Decode(`{"type":"SEARCH_BOARD","schemaVersion":1,"payload":{}}`, &GreetCommandV2{})
+	assert.True(t, IsPermanent(err))
+
+	_, err = greetCodec.Decode(`not a command`, &GreetCommandV2{})
+	assert.True(t, IsPermanent(err))
+
+	_, err = Codec{Type: "GREET", SchemaVersion: 2}.Decode(`{"type":"GREET","schemaVersion":1,"payload":{}}`, &GreetCommandV2{})
+	assert.True(t, IsPermanent(err))
+}
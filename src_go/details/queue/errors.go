@@ -0,0 +1,65 @@
+package queue
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RetryableError is a failure that may not happen again, the message is left to SQS to deliver it once more.
+// The errors that are neither retryable nor permanent are retried as well.
+type RetryableError struct {
+	Err error
+}
+
+func (err RetryableError) Error() string {
+	return err.Err.Error()
+}
+
+func (err RetryableError) Unwrap() error {
+	return err.Err
+}
+
+// PermanentError is a failure that happens on every delivery of the message, such as a malformed command.
+// The message is moved to the dead-letter queue with the error as its reason.
+type PermanentError struct {
+	Err error
+}
+
+func (err PermanentError) Error() string {
+	return err.Err.Error()
+}
+
+func (err PermanentError) Unwrap() error {
+	return err.Err
+}
+
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return RetryableError{Err: err}
+}
+
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return PermanentError{Err: err}
+}
+
+func IsPermanent(err error) bool {
+	return errors.As(err, &PermanentError{})
+}
+
+// IsLastDelivery tells whether SQS gives up on the message if it fails once more,
+// maxReceiveCount being the one of the redrive policy of the queue.
+// Without a known receive count or redrive policy it is never the last one, the redrive policy decides.
+func IsLastDelivery(message *events.SQSMessage, maxReceiveCount int) bool {
+	receiveCount, err := strconv.Atoi(message.Attributes["ApproximateReceiveCount"])
+	if err != nil || maxReceiveCount <= 0 {
+		return false
+	}
+	return receiveCount >= maxReceiveCount
+}
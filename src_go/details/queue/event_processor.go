@@ -1,34 +1,175 @@
 package queue
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
 	"go.uber.org/zap"
 )
 
 type EventProcessor interface {
-	ProcessSingle(event *events.SQSMessage, logger *zap.Logger) (eventFailure *events.SQSBatchItemFailure, err error)
+	// ProcessSingle returns a PermanentError for the messages that no delivery can process, any other error is retried.
+	// The context carries the span of the message, a child of the span that published it.
+	ProcessSingle(ctx context.Context, event *events.SQSMessage, logger *zap.Logger) (err error)
 }
 
+// Outcome is what became of a message, it is logged for every message of a batch.
+type Outcome string
+
+const (
+	Succeeded    Outcome = "SUCCEEDED"
+	Retried      Outcome = "RETRIED"
+	DeadLettered Outcome = "DEAD_LETTERED"
+	// Skipped are the messages after a failed one of the same group, they are retried without being processed.
+	Skipped Outcome = "SKIPPED"
+)
+
+// FailureReasonAttribute is the message attribute of a dead letter with the error of its last processing.
+const FailureReasonAttribute = "FailureReason"
+
 func ProcessMultiple(
 	sqsEvents events.SQSEvent,
 	eventProcessor EventProcessor,
 	logger *zap.Logger,
 ) (commandsProcessed events.SQSEventResponse) {
+	return BatchProcessor{}.Process(sqsEvents, eventProcessor, logger)
+}
 
-	logger.Info("Processing events in total", zap.Int("events", len(sqsEvents.Records)))
+// BatchProcessor processes up to Concurrency message groups at once, the messages of a group one by one in their order.
+// Once a message of a group fails, the rest of the group is reported as failed without being processed,
+// so that SQS redelivers them in the same order. The messages without a group are groups on their own.
+// The event processor must be safe for concurrent use when Concurrency is more than 1.
+//
+// The messages failed permanently are published to DeadLetters and acknowledged,
+// without DeadLetters they are only logged. The SQS trigger must report the batch item failures.
+type BatchProcessor struct {
+	Concurrency int
+	DeadLetters Publisher
+}
 
-	failures := []events.SQSBatchItemFailure{}
+func (processor BatchProcessor) Process(
+	sqsEvents events.SQSEvent,
+	eventProcessor EventProcessor,
+	logger *zap.Logger,
+) (commandsProcessed events.SQSEventResponse) {
+
+	concurrency := processor.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	logger.Info("Processing events in total", zap.Int("events", len(sqsEvents.Records)), zap.Int("concurrency", concurrency))
 
-	for _, events := range sqsEvents.Records {
-		eventFailure, errOfTheMessage := eventProcessor.ProcessSingle(&events, logger)
-		if errOfTheMessage != nil && eventFailure != nil {
-			failures = append(failures, *eventFailure)
+	outcomes := make([]Outcome, len(sqsEvents.Records))
+
+	workers := make(chan struct{}, concurrency)
+	var groups sync.WaitGroup
+	for _, group := range groupRecords(sqsEvents.Records) {
+		workers <- struct{}{}
+		groups.Add(1)
+		go func(group []int) {
+			defer func() {
+				<-workers
+				groups.Done()
+			}()
+			for i, record := range group {
+				outcomes[record] = processor.processSingle(&sqsEvents.Records[record], eventProcessor, logger)
+				if outcomes[record] == Retried {
+					for _, skippedRecord := range group[i+1:] {
+						outcomes[skippedRecord] = Skipped
+						logger.Info("Event processed", zap.String("messageId", sqsEvents.Records[skippedRecord].MessageId), zap.String("outcome", string(Skipped)))
+					}
+					return
+				}
+			}
+		}(group)
+	}
+	groups.Wait()
+
+	failures := []events.SQSBatchItemFailure{}
+	outcomeCounts := map[Outcome]int{}
+	for record, outcome := range outcomes {
+		outcomeCounts[outcome]++
+		if outcome == Retried || outcome == Skipped {
+			failures = append(failures, events.SQSBatchItemFailure{ItemIdentifier: sqsEvents.Records[record].MessageId})
 		}
 	}
 
+	logger.Info(
+		"Events processed",
+		zap.Int("succeeded", outcomeCounts[Succeeded]),
+		zap.Int("retried", outcomeCounts[Retried]),
+		zap.Int("deadLettered", outcomeCounts[DeadLettered]),
+		zap.Int("skipped", outcomeCounts[Skipped]),
+	)
+
 	if len(failures) > 0 {
 		logger.Error("Some events failed", zap.Int("eventFailures", len(failures)))
 		commandsProcessed.BatchItemFailures = failures
 	}
 	return
 }
+
+func (processor BatchProcessor) processSingle(message *events.SQSMessage, eventProcessor EventProcessor, logger *zap.Logger) (outcome Outcome) {
+	ctx, span := tracing.StartMessage(message)
+	logger = tracing.Logger(ctx, logger.With(zap.String("messageId", message.MessageId)))
+	startedAt := time.Now()
+	defer func() {
+		logger.Info("Event processed", zap.String("outcome", string(outcome)), zap.Duration("duration", time.Since(startedAt)))
+	}()
+
+	err := eventProcessor.ProcessSingle(ctx, message, logger)
+	tracing.End(span, err)
+	if err == nil {
+		return Succeeded
+	}
+
+	if !IsPermanent(err) {
+		logger.Warn("the event will be retried", zap.Error(err))
+		return Retried
+	}
+
+	logger.Error("the event failed permanently", zap.Error(err))
+	if processor.DeadLetters == nil {
+		return DeadLettered
+	}
+
+	groupId := message.Attributes["MessageGroupId"]
+	if groupId == "" {
+		groupId = message.MessageId
+	}
+	errOfDeadLetter := processor.DeadLetters.Publish(Message{
+		Body:            message.Body,
+		GroupId:         groupId,
+		DeduplicationId: message.MessageId,
+		Attributes:      map[string]string{FailureReasonAttribute: err.Error()},
+	})
+	if errOfDeadLetter != nil {
+		logger.Error("impossible to publish the dead letter, the event will be retried", zap.Error(errOfDeadLetter))
+		return Retried
+	}
+	return DeadLettered
+}
+
+// groupRecords returns the indexes of the records by their message group in the order of the first record of each group.
+func groupRecords(records []events.SQSMessage) (groups [][]int) {
+	groupIndexes := map[string]int{}
+	for record, message := range records {
+		groupId, groupIdExists := message.Attributes["MessageGroupId"]
+		if !groupIdExists || groupId == "" {
+			groups = append(groups, []int{record})
+			continue
+		}
+		groupIndex, groupIndexExists := groupIndexes[groupId]
+		if !groupIndexExists {
+			groupIndex = len(groups)
+			groupIndexes[groupId] = groupIndex
+			groups = append(groups, nil)
+		}
+		groups[groupIndex] = append(groups[groupIndex], record)
+	}
+	return
+}
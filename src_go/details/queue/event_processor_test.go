@@ -0,0 +1,147 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type RecordingProcessor struct {
+	failing   map[string]error
+	inFlight  atomic.Int32
+	maxFlight atomic.Int32
+	lock      sync.Mutex
+	processed map[string][]string
+}
+
+func (processor *RecordingProcessor) ProcessSingle(ctx context.Context, message *events.SQSMessage, logger *zap.Logger) (err error) {
+	inFlight := processor.inFlight.Add(1)
+	defer processor.inFlight.Add(-1)
+	for {
+		maxFlight := processor.maxFlight.Load()
+		if inFlight <= maxFlight || processor.maxFlight.CompareAndSwap(maxFlight, inFlight) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	processor.lock.Lock()
+	groupId := message.Attributes["MessageGroupId"]
+	processor.processed[groupId] = append(processor.processed[groupId], message.Body)
+	processor.lock.Unlock()
+
+	return processor.failing[message.Body]
+}
+
+type FailingPublisher struct{}
+
+func (publisher FailingPublisher) Publish(message Message) error {
+	return errors.New("the dead-letter queue is gone")
+}
+
+func message(messageId string, groupId string) events.SQSMessage {
+	return events.SQSMessage{MessageId: messageId, Body: messageId, Attributes: map[string]string{"MessageGroupId": groupId}}
+}
+
+func Test_BatchProcessor_should_keep_the_order_of_every_group_within_the_concurrency(t *testing.T) {
+	processor := &RecordingProcessor{processed: map[string][]string{}}
+	sqsEvent := events.SQSEvent{Records: []events.SQSMessage{
+		message("1", "tigran"), message("2", "rick"), message("3", "morty"), message("4", "tigran"),
+		message("5", "rick"), message("6", "summer"), message("7", "tigran"), message("8", "morty"),
+	}}
+
+	response := BatchProcessor{Concurrency: 3}.Process(sqsEvent, processor, zap.NewNop())
+
+	assert.Empty(t, response.BatchItemFailures)
+	assert.Equal(t, map[string][]string{
+		"tigran": {"1", "4", "7"},
+		"rick":   {"2", "5"},
+		"morty":  {"3", "8"},
+		"summer": {"6"},
+	}, processor.processed)
+	assert.Equal(t, int32(3), processor.maxFlight.Load())
+}
+
+func Test_BatchProcessor_should_retry_the_rest_of_the_group_after_a_retryable_failure(t *testing.T) {
+	processor := &RecordingProcessor{processed: map[string][]string{}, failing: map[string]error{"4": Retryable(errors.New("throttled"))}}
+	sqsEvent := events.SQSEvent{Records: []events.SQSMessage{
+		message("1", "tigran"), message("2", "rick"), message("3", "tigran"), message("4", "tigran"),
+		message("5", "rick"), message("6", "tigran"),
+	}}
+
+	response := BatchProcessor{Concurrency: 10}.Process(sqsEvent, processor, zap.NewNop())
+
+	assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "4"}, {ItemIdentifier: "6"}}, response.BatchItemFailures)
+	assert.Equal(t, map[string][]string{
+		"tigran": {"1", "3", "4"},
+		"rick":   {"2", "5"},
+	}, processor.processed)
+}
+
+func Test_ProcessMultiple_should_process_the_messages_one_by_one(t *testing.T) {
+	processor := &RecordingProcessor{processed: map[string][]string{}}
+	sqsEvent := events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "1", Body: "1"}, {MessageId: "2", Body: "2"}, {MessageId: "3", Body: "3"},
+	}}
+
+	response := ProcessMultiple(sqsEvent, processor, zap.NewNop())
+
+	assert.Empty(t, response.BatchItemFailures)
+	assert.Equal(t, map[string][]string{"": {"1", "2", "3"}}, processor.processed)
+	assert.Equal(t, int32(1), processor.maxFlight.Load())
+}
+
+func Test_BatchProcessor_should_retry_the_untyped_failures(t *testing.T) {
+	processor := &RecordingProcessor{processed: map[string][]string{}, failing: map[string]error{"2": errors.New("connection reset")}}
+	sqsEvent := events.SQSEvent{Records: []events.SQSMessage{message("1", "tigran"), message("2", "rick")}}
+
+	response := BatchProcessor{}.Process(sqsEvent, processor, zap.NewNop())
+
+	assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "2"}}, response.BatchItemFailures)
+}
+
+func Test_BatchProcessor_should_move_the_permanent_failures_to_the_dead_letters_with_their_reason(t *testing.T) {
+	processor := &RecordingProcessor{processed: map[string][]string{}, failing: map[string]error{"1": Permanent(errors.New("malformed command"))}}
+	deadLetters := NewLocalQueue()
+	sqsEvent := events.SQSEvent{Records: []events.SQSMessage{message("1", "tigran"), message("2", "tigran")}}
+
+	response := BatchProcessor{DeadLetters: deadLetters}.Process(sqsEvent, processor, zap.NewNop())
+
+	assert.Empty(t, response.BatchItemFailures)
+	assert.Equal(t, map[string][]string{"tigran": {"1", "2"}}, processor.processed)
+
+	received, _, _ := deadLetters.receive(time.Now())
+	deadLetter := received.Records[0]
+	assert.Equal(t, "1", deadLetter.Body)
+	assert.Equal(t, "tigran", deadLetter.Attributes["MessageGroupId"])
+	assert.Equal(t, "malformed command", *deadLetter.MessageAttributes[FailureReasonAttribute].StringValue)
+}
+
+func Test_BatchProcessor_should_retry_the_permanent_failures_that_cannot_be_dead_lettered(t *testing.T) {
+	processor := &RecordingProcessor{processed: map[string][]string{}, failing: map[string]error{"1": Permanent(errors.New("malformed command"))}}
+	sqsEvent := events.SQSEvent{Records: []events.SQSMessage{message("1", "tigran"), message("2", "tigran")}}
+
+	response := BatchProcessor{DeadLetters: FailingPublisher{}}.Process(sqsEvent, processor, zap.NewNop())
+
+	assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "1"}, {ItemIdentifier: "2"}}, response.BatchItemFailures)
+}
+
+func Test_IsLastDelivery_should_compare_the_receive_count_with_the_redrive_policy(t *testing.T) {
+	delivery := events.SQSMessage{Attributes: map[string]string{"ApproximateReceiveCount": "2"}}
+
+	assert.False(t, IsLastDelivery(&delivery, 3))
+	assert.True(t, IsLastDelivery(&delivery, 2))
+}
+
+func Test_IsLastDelivery_should_leave_the_unknown_receive_counts_to_the_redrive_policy(t *testing.T) {
+	assert.False(t, IsLastDelivery(&events.SQSMessage{}, 3))
+	assert.False(t, IsLastDelivery(&events.SQSMessage{Attributes: map[string]string{"ApproximateReceiveCount": "many"}}, 3))
+	assert.False(t, IsLastDelivery(&events.SQSMessage{Attributes: map[string]string{"ApproximateReceiveCount": "5"}}, 0))
+}
@@ -0,0 +1,195 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/zap"
+)
+
+var _ Queue = &LocalQueue{}
+
+// DeduplicationInterval is how long a deduplication id drops the messages published with it, as in SQS.
+const DeduplicationInterval = 5 * time.Minute
+
+// LocalQueue is a FIFO queue in memory that hands the messages over to the consumers of the same process as SQS does:
+//   - the messages of a group are received in the order they were published, none of them while an earlier one is in flight;
+//   - a received message stays invisible for VisibilityTimeout and is received again after it, unless it is deleted;
+//   - a message received MaxReceiveCount times is moved to DeadLetters instead, or dropped without them,
+//     0 meaning that it is received until it is deleted;
+//   - a message with the deduplication id of a message published within DeduplicationInterval is dropped.
+//
+// Consume deletes the messages that are not batch item failures.
+type LocalQueue struct {
+	VisibilityTimeout time.Duration
+	BatchSize         int
+	MaxReceiveCount   int
+	DeadLetters       Publisher
+
+	lock             sync.Mutex
+	messages         []*localMessage
+	deduplicationIds map[string]time.Time
+	published        chan struct{}
+	lastMessageId    int64
+}
+
+type localMessage struct {
+	Message
+	messageId    string
+	receiveCount int
+	visibleAt    time.Time
+}
+
+func NewLocalQueue() *LocalQueue {
+	return &LocalQueue{
+		VisibilityTimeout: 30 * time.Second,
+		BatchSize:         10,
+		deduplicationIds:  map[string]time.Time{},
+		published:         make(chan struct{}, 1),
+	}
+}
+
+func (queue *LocalQueue) Publish(message Message) (err error) {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	now := time.Now()
+	for deduplicationId, publishedAt := range queue.deduplicationIds {
+		if now.Sub(publishedAt) >= DeduplicationInterval {
+			delete(queue.deduplicationIds, deduplicationId)
+		}
+	}
+	if message.DeduplicationId != "" {
+		if _, isDuplicate := queue.deduplicationIds[message.DeduplicationId]; isDuplicate {
+			return
+		}
+		queue.deduplicationIds[message.DeduplicationId] = now
+	}
+
+	queue.lastMessageId++
+	queue.messages = append(queue.messages, &localMessage{Message: message, messageId: strconv.FormatInt(queue.lastMessageId, 10)})
+
+	select {
+	case queue.published <- struct{}{}:
+	default:
+	}
+	return
+}
+
+func (queue *LocalQueue) Consume(ctx context.Context, handler Handler, logger *zap.Logger) {
+	for ctx.Err() == nil {
+		sqsEvent, deadLetters, nextVisibleAt := queue.receive(time.Now())
+
+		for _, deadLetter := range deadLetters {
+			if queue.DeadLetters == nil {
+				logger.Warn("the message was received too many times, it is dropped", zap.String("messageId", deadLetter.messageId))
+				continue
+			}
+			err := queue.DeadLetters.Publish(deadLetter.Message)
+			if err != nil {
+				logger.Error("impossible to move the message to the dead letters, it is dropped", zap.String("messageId", deadLetter.messageId), zap.Error(err))
+			}
+		}
+
+		if len(sqsEvent.Records) == 0 {
+			wait := time.Hour
+			if !nextVisibleAt.IsZero() {
+				wait = time.Until(nextVisibleAt)
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+			case <-queue.published:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+
+		response, err := handler(sqsEvent)
+		if err != nil {
+			logger.Error("the batch failed, its messages will be received again", zap.Error(err))
+			continue
+		}
+		queue.delete(sqsEvent, response)
+	}
+}
+
+// receive takes the next batch and the messages to move to the dead letters.
+// Without any message to receive, it returns when the first message in flight is visible again.
+func (queue *LocalQueue) receive(now time.Time) (sqsEvent events.SQSEvent, deadLetters []*localMessage, nextVisibleAt time.Time) {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	groupsInFlight := map[string]bool{}
+	messages := queue.messages[:0]
+	for _, message := range queue.messages {
+		isInFlight := message.visibleAt.After(now)
+		if isInFlight && (nextVisibleAt.IsZero() || message.visibleAt.Before(nextVisibleAt)) {
+			nextVisibleAt = message.visibleAt
+		}
+		if isInFlight && message.GroupId != "" {
+			groupsInFlight[message.GroupId] = true
+		}
+		if isInFlight || groupsInFlight[message.GroupId] || len(sqsEvent.Records) >= queue.BatchSize {
+			messages = append(messages, message)
+			continue
+		}
+
+		if queue.MaxReceiveCount > 0 && message.receiveCount >= queue.MaxReceiveCount {
+			deadLetters = append(deadLetters, message)
+			continue
+		}
+
+		message.receiveCount++
+		message.visibleAt = now.Add(queue.VisibilityTimeout)
+		sqsEvent.Records = append(sqsEvent.Records, message.sqsMessage())
+		messages = append(messages, message)
+	}
+	clear(queue.messages[len(messages):])
+	queue.messages = messages
+	return
+}
+
+func (queue *LocalQueue) delete(sqsEvent events.SQSEvent, response events.SQSEventResponse) {
+	deleted := map[string]bool{}
+	for _, record := range sqsEvent.Records {
+		deleted[record.MessageId] = true
+	}
+	for _, failure := range response.BatchItemFailures {
+		delete(deleted, failure.ItemIdentifier)
+	}
+
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	messages := queue.messages[:0]
+	for _, message := range queue.messages {
+		if !deleted[message.messageId] {
+			messages = append(messages, message)
+		}
+	}
+	clear(queue.messages[len(messages):])
+	queue.messages = messages
+}
+
+func (message *localMessage) sqsMessage() events.SQSMessage {
+	messageAttributes := map[string]events.SQSMessageAttribute{}
+	for name, value := range message.Attributes {
+		messageAttributes[name] = events.SQSMessageAttribute{DataType: "String", StringValue: aws.String(value)}
+	}
+	return events.SQSMessage{
+		MessageId: message.messageId,
+		Body:      message.Body,
+		Attributes: map[string]string{
+			"MessageGroupId":          message.GroupId,
+			"MessageDeduplicationId":  message.DeduplicationId,
+			"ApproximateReceiveCount": strconv.Itoa(message.receiveCount),
+		},
+		MessageAttributes: messageAttributes,
+	}
+}
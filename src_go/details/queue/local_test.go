@@ -0,0 +1,130 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func bodies(sqsEvent events.SQSEvent) (bodies []string) {
+	for _, message := range sqsEvent.Records {
+		bodies = append(bodies, message.Body)
+	}
+	return
+}
+
+func Test_LocalQueue_should_hand_over_the_messages_in_the_order_they_were_published(t *testing.T) {
+	queue := NewLocalQueue()
+	for _, body := range []string{"first", "second", "third"} {
+		err := queue.Publish(Message{Body: body, GroupId: "tigran-c-137", DeduplicationId: body})
+		assert.NoError(t, err)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	consumed := []events.SQSMessage{}
+	queue.Consume(ctx, func(event events.SQSEvent) (response events.SQSEventResponse, err error) {
+		consumed = append(consumed, event.Records...)
+		if len(consumed) == 3 {
+			stop()
+		}
+		return
+	}, zap.NewNop())
+
+	assert.Equal(t, []string{"first", "second", "third"}, bodies(events.SQSEvent{Records: consumed}))
+	assert.Equal(t, "tigran-c-137", consumed[0].Attributes["MessageGroupId"])
+	assert.Equal(t, "3", consumed[2].MessageId)
+}
+
+func Test_LocalQueue_should_hold_back_a_group_while_one_of_its_messages_is_in_flight(t *testing.T) {
+	queue := NewLocalQueue()
+	queue.BatchSize = 1
+	queue.Publish(Message{Body: "tigran-1", GroupId: "tigran", DeduplicationId: "tigran-1"})
+	queue.Publish(Message{Body: "tigran-2", GroupId: "tigran", DeduplicationId: "tigran-2"})
+	queue.Publish(Message{Body: "rick-1", GroupId: "rick", DeduplicationId: "rick-1"})
+	now := time.Now()
+
+	tigran, _, _ := queue.receive(now)
+	assert.Equal(t, []string{"tigran-1"}, bodies(tigran))
+
+	rick, _, _ := queue.receive(now)
+	assert.Equal(t, []string{"rick-1"}, bodies(rick))
+
+	nothing, _, nextVisibleAt := queue.receive(now)
+	assert.Empty(t, nothing.Records)
+	assert.Equal(t, now.Add(queue.VisibilityTimeout), nextVisibleAt)
+
+	queue.delete(tigran, events.SQSEventResponse{})
+	tigran, _, _ = queue.receive(now)
+	assert.Equal(t, []string{"tigran-2"}, bodies(tigran))
+}
+
+func Test_LocalQueue_should_receive_the_failed_messages_again_after_the_visibility_timeout(t *testing.T) {
+	queue := NewLocalQueue()
+	queue.VisibilityTimeout = 20 * time.Millisecond
+	queue.BatchSize = 1
+	queue.Publish(Message{Body: "first", GroupId: "tigran", DeduplicationId: "first"})
+	queue.Publish(Message{Body: "second", GroupId: "tigran", DeduplicationId: "second"})
+
+	ctx, stop := context.WithTimeout(context.Background(), 5*time.Second)
+	defer stop()
+	consumed := []string{}
+	queue.Consume(ctx, func(event events.SQSEvent) (response events.SQSEventResponse, err error) {
+		for _, message := range event.Records {
+			consumed = append(consumed, message.Body+"#"+message.Attributes["ApproximateReceiveCount"])
+			if message.Body == "first" && message.Attributes["ApproximateReceiveCount"] == "1" {
+				response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: message.MessageId})
+			}
+			if message.Body == "second" {
+				stop()
+			}
+		}
+		return
+	}, zap.NewNop())
+
+	assert.Equal(t, []string{"first#1", "first#2", "second#1"}, consumed)
+}
+
+func Test_LocalQueue_should_move_the_messages_received_too_many_times_to_the_dead_letters(t *testing.T) {
+	deadLetters := NewLocalQueue()
+	queue := NewLocalQueue()
+	queue.MaxReceiveCount = 2
+	queue.BatchSize = 1
+	queue.DeadLetters = deadLetters
+	queue.Publish(Message{Body: "poison", GroupId: "tigran", DeduplicationId: "poison", Attributes: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}})
+	queue.Publish(Message{Body: "next", GroupId: "tigran", DeduplicationId: "next"})
+
+	ctx, stop := context.WithTimeout(context.Background(), 5*time.Second)
+	defer stop()
+	queue.VisibilityTimeout = time.Millisecond
+	receiveCounts := []string{}
+	queue.Consume(ctx, func(event events.SQSEvent) (response events.SQSEventResponse, err error) {
+		message := event.Records[0]
+		if message.Body == "next" {
+			stop()
+			return
+		}
+		receiveCounts = append(receiveCounts, message.Attributes["ApproximateReceiveCount"])
+		response.BatchItemFailures = []events.SQSBatchItemFailure{{ItemIdentifier: message.MessageId}}
+		return
+	}, zap.NewNop())
+
+	assert.Equal(t, []string{"1", "2"}, receiveCounts)
+	deadLetter, _, _ := deadLetters.receive(time.Now())
+	assert.Equal(t, []string{"poison"}, bodies(deadLetter))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", *deadLetter.Records[0].MessageAttributes["traceparent"].StringValue)
+}
+
+func Test_LocalQueue_should_drop_the_messages_with_the_deduplication_id_of_a_recent_one(t *testing.T) {
+	queue := NewLocalQueue()
+	queue.Publish(Message{Body: "first", GroupId: "tigran", DeduplicationId: "2023-10"})
+	queue.Publish(Message{Body: "again", GroupId: "tigran", DeduplicationId: "2023-10"})
+	queue.Publish(Message{Body: "second", GroupId: "tigran", DeduplicationId: "2023-11"})
+
+	received, _, _ := queue.receive(time.Now())
+
+	assert.Equal(t, []string{"first", "second"}, bodies(received))
+}
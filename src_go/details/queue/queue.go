@@ -0,0 +1,36 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+// Message is a command for a FIFO queue. The messages of a group are delivered in the order they were published,
+// a message with the deduplication id of a recent one is dropped. The attributes are string message attributes.
+type Message struct {
+	Body            string
+	GroupId         string
+	DeduplicationId string
+	Attributes      map[string]string
+}
+
+type Publisher interface {
+	Publish(message Message) error
+}
+
+// Handler processes a batch of messages as the handler of a Lambda with an SQS trigger does.
+// The messages reported as batch item failures are received again, the whole batch is if the error is not nil.
+type Handler func(sqsEvent events.SQSEvent) (events.SQSEventResponse, error)
+
+type Consumer interface {
+	// Consume passes the batches of messages to the handler until the context is done.
+	Consume(ctx context.Context, handler Handler, logger *zap.Logger)
+}
+
+// Queue is what the producers publish the commands to and the workers consume them from, SQS or a LocalQueue.
+type Queue interface {
+	Publisher
+	Consumer
+}
@@ -1,7 +1,23 @@
 package queue
 
+var SearchBoardCodec = Codec{Type: SearchBoard, SchemaVersion: 1}
+
 type SearchBoardCommand struct {
 	SearchId string `json:"searchId"`
 	Board    string `json:"board"`
 	UserId   string `json:"userId"`
+
+	Boards    []string `json:"boards,omitempty"`
+	MaxPlyGap int      `json:"maxPlyGap,omitempty"`
+
+	SideToMove string `json:"sideToMove,omitempty"`
+	Castling   string `json:"castling,omitempty"`
+	EnPassant  string `json:"enPassant,omitempty"`
+
+	EndedFrom int64  `json:"endedFrom,omitempty"`
+	EndedTo   int64  `json:"endedTo,omitempty"`
+	Color     string `json:"color,omitempty"`
+	Result    string `json:"result,omitempty"`
+	TimeClass string `json:"timeClass,omitempty"`
+	Opponent  string `json:"opponent,omitempty"`
 }
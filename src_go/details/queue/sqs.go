@@ -0,0 +1,123 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"go.uber.org/zap"
+)
+
+var _ Queue = SqsQueue{}
+
+// SqsQueue is a FIFO queue of SQS. The Lambdas are triggered by SQS itself, Consume is for the workers running elsewhere.
+type SqsQueue struct {
+	QueueUrl  string
+	SqsClient *sqs.SQS
+}
+
+func (queue SqsQueue) Publish(message Message) (err error) {
+	_, err = queue.SqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:               aws.String(queue.QueueUrl),
+		MessageBody:            aws.String(message.Body),
+		MessageDeduplicationId: aws.String(message.DeduplicationId),
+		MessageGroupId:         aws.String(message.GroupId),
+		MessageAttributes:      messageAttributes(message.Attributes),
+	})
+	return
+}
+
+// Consume long polls the queue and deletes the messages the handler succeeded with,
+// the others are received again once their visibility timeout is over.
+func (queue SqsQueue) Consume(ctx context.Context, handler Handler, logger *zap.Logger) {
+	logger = logger.With(zap.String("queueUrl", queue.QueueUrl))
+	for ctx.Err() == nil {
+		output, err := queue.SqsClient.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:              aws.String(queue.QueueUrl),
+			MaxNumberOfMessages:   aws.Int64(10),
+			WaitTimeSeconds:       aws.Int64(20),
+			AttributeNames:        []*string{aws.String(sqs.QueueAttributeNameAll)},
+			MessageAttributeNames: []*string{aws.String("All")},
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Error("impossible to receive the messages", zap.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		if len(output.Messages) == 0 {
+			continue
+		}
+
+		sqsEvent := events.SQSEvent{Records: make([]events.SQSMessage, len(output.Messages))}
+		for i, message := range output.Messages {
+			sqsEvent.Records[i] = sqsMessage(message)
+		}
+
+		response, err := handler(sqsEvent)
+		if err != nil {
+			logger.Error("the batch failed, its messages will be received again", zap.Error(err))
+			continue
+		}
+
+		failed := map[string]bool{}
+		for _, failure := range response.BatchItemFailures {
+			failed[failure.ItemIdentifier] = true
+		}
+		entries := []*sqs.DeleteMessageBatchRequestEntry{}
+		for _, message := range output.Messages {
+			if !failed[aws.StringValue(message.MessageId)] {
+				entries = append(entries, &sqs.DeleteMessageBatchRequestEntry{Id: message.MessageId, ReceiptHandle: message.ReceiptHandle})
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		deleted, err := queue.SqsClient.DeleteMessageBatch(&sqs.DeleteMessageBatchInput{QueueUrl: aws.String(queue.QueueUrl), Entries: entries})
+		if err != nil {
+			logger.Error("impossible to delete the messages, they will be received again", zap.Error(err))
+			continue
+		}
+		for _, failure := range deleted.Failed {
+			logger.Error("impossible to delete the message, it will be received again", zap.String("messageId", aws.StringValue(failure.Id)), zap.String("reason", aws.StringValue(failure.Message)))
+		}
+	}
+}
+
+func sqsMessage(message *sqs.Message) (sqsMessage events.SQSMessage) {
+	sqsMessage = events.SQSMessage{
+		MessageId:         aws.StringValue(message.MessageId),
+		ReceiptHandle:     aws.StringValue(message.ReceiptHandle),
+		Body:              aws.StringValue(message.Body),
+		Md5OfBody:         aws.StringValue(message.MD5OfBody),
+		Attributes:        aws.StringValueMap(message.Attributes),
+		MessageAttributes: map[string]events.SQSMessageAttribute{},
+		EventSource:       "aws:sqs",
+	}
+	for name, attribute := range message.MessageAttributes {
+		sqsMessage.MessageAttributes[name] = events.SQSMessageAttribute{
+			DataType:    aws.StringValue(attribute.DataType),
+			StringValue: attribute.StringValue,
+			BinaryValue: attribute.BinaryValue,
+		}
+	}
+	return
+}
+
+func messageAttributes(attributes map[string]string) (messageAttributes map[string]*sqs.MessageAttributeValue) {
+	if len(attributes) == 0 {
+		return
+	}
+	messageAttributes = make(map[string]*sqs.MessageAttributeValue, len(attributes))
+	for name, value := range attributes {
+		messageAttributes[name] = &sqs.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	return
+}
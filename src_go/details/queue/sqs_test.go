@@ -0,0 +1,58 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var awsConfig = aws.Config{
+	Region:     aws.String("us-east-1"),
+	Endpoint:   aws.String("http://localhost:4566"), // this is the LocalStack endpoint for all services
+	DisableSSL: aws.Bool(true),
+}
+
+var sqsClient = sqs.New(session.Must(session.NewSession(&awsConfig)))
+
+var searchBoardQueue = SqsQueue{
+	QueueUrl:  "http://localhost:4566/000000000000/chessfinder_sqs-SearchBoard.fifo",
+	SqsClient: sqsClient,
+}
+
+func Test_SqsQueue_should_consume_the_published_messages_and_delete_them(t *testing.T) {
+	_, err := GetLastNCommands(sqsClient, searchBoardQueue.QueueUrl, 10)
+	assert.NoError(t, err)
+
+	run := strconv.FormatInt(time.Now().UnixNano(), 10)
+	for _, body := range []string{"first", "second"} {
+		err = searchBoardQueue.Publish(Message{Body: body, GroupId: run, DeduplicationId: run + body, Attributes: map[string]string{"run": run}})
+		assert.NoError(t, err)
+	}
+
+	ctx, stop := context.WithTimeout(context.Background(), 30*time.Second)
+	defer stop()
+	consumed := []events.SQSMessage{}
+	searchBoardQueue.Consume(ctx, func(event events.SQSEvent) (response events.SQSEventResponse, err error) {
+		consumed = append(consumed, event.Records...)
+		if len(consumed) == 2 {
+			stop()
+		}
+		return
+	}, zap.NewNop())
+
+	assert.Equal(t, []string{"first", "second"}, bodies(events.SQSEvent{Records: consumed}))
+	assert.Equal(t, run, consumed[0].Attributes["MessageGroupId"])
+	assert.Equal(t, run, *consumed[0].MessageAttributes["run"].StringValue)
+
+	leftovers, err := GetLastNCommands(sqsClient, searchBoardQueue.QueueUrl, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
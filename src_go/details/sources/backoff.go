@@ -0,0 +1,72 @@
+package sources
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Backoff is how the clients of the platforms retry 429 Too Many Requests and 5xx answers:
+// a jittered exponential backoff, waiting at least as long as the Retry-After header asks to.
+// A 429 Too Many Requests without Retry-After is waited for at least TooManyRequestsDelay.
+type Backoff struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	TooManyRequestsDelay time.Duration
+	sleep                func(time.Duration)
+}
+
+func NewBackoff() Backoff {
+	return Backoff{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    8 * time.Second,
+	}
+}
+
+// NewLichessBackoff waits a full minute after a 429 Too Many Requests, as lichess asks its API clients to.
+func NewLichessBackoff() Backoff {
+	backoff := NewBackoff()
+	backoff.MaxDelay = time.Minute
+	backoff.TooManyRequestsDelay = time.Minute
+	return backoff
+}
+
+func isRetryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// delay is a random duration up to the exponential backoff of the attempt, but not shorter than Retry-After
+// or, without Retry-After, than TooManyRequestsDelay for a 429. The client does not wait longer than MaxDelay.
+func (backoff Backoff) delay(attempt int, statusCode int, retryAfter string) (delay time.Duration, canWait bool) {
+	ceiling := backoff.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > backoff.MaxDelay {
+		ceiling = backoff.MaxDelay
+	}
+	if ceiling > 0 {
+		delay = time.Duration(rand.Int63n(int64(ceiling) + 1))
+	}
+
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			delay = max(delay, time.Duration(seconds)*time.Second)
+		} else if retryAt, err := http.ParseTime(retryAfter); err == nil {
+			delay = max(delay, time.Until(retryAt))
+		}
+	} else if statusCode == http.StatusTooManyRequests {
+		delay = max(delay, backoff.TooManyRequestsDelay)
+	}
+
+	canWait = delay <= backoff.MaxDelay
+	return
+}
+
+func (backoff Backoff) wait(delay time.Duration) {
+	if backoff.sleep == nil {
+		time.Sleep(delay)
+		return
+	}
+	backoff.sleep(delay)
+}
@@ -0,0 +1,278 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"go.uber.org/zap"
+)
+
+type ChessDotCom struct {
+	Url    string
+	Client ChessDotComClient
+}
+
+type ChessDotComProfile struct {
+	UserId string `json:"@id"`
+}
+
+type ChessDotComArchives struct {
+	Archives []string `json:"archives"`
+}
+
+type ChessDotComGame struct {
+	Url         string            `json:"url"`
+	Pgn         string            `json:"pgn"`
+	EndTime     int64             `json:"end_time"`
+	TimeControl string            `json:"time_control"`
+	TimeClass   string            `json:"time_class"`
+	Rated       bool              `json:"rated"`
+	Rules       string            `json:"rules"`
+	White       ChessDotComPlayer `json:"white"`
+	Black       ChessDotComPlayer `json:"black"`
+}
+
+type ChessDotComPlayer struct {
+	Username string `json:"username"`
+	Rating   int    `json:"rating"`
+	Result   string `json:"result"`
+}
+
+// WithMetadata copies the players, the result and the game settings into the record.
+// The result and the ECO code are only available in the tag pairs of the original PGN.
+// The color, the result and the opponent of the given user are stored as well for the search filters.
+func (game ChessDotComGame) WithMetadata(gameRecord games.GameRecord, username string) games.GameRecord {
+	tags := pgnTags(game.Pgn)
+	gameRecord.White = game.White.Username
+	gameRecord.WhiteRating = game.White.Rating
+	gameRecord.WhiteResult = game.White.Result
+	gameRecord.Black = game.Black.Username
+	gameRecord.BlackRating = game.Black.Rating
+	gameRecord.BlackResult = game.Black.Result
+	gameRecord.Result = tags["Result"]
+	gameRecord.TimeClass = game.TimeClass
+	gameRecord.TimeControl = game.TimeControl
+	gameRecord.Rated = game.Rated
+	gameRecord.Eco = tags["ECO"]
+	gameRecord.Rules = game.Rules
+
+	var user, opponent ChessDotComPlayer
+	switch {
+	case strings.EqualFold(game.White.Username, username):
+		gameRecord.UserColor = games.White
+		user, opponent = game.White, game.Black
+	case strings.EqualFold(game.Black.Username, username):
+		gameRecord.UserColor = games.Black
+		user, opponent = game.Black, game.White
+	default:
+		return gameRecord
+	}
+	gameRecord.UserResult = userResult(user.Result)
+	gameRecord.Opponent = strings.ToLower(opponent.Username)
+	return gameRecord
+}
+
+// chess.com reports "win" for the winner and the reason of the outcome for the other side.
+var drawResults = map[string]bool{
+	"agreed":             true,
+	"repetition":         true,
+	"stalemate":          true,
+	"insufficient":       true,
+	"50move":             true,
+	"timevsinsufficient": true,
+}
+
+func userResult(result string) string {
+	switch {
+	case result == "":
+		return ""
+	case result == "win":
+		return games.Win
+	case drawResults[result]:
+		return games.Draw
+	default:
+		return games.Loss
+	}
+}
+
+func pgnTags(pgn string) map[string]string {
+	tags := map[string]string{}
+	for _, line := range strings.Split(pgn, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "\"]") {
+			continue
+		}
+		name, value, found := strings.Cut(line[1:len(line)-2], " \"")
+		if found {
+			tags[name] = value
+		}
+	}
+	return tags
+}
+
+func (chessDotCom ChessDotCom) Profile(ctx context.Context, logger *zap.Logger, username string) (profile Profile, err error) {
+	url := chessDotCom.Url + "/pub/player/" + username
+	logger = logger.With(zap.String("url", url))
+
+	responseBodyBytes, err := chessDotCom.Client.Get(ctx, logger, url, metrics.GetProfile)
+	if errors.As(err, &NotFoundError{}) {
+		logger.Error("profile not found on chess.com!")
+		err = fmt.Errorf("%w: %w", ErrProfileNotFound, err)
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	responseBodyString := string(responseBodyBytes)
+
+	chessDotComProfile := ChessDotComProfile{}
+	err = json.Unmarshal(responseBodyBytes, &chessDotComProfile)
+	if err != nil {
+		logger.Error("impossible to unmarshal the response body from chess.com!", zap.Error(err), zap.String("responseBody", responseBodyString))
+		return
+	}
+
+	logger.Info("profile found in chess.com")
+	profile = Profile{UserId: chessDotComProfile.UserId}
+	return
+}
+
+func (chessDotCom ChessDotCom) Archives(ctx context.Context, logger *zap.Logger, username string, now time.Time) (archiveUrls []string, err error) {
+	url := chessDotCom.Url + "/pub/player/" + username + "/games/archives"
+	logger = logger.With(zap.String("url", url))
+	logger.Info("requesting chess.com for archives")
+
+	responseBodyBytes, err := chessDotCom.Client.Get(ctx, logger, url, metrics.GetArchives)
+	if err != nil {
+		return
+	}
+
+	responseBodyString := string(responseBodyBytes)
+
+	chessDotComArchives := ChessDotComArchives{}
+	err = json.Unmarshal(responseBodyBytes, &chessDotComArchives)
+	if err != nil {
+		logger.Error("impossible to unmarshal the response body from chess.com!", zap.Error(err), zap.String("responseBody", responseBodyString))
+		return
+	}
+
+	archiveUrls = chessDotComArchives.Archives
+	logger.Info("archives found from chess.com", zap.Int("existingArchivesCount", len(archiveUrls)))
+	return
+}
+
+func (chessDotCom ChessDotCom) Games(
+	ctx context.Context,
+	logger *zap.Logger,
+	username string,
+	archive archives.ArchiveRecord,
+	consume func(gameRecords []games.GameRecord) error,
+) (validators ArchiveValidators, err error) {
+	// make this validation while reading envarionment variables
+	requestUrl, err := url.ParseRequestURI(chessDotCom.Url)
+	if err != nil {
+		logger.Error("impossible to parse the chess.com url", zap.Error(err))
+		return
+	}
+	monthInString := strconv.Itoa(archive.Month)
+	if len(monthInString) == 1 {
+		monthInString = "0" + monthInString
+	}
+	requestUrl.Path = "/pub/player/" + username + "/games/" + strconv.Itoa(archive.Year) + "/" + monthInString
+	url := requestUrl.String()
+	logger.Info("requesting games", zap.String("url", url))
+
+	validators, err = chessDotCom.Client.StreamIfModified(
+		ctx,
+		logger,
+		url,
+		metrics.GetGames,
+		ArchiveValidators{Etag: archive.Etag, LastModified: archive.LastModified},
+		func(responseBody io.Reader) (err error) {
+			chunker := gamesChunker{consume: consume}
+			err = decodeChessDotComGames(responseBody, func(chessDotComGame ChessDotComGame) error {
+				return chunker.add(chessDotComGame.WithMetadata(games.GameRecord{
+					UserId:       archive.UserId,
+					ArchiveId:    archive.ArchiveId,
+					GameId:       chessDotComGame.Url,
+					Resource:     chessDotComGame.Url,
+					Pgn:          chessDotComGame.Pgn,
+					EndTimestamp: chessDotComGame.EndTime,
+				}, username))
+			})
+			if err != nil {
+				logger.Error("impossible to stream the games", zap.Error(err))
+				return
+			}
+			return chunker.flush()
+		},
+	)
+	return
+}
+
+// decodeChessDotComGames decodes the games of a monthly archive one by one, so that the archive is never held in memory as a whole.
+func decodeChessDotComGames(responseBody io.Reader, consume func(chessDotComGame ChessDotComGame) error) (err error) {
+	decoder := json.NewDecoder(responseBody)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected the archive to be an object, got %v", token)
+	}
+
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return
+		}
+
+		if token != "games" {
+			err = decoder.Decode(&json.RawMessage{})
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		token, err = decoder.Token()
+		if err != nil {
+			return
+		}
+		if token != json.Delim('[') {
+			return fmt.Errorf("expected the games to be an array, got %v", token)
+		}
+
+		for decoder.More() {
+			chessDotComGame := ChessDotComGame{}
+			err = decoder.Decode(&chessDotComGame)
+			if err != nil {
+				return
+			}
+			err = consume(chessDotComGame)
+			if err != nil {
+				return
+			}
+		}
+
+		_, err = decoder.Token()
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = decoder.Token()
+	return
+}
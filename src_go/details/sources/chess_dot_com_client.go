@@ -0,0 +1,127 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"go.uber.org/zap"
+)
+
+// ChessDotComUserAgent tells chess.com who is calling, as it asks the users of its public API to do.
+const ChessDotComUserAgent = "chessfinder (+https://chessfinder.org)"
+
+// ChessDotComClient is the client of all chess.com calls. It retries 429 Too Many Requests and 5xx answers with the backoff.
+// Every attempt is recorded by the meter.
+type ChessDotComClient struct {
+	HttpClient *http.Client
+	UserAgent  string
+	Meter      metrics.ChessDotComMeter
+	Backoff
+}
+
+func NewChessDotComClient(meter metrics.ChessDotComMeter) ChessDotComClient {
+	return ChessDotComClient{
+		HttpClient: newHttpClient(),
+		UserAgent:  ChessDotComUserAgent,
+		Meter:      meter,
+		Backoff:    NewBackoff(),
+	}
+}
+
+// NotFoundError is the answer of chess.com for 404 Not Found and for 410 Gone, the latter being the answer for closed accounts.
+type NotFoundError struct {
+	Url        string
+	StatusCode int
+}
+
+func (err NotFoundError) Error() string {
+	return fmt.Sprintf("%s is not found on chess.com, the status code is %d", err.Url, err.StatusCode)
+}
+
+// UnexpectedStatusError is any other answer than 200 OK that is not retried or is still there after the last attempt.
+type UnexpectedStatusError struct {
+	Url        string
+	StatusCode int
+}
+
+func (err UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d from chess.com for %s", err.StatusCode, err.Url)
+}
+
+func (err UnexpectedStatusError) Is(target error) bool {
+	return target == ErrUnexpectedStatus
+}
+
+// Get returns the body of the 200 OK answer of chess.com.
+func (client ChessDotComClient) Get(ctx context.Context, logger *zap.Logger, url string, action metrics.ChessDotComAction) (responseBodyBytes []byte, err error) {
+	responseBodyBytes, _, err = client.GetIfModified(ctx, logger, url, action, ArchiveValidators{})
+	return
+}
+
+// GetIfModified sends the validators of the previous answer back to chess.com and returns ErrArchiveNotModified
+// if chess.com answers 304 Not Modified. Otherwise it returns the body and the validators of the 200 OK answer.
+func (client ChessDotComClient) GetIfModified(
+	ctx context.Context,
+	logger *zap.Logger,
+	url string,
+	action metrics.ChessDotComAction,
+	validators ArchiveValidators,
+) (responseBodyBytes []byte, newValidators ArchiveValidators, err error) {
+	newValidators, err = client.StreamIfModified(ctx, logger, url, action, validators, func(responseBody io.Reader) (err error) {
+		responseBodyBytes, err = io.ReadAll(responseBody)
+		return
+	})
+	return
+}
+
+// StreamIfModified is GetIfModified that hands the body of the 200 OK answer to consume while it is still being read.
+// The errors of consume are returned as they are, the request is not retried then.
+func (client ChessDotComClient) StreamIfModified(
+	ctx context.Context,
+	logger *zap.Logger,
+	url string,
+	action metrics.ChessDotComAction,
+	validators ArchiveValidators,
+	consume func(responseBody io.Reader) error,
+) (newValidators ArchiveValidators, err error) {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set("User-Agent", client.UserAgent)
+	if validators.Etag != "" {
+		header.Set("If-None-Match", validators.Etag)
+	}
+	if validators.LastModified != "" {
+		header.Set("If-Modified-Since", validators.LastModified)
+	}
+	request := platformRequest{
+		platform: "chess.com",
+		url:      url,
+		header:   header,
+		record: func(statusCode int) error {
+			return client.Meter.ChessDotComStatistics(action, statusCode)
+		},
+	}
+
+	statusCode, responseHeader, responseBodyBytes, err := retryingGet(ctx, logger, client.HttpClient, client.Backoff, request, consume)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case statusCode == http.StatusOK:
+		newValidators = ArchiveValidators{Etag: responseHeader.Get("ETag"), LastModified: responseHeader.Get("Last-Modified")}
+	case statusCode == http.StatusNotModified:
+		logger.Info("resource not modified on chess.com")
+		newValidators, err = validators, ErrArchiveNotModified
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		logger.Info("resource not found on chess.com", zap.Int("statusCode", statusCode), zap.String("responseBody", string(responseBodyBytes)))
+		err = NotFoundError{Url: url, StatusCode: statusCode}
+	default:
+		logger.Error("unexpected status code from chess.com", zap.Int("statusCode", statusCode))
+		err = UnexpectedStatusError{Url: url, StatusCode: statusCode}
+	}
+	return
+}
@@ -0,0 +1,158 @@
+package sources
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// chessDotComStandIn answers with the given status codes one by one, repeating the last one.
+func chessDotComStandIn(t *testing.T, headers http.Header, statusCodes ...int) (server *httptest.Server, requests *int) {
+	requests = new(int)
+	server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, ChessDotComUserAgent, request.Header.Get("User-Agent"))
+		statusCode := statusCodes[min(*requests, len(statusCodes)-1)]
+		*requests++
+		for key, values := range headers {
+			writer.Header()[key] = values
+		}
+		writer.WriteHeader(statusCode)
+		writer.Write([]byte(`{"player_id": 191338281}`))
+	}))
+	return
+}
+
+func chessDotComClientOf(server *httptest.Server, delays *[]time.Duration) ChessDotComClient {
+	client := NewChessDotComClient(metrics.ChessDotComMeter{CloudWatchClient: cloudwatch.New(awsSession)})
+	client.HttpClient = server.Client()
+	client.sleep = func(delay time.Duration) { *delays = append(*delays, delay) }
+	return client
+}
+
+func Test_ChessDotComClient_should_retry_too_many_requests_after_the_time_chess_dot_com_asks_for(t *testing.T) {
+	server, requests := chessDotComStandIn(t, http.Header{"Retry-After": {"3"}}, http.StatusTooManyRequests, http.StatusOK)
+	defer server.Close()
+	delays := []time.Duration{}
+
+	actualBody, err := chessDotComClientOf(server, &delays).Get(context.Background(), zap.NewNop(), server.URL+"/pub/player/tigran-c-137", metrics.GetProfile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"player_id": 191338281}`, string(actualBody))
+	assert.Equal(t, 2, *requests)
+	assert.Equal(t, []time.Duration{3 * time.Second}, delays)
+}
+
+func Test_ChessDotComClient_should_give_up_with_the_last_status_code_when_the_attempts_are_exhausted(t *testing.T) {
+	server, requests := chessDotComStandIn(t, nil, http.StatusServiceUnavailable)
+	defer server.Close()
+	delays := []time.Duration{}
+	client := chessDotComClientOf(server, &delays)
+
+	_, err := client.Get(context.Background(), zap.NewNop(), server.URL+"/pub/player/tigran-c-137/games/archives", metrics.GetArchives)
+
+	assert.Equal(t, UnexpectedStatusError{Url: server.URL + "/pub/player/tigran-c-137/games/archives", StatusCode: http.StatusServiceUnavailable}, err)
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+	assert.Equal(t, client.MaxAttempts, *requests)
+	assert.Len(t, delays, client.MaxAttempts-1)
+	for attempt, delay := range delays {
+		assert.LessOrEqual(t, delay, client.BaseDelay<<attempt)
+	}
+}
+
+func Test_ChessDotComClient_should_not_wait_longer_than_the_max_delay(t *testing.T) {
+	server, requests := chessDotComStandIn(t, http.Header{"Retry-After": {"3600"}}, http.StatusTooManyRequests)
+	defer server.Close()
+	delays := []time.Duration{}
+
+	_, err := chessDotComClientOf(server, &delays).Get(context.Background(), zap.NewNop(), server.URL+"/pub/player/tigran-c-137", metrics.GetProfile)
+
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+	assert.Equal(t, 1, *requests)
+	assert.Empty(t, delays)
+}
+
+func Test_ChessDotComClient_should_not_retry_gone_resources(t *testing.T) {
+	server, requests := chessDotComStandIn(t, nil, http.StatusGone)
+	defer server.Close()
+	delays := []time.Duration{}
+
+	_, err := chessDotComClientOf(server, &delays).Get(context.Background(), zap.NewNop(), server.URL+"/pub/player/tigran-c-137", metrics.GetProfile)
+
+	assert.Equal(t, NotFoundError{Url: server.URL + "/pub/player/tigran-c-137", StatusCode: http.StatusGone}, err)
+	assert.Equal(t, 1, *requests)
+	assert.Empty(t, delays)
+}
+
+func Test_ChessDotCom_should_not_find_the_profile_of_a_closed_account(t *testing.T) {
+	server, _ := chessDotComStandIn(t, nil, http.StatusGone)
+	defer server.Close()
+	delays := []time.Duration{}
+
+	_, err := ChessDotCom{Url: server.URL, Client: chessDotComClientOf(server, &delays)}.Profile(context.Background(), zap.NewNop(), "tigran-c-137")
+
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+}
+
+func Test_ChessDotCom_should_not_download_the_archive_again_if_chess_dot_com_says_it_is_not_modified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/pub/player/tigran-c-137/games/2023/10", request.URL.Path)
+		if request.Header.Get("If-None-Match") == `W/"6a1f2e3d"` && request.Header.Get("If-Modified-Since") == "Tue, 31 Oct 2023 22:17:04 GMT" {
+			writer.WriteHeader(http.StatusNotModified)
+			return
+		}
+		writer.Header().Set("ETag", `W/"6a1f2e3d"`)
+		writer.Header().Set("Last-Modified", "Tue, 31 Oct 2023 22:17:04 GMT")
+		writer.Write([]byte(`{"games": []}`))
+	}))
+	defer server.Close()
+	delays := []time.Duration{}
+	chessDotCom := ChessDotCom{Url: server.URL, Client: chessDotComClientOf(server, &delays)}
+	archive := archives.ArchiveRecord{Year: 2023, Month: 10}
+
+	actualValidators, err := chessDotCom.Games(context.Background(), zap.NewNop(), "tigran-c-137", archive, func([]games.GameRecord) error { return nil })
+	assert.NoError(t, err)
+	expectedValidators := ArchiveValidators{Etag: `W/"6a1f2e3d"`, LastModified: "Tue, 31 Oct 2023 22:17:04 GMT"}
+	assert.Equal(t, expectedValidators, actualValidators)
+
+	archive.Etag = actualValidators.Etag
+	archive.LastModified = actualValidators.LastModified
+	actualGames := []games.GameRecord{}
+	_, err = chessDotCom.Games(context.Background(), zap.NewNop(), "tigran-c-137", archive, func(gameRecords []games.GameRecord) error {
+		actualGames = append(actualGames, gameRecords...)
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrArchiveNotModified)
+	assert.Empty(t, actualGames)
+}
+
+func Test_ChessDotComClient_should_let_consume_take_longer_than_the_response_header_timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte(`{"games": [`))
+		writer.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		writer.Write([]byte(`]}`))
+	}))
+	defer server.Close()
+	client := NewChessDotComClient(metrics.ChessDotComMeter{CloudWatchClient: cloudwatch.New(awsSession)})
+	client.HttpClient.Transport.(*http.Transport).ResponseHeaderTimeout = 50 * time.Millisecond
+
+	actualBody := []byte{}
+	_, err := client.StreamIfModified(context.Background(), zap.NewNop(), server.URL+"/pub/player/tigran-c-137/games/2023/10", metrics.GetGames, ArchiveValidators{}, func(responseBody io.Reader) (err error) {
+		time.Sleep(200 * time.Millisecond)
+		actualBody, err = io.ReadAll(responseBody)
+		return
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"games": []}`, string(actualBody))
+}
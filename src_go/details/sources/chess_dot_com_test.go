@@ -0,0 +1,99 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ChessDotComGame_should_fill_the_metadata_from_the_side_of_the_user(t *testing.T) {
+	game := ChessDotComGame{
+		Url:         "https://www.chess.com/game/live/53169604577",
+		Pgn:         "[Event \"Live Chess\"]\n[White \"N-60\"]\n[Black \"tigran-c-137\"]\n[Result \"1-0\"]\n[ECO \"C34\"]\n\n1. e4 e5 1-0\n",
+		EndTime:     1659431044,
+		TimeControl: "300",
+		TimeClass:   "blitz",
+		Rated:       true,
+		Rules:       "chess",
+		White:       ChessDotComPlayer{Username: "N-60", Rating: 1067, Result: "win"},
+		Black:       ChessDotComPlayer{Username: "tigran-c-137", Rating: 990, Result: "checkmated"},
+	}
+
+	actualGame := game.WithMetadata(games.GameRecord{GameId: game.Url}, "Tigran-C-137")
+
+	expectedGame := games.GameRecord{
+		GameId:      game.Url,
+		White:       "N-60",
+		WhiteRating: 1067,
+		WhiteResult: "win",
+		Black:       "tigran-c-137",
+		BlackRating: 990,
+		BlackResult: "checkmated",
+		Result:      "1-0",
+		TimeClass:   "blitz",
+		TimeControl: "300",
+		Rated:       true,
+		Eco:         "C34",
+		Rules:       "chess",
+		UserColor:   games.Black,
+		UserResult:  games.Loss,
+		Opponent:    "n-60",
+	}
+	assert.Equal(t, expectedGame, actualGame)
+
+	game.White.Result = "repetition"
+	game.Black.Result = "repetition"
+	actualGame = game.WithMetadata(games.GameRecord{}, "n-60")
+	assert.Equal(t, games.White, actualGame.UserColor)
+	assert.Equal(t, games.Draw, actualGame.UserResult)
+	assert.Equal(t, "tigran-c-137", actualGame.Opponent)
+
+	actualGame = game.WithMetadata(games.GameRecord{}, "someone-else")
+	assert.Empty(t, actualGame.UserColor)
+	assert.Empty(t, actualGame.UserResult)
+	assert.Empty(t, actualGame.Opponent)
+}
+
+func Test_decodeChessDotComGames_should_stream_the_games_and_skip_the_other_fields_of_the_archive(t *testing.T) {
+	archive := `{"comment": {"games": [{"url": "ignored"}]}, "games": [` +
+		`{"url": "https://www.chess.com/game/live/1", "end_time": 1659431044},` +
+		`{"url": "https://www.chess.com/game/live/2", "end_time": 1659431045}` +
+		`], "count": 2}`
+
+	actualUrls := []string{}
+	err := decodeChessDotComGames(strings.NewReader(archive), func(game ChessDotComGame) error {
+		actualUrls = append(actualUrls, game.Url)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://www.chess.com/game/live/1", "https://www.chess.com/game/live/2"}, actualUrls)
+}
+
+func Test_decodeChessDotComGames_should_fail_on_a_truncated_archive(t *testing.T) {
+	archive := `{"games": [{"url": "https://www.chess.com/game/live/1", "end_time": 1659431044}, {"url": "https://www.ches`
+
+	err := decodeChessDotComGames(strings.NewReader(archive), func(game ChessDotComGame) error { return nil })
+
+	assert.Error(t, err)
+}
+
+func Test_gamesChunker_should_hand_over_the_games_in_bounded_chunks(t *testing.T) {
+	actualChunkSizes := []int{}
+	chunker := gamesChunker{consume: func(gameRecords []games.GameRecord) error {
+		actualChunkSizes = append(actualChunkSizes, len(gameRecords))
+		return nil
+	}}
+
+	for i := 0; i < 2*GamesChunkSize+7; i++ {
+		err := chunker.add(games.GameRecord{GameId: fmt.Sprint(i)})
+		assert.NoError(t, err)
+	}
+	err := chunker.flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []int{GamesChunkSize, GamesChunkSize, 7}, actualChunkSizes)
+}
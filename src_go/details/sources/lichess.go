@@ -0,0 +1,290 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"go.uber.org/zap"
+)
+
+const LichessGameUrl = "https://lichess.org/"
+
+type Lichess struct {
+	Url    string
+	Client LichessClient
+}
+
+type LichessAccount struct {
+	Id        string `json:"id"`
+	Username  string `json:"username"`
+	CreatedAt int64  `json:"createdAt"`
+	Disabled  bool   `json:"disabled"`
+}
+
+// LichessGame is a single line of the NDJSON games export requested with pgnInJson and opening.
+type LichessGame struct {
+	Id          string         `json:"id"`
+	Rated       bool           `json:"rated"`
+	Variant     string         `json:"variant"`
+	Speed       string         `json:"speed"`
+	LastMoveAt  int64          `json:"lastMoveAt"`
+	Status      string         `json:"status"`
+	Players     LichessPlayers `json:"players"`
+	Winner      string         `json:"winner"`
+	Opening     LichessOpening `json:"opening"`
+	Clock       *LichessClock  `json:"clock"`
+	DaysPerTurn int            `json:"daysPerTurn"`
+	Pgn         string         `json:"pgn"`
+}
+
+type LichessPlayers struct {
+	White LichessPlayer `json:"white"`
+	Black LichessPlayer `json:"black"`
+}
+
+type LichessPlayer struct {
+	User   LichessUser `json:"user"`
+	Rating int         `json:"rating"`
+}
+
+type LichessUser struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type LichessOpening struct {
+	Eco string `json:"eco"`
+}
+
+type LichessClock struct {
+	Initial   int `json:"initial"`
+	Increment int `json:"increment"`
+}
+
+// GameRecord maps the game onto the shape chess.com games are stored in:
+// the variant "standard" becomes the rules "chess" and the clock is written like chess.com time controls.
+func (game LichessGame) GameRecord(userId string, archiveId string, username string) (gameRecord games.GameRecord) {
+	resource := LichessGameUrl + game.Id
+	gameRecord = games.GameRecord{
+		UserId:       userId,
+		ArchiveId:    archiveId,
+		GameId:       resource,
+		Resource:     resource,
+		Pgn:          game.Pgn,
+		EndTimestamp: game.LastMoveAt / 1000,
+		White:        game.Players.White.User.Name,
+		WhiteRating:  game.Players.White.Rating,
+		WhiteResult:  game.resultOf("white"),
+		Black:        game.Players.Black.User.Name,
+		BlackRating:  game.Players.Black.Rating,
+		BlackResult:  game.resultOf("black"),
+		Result:       pgnTags(game.Pgn)["Result"],
+		TimeClass:    strings.ToLower(game.Speed),
+		TimeControl:  game.timeControl(),
+		Rated:        game.Rated,
+		Eco:          game.Opening.Eco,
+		Rules:        game.Variant,
+	}
+	if game.Variant == "standard" {
+		gameRecord.Rules = "chess"
+	}
+
+	switch username {
+	case game.Players.White.User.Id:
+		gameRecord.UserColor = games.White
+		gameRecord.UserResult = gameRecord.WhiteResult
+		gameRecord.Opponent = game.Players.Black.User.Id
+	case game.Players.Black.User.Id:
+		gameRecord.UserColor = games.Black
+		gameRecord.UserResult = gameRecord.BlackResult
+		gameRecord.Opponent = game.Players.White.User.Id
+	}
+	return
+}
+
+// IsFinished tells the games that were played to an end from the ones that were aborted or never started.
+// Only the former have a result, a game without a winner is a draw only when it is finished.
+func (game LichessGame) IsFinished() bool {
+	switch game.Status {
+	case "created", "started", "aborted", "noStart", "unknownFinish":
+		return false
+	default:
+		return true
+	}
+}
+
+func (game LichessGame) resultOf(color string) string {
+	switch game.Winner {
+	case "":
+		return games.Draw
+	case color:
+		return games.Win
+	default:
+		return games.Loss
+	}
+}
+
+func (game LichessGame) timeControl() string {
+	switch {
+	case game.Clock != nil && game.Clock.Increment > 0:
+		return strconv.Itoa(game.Clock.Initial) + "+" + strconv.Itoa(game.Clock.Increment)
+	case game.Clock != nil:
+		return strconv.Itoa(game.Clock.Initial)
+	case game.DaysPerTurn > 0:
+		return "1/" + strconv.Itoa(game.DaysPerTurn*24*60*60)
+	default:
+		return "-"
+	}
+}
+
+func (lichess Lichess) Profile(ctx context.Context, logger *zap.Logger, username string) (profile Profile, err error) {
+	account, err := lichess.account(ctx, logger, username)
+	if err != nil {
+		return
+	}
+
+	logger.Info("account found in lichess")
+	profile = Profile{UserId: lichess.Url + "/@/" + account.Id}
+	return
+}
+
+// Archives slices the games of the account into months, from the month the account was created
+// till the current one. Lichess has no archives, so every month is an archive that the games export
+// endpoint is asked for separately.
+func (lichess Lichess) Archives(ctx context.Context, logger *zap.Logger, username string, now time.Time) (archiveUrls []string, err error) {
+	logger.Info("requesting lichess for the account creation date")
+	account, err := lichess.account(ctx, logger, username)
+	if err != nil {
+		return
+	}
+
+	archiveUrls = lichessArchives(lichess.Url, username, time.UnixMilli(account.CreatedAt).UTC(), now.UTC())
+	logger.Info("archives resolved from lichess", zap.Int("existingArchivesCount", len(archiveUrls)))
+	return
+}
+
+func lichessArchives(lichessUrl string, username string, createdAt time.Time, now time.Time) (archiveUrls []string) {
+	month := time.Date(createdAt.Year(), createdAt.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for !month.After(now) {
+		archiveUrls = append(archiveUrls, fmt.Sprintf("%s/api/games/user/%s/%04d/%02d", lichessUrl, username, month.Year(), int(month.Month())))
+		month = month.AddDate(0, 1, 0)
+	}
+	return
+}
+
+// Games streams the games of the archive month from the NDJSON export one line at a time, the oldest first.
+// The games that are not finished are skipped.
+func (lichess Lichess) Games(
+	ctx context.Context,
+	logger *zap.Logger,
+	username string,
+	archive archives.ArchiveRecord,
+	consume func(gameRecords []games.GameRecord) error,
+) (validators ArchiveValidators, err error) {
+	exportUrl, err := url.JoinPath(lichess.Url, "api", "games", "user", username)
+	if err != nil {
+		logger.Error("impossible to parse the lichess url", zap.Error(err))
+		return
+	}
+	requestUrl, err := url.ParseRequestURI(exportUrl)
+	if err != nil {
+		logger.Error("impossible to parse the lichess url", zap.Error(err))
+		return
+	}
+	since := time.Date(archive.Year, time.Month(archive.Month), 1, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 1, 0)
+	requestUrl.RawQuery = url.Values{
+		"since":     {strconv.FormatInt(since.UnixMilli(), 10)},
+		"until":     {strconv.FormatInt(until.UnixMilli()-1, 10)},
+		"sort":      {"dateAsc"},
+		"pgnInJson": {"true"},
+		"opening":   {"true"},
+		"clocks":    {"false"},
+		"evals":     {"false"},
+	}.Encode()
+	url := requestUrl.String()
+	logger.Info("requesting games", zap.String("url", url))
+
+	statusCode, responseBodyBytes, err := lichess.Client.Stream(ctx, logger, url, "application/x-ndjson", metrics.ExportGames, func(responseBody io.Reader) (err error) {
+		chunker := gamesChunker{consume: consume}
+		decoder := json.NewDecoder(responseBody)
+		for {
+			lichessGame := LichessGame{}
+			err = decoder.Decode(&lichessGame)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				logger.Error("impossible to unmarshal the games", zap.Error(err))
+				return
+			}
+			if !lichessGame.IsFinished() {
+				logger.Info("skipping the game that is not finished", zap.String("gameId", lichessGame.Id), zap.String("status", lichessGame.Status))
+				continue
+			}
+			err = chunker.add(lichessGame.GameRecord(archive.UserId, archive.ArchiveId, username))
+			if err != nil {
+				return
+			}
+		}
+		return chunker.flush()
+	})
+	if err != nil {
+		return
+	}
+
+	if statusCode != http.StatusOK {
+		logger.Error("unexpected status code from lichess", zap.Int("statusCode", statusCode), zap.String("responseBody", string(responseBodyBytes)))
+		err = ErrUnexpectedStatus
+		return
+	}
+	return
+}
+
+func (lichess Lichess) account(ctx context.Context, logger *zap.Logger, username string) (account LichessAccount, err error) {
+	url := lichess.Url + "/api/user/" + username
+	logger = logger.With(zap.String("url", url))
+
+	statusCode, responseBodyBytes, err := lichess.Client.Get(ctx, logger, url, "application/json", metrics.GetAccount)
+	if err != nil {
+		return
+	}
+
+	responseBodyString := string(responseBodyBytes)
+
+	if statusCode == http.StatusNotFound {
+		logger.Error("account not found on lichess!", zap.String("responseBody", responseBodyString))
+		err = ErrProfileNotFound
+		return
+	}
+
+	if statusCode != http.StatusOK {
+		logger.Error("unexpected status code from lichess!", zap.Int("statusCode", statusCode), zap.String("responseBody", responseBodyString))
+		err = ErrUnexpectedStatus
+		return
+	}
+
+	err = json.Unmarshal(responseBodyBytes, &account)
+	if err != nil {
+		logger.Error("impossible to unmarshal the response body from lichess!", zap.Error(err), zap.String("responseBody", responseBodyString))
+		return
+	}
+
+	if account.Disabled {
+		logger.Info("account is closed on lichess")
+		err = ErrProfileNotFound
+		return
+	}
+
+	return
+}
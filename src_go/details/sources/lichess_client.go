@@ -0,0 +1,73 @@
+package sources
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"go.uber.org/zap"
+)
+
+// LichessClient is the client of all lichess calls, it waits a minute before it retries a 429 Too Many Requests.
+type LichessClient struct {
+	HttpClient *http.Client
+	Meter      metrics.LichessMeter
+	Backoff
+}
+
+func NewLichessClient(meter metrics.LichessMeter) LichessClient {
+	return LichessClient{
+		HttpClient: newHttpClient(),
+		Meter:      meter,
+		Backoff:    NewLichessBackoff(),
+	}
+}
+
+// Get returns the body of the answer, whatever its status code.
+func (client LichessClient) Get(
+	ctx context.Context,
+	logger *zap.Logger,
+	url string,
+	accept string,
+	action metrics.LichessAction,
+) (statusCode int, responseBodyBytes []byte, err error) {
+	var okResponseBodyBytes []byte
+	statusCode, responseBodyBytes, err = client.Stream(ctx, logger, url, accept, action, func(responseBody io.Reader) (err error) {
+		okResponseBodyBytes, err = io.ReadAll(responseBody)
+		if err != nil {
+			logger.Error("impossible to read the response body from lichess!", zap.Error(err))
+		}
+		return
+	})
+	if statusCode == http.StatusOK {
+		responseBodyBytes = okResponseBodyBytes
+	}
+	return
+}
+
+// Stream hands the body of the 200 OK answer to consume while it is still being read.
+// Any other answer is returned with its body once it is not retried anymore, it is up to the caller to tell what it means.
+// The errors of consume are returned as they are, the request is not retried then.
+func (client LichessClient) Stream(
+	ctx context.Context,
+	logger *zap.Logger,
+	url string,
+	accept string,
+	action metrics.LichessAction,
+	consume func(responseBody io.Reader) error,
+) (statusCode int, responseBodyBytes []byte, err error) {
+	header := http.Header{}
+	header.Set("Accept", accept)
+	request := platformRequest{
+		platform: "lichess",
+		url:      url,
+		header:   header,
+		record: func(statusCode int) error {
+			return client.Meter.LichessStatistics(action, statusCode)
+		},
+	}
+
+	statusCode, _, responseBodyBytes, err = retryingGet(ctx, logger, client.HttpClient, client.Backoff, request, consume)
+	return
+}
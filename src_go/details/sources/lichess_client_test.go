@@ -0,0 +1,92 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// lichessClientStandIn answers with the given status codes one by one, repeating the last one.
+func lichessClientStandIn(headers http.Header, statusCodes ...int) (server *httptest.Server, requests *int) {
+	requests = new(int)
+	server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		statusCode := statusCodes[min(*requests, len(statusCodes)-1)]
+		*requests++
+		for key, values := range headers {
+			writer.Header()[key] = values
+		}
+		writer.WriteHeader(statusCode)
+		writer.Write([]byte(`{"id": "tigran-c-137", "username": "Tigran-C-137", "createdAt": 1637186700000}`))
+	}))
+	return
+}
+
+func lichessClientOf(server *httptest.Server, delays *[]time.Duration) LichessClient {
+	client := NewLichessClient(metrics.LichessMeter{CloudWatchClient: cloudwatch.New(awsSession)})
+	client.HttpClient = server.Client()
+	client.sleep = func(delay time.Duration) { *delays = append(*delays, delay) }
+	return client
+}
+
+func Test_LichessClient_should_retry_too_many_requests_after_the_time_lichess_asks_for(t *testing.T) {
+	server, requests := lichessClientStandIn(http.Header{"Retry-After": {"3"}}, http.StatusTooManyRequests, http.StatusOK)
+	defer server.Close()
+	delays := []time.Duration{}
+
+	statusCode, actualBody, err := lichessClientOf(server, &delays).Get(context.Background(), zap.NewNop(), server.URL+"/api/user/tigran-c-137", "application/json", metrics.GetAccount)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, `{"id": "tigran-c-137", "username": "Tigran-C-137", "createdAt": 1637186700000}`, string(actualBody))
+	assert.Equal(t, 2, *requests)
+	assert.Equal(t, []time.Duration{3 * time.Second}, delays)
+}
+
+func Test_LichessClient_should_wait_a_minute_before_retrying_too_many_requests_without_retry_after(t *testing.T) {
+	server, requests := lichessClientStandIn(nil, http.StatusTooManyRequests, http.StatusOK)
+	defer server.Close()
+	delays := []time.Duration{}
+
+	statusCode, _, err := lichessClientOf(server, &delays).Get(context.Background(), zap.NewNop(), server.URL+"/api/user/tigran-c-137", "application/json", metrics.GetAccount)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 2, *requests)
+	assert.Equal(t, []time.Duration{time.Minute}, delays)
+}
+
+func Test_LichessClient_should_give_up_with_the_last_status_code_when_the_attempts_are_exhausted(t *testing.T) {
+	server, requests := lichessClientStandIn(nil, http.StatusServiceUnavailable)
+	defer server.Close()
+	delays := []time.Duration{}
+	client := lichessClientOf(server, &delays)
+
+	statusCode, _, err := client.Get(context.Background(), zap.NewNop(), server.URL+"/api/user/tigran-c-137", "application/json", metrics.GetAccount)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+	assert.Equal(t, client.MaxAttempts, *requests)
+	assert.Len(t, delays, client.MaxAttempts-1)
+	for attempt, delay := range delays {
+		assert.LessOrEqual(t, delay, client.BaseDelay<<attempt)
+	}
+}
+
+func Test_Lichess_should_not_retry_an_unknown_account(t *testing.T) {
+	server, requests := lichessClientStandIn(nil, http.StatusNotFound)
+	defer server.Close()
+	delays := []time.Duration{}
+
+	_, err := Lichess{Url: server.URL, Client: lichessClientOf(server, &delays)}.Profile(context.Background(), zap.NewNop(), "tigran-c-137")
+
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+	assert.Equal(t, 1, *requests)
+	assert.Empty(t, delays)
+}
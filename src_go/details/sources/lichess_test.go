@@ -0,0 +1,187 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var awsSession = session.Must(session.NewSession(&aws.Config{
+	Region:     aws.String("us-east-1"),
+	Endpoint:   aws.String("http://localhost:4566"), // this is the LocalStack endpoint for all services
+	DisableSSL: aws.Bool(true),
+}))
+
+func lichessStandIn(t *testing.T, path string, contentType string, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, path, request.URL.Path)
+		if request.URL.Path != path {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writer.Header().Set("Content-Type", contentType)
+		writer.WriteHeader(http.StatusOK)
+		writer.Write([]byte(body))
+	}))
+}
+
+func lichessOf(server *httptest.Server) Lichess {
+	client := NewLichessClient(metrics.LichessMeter{CloudWatchClient: cloudwatch.New(awsSession)})
+	client.HttpClient = server.Client()
+	client.sleep = func(time.Duration) {}
+	return Lichess{Url: server.URL, Client: client}
+}
+
+func Test_lichessArchives_should_slice_the_games_into_months_from_the_creation_of_the_account_till_now(t *testing.T) {
+	createdAt := time.Date(2021, 11, 17, 22, 5, 0, 0, time.UTC)
+	now := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	actualArchives := lichessArchives("https://lichess.org", "tigran-c-137", createdAt, now)
+
+	expectedArchives := []string{
+		"https://lichess.org/api/games/user/tigran-c-137/2021/11",
+		"https://lichess.org/api/games/user/tigran-c-137/2021/12",
+		"https://lichess.org/api/games/user/tigran-c-137/2022/01",
+		"https://lichess.org/api/games/user/tigran-c-137/2022/02",
+	}
+
+	assert.Equal(t, expectedArchives, actualArchives)
+}
+
+func Test_Lichess_should_not_find_the_profile_of_a_closed_account(t *testing.T) {
+	server := lichessStandIn(t, "/api/user/tigran-c-137", "application/json", `{"id": "tigran-c-137", "username": "Tigran-C-137", "disabled": true}`)
+	defer server.Close()
+
+	_, err := lichessOf(server).Profile(context.Background(), zap.NewNop(), "tigran-c-137")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+}
+
+func Test_Lichess_should_stream_the_games_of_the_month_from_the_export(t *testing.T) {
+	export, err := os.ReadFile("testdata/lichess_2022-08.ndjson")
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/api/games/user/tigran-c-137", request.URL.Path)
+		assert.Equal(t, "1659312000000", request.URL.Query().Get("since"))
+		assert.Equal(t, "1661990399999", request.URL.Query().Get("until"))
+		assert.Equal(t, "dateAsc", request.URL.Query().Get("sort"))
+		assert.Equal(t, "true", request.URL.Query().Get("pgnInJson"))
+		assert.Equal(t, "application/x-ndjson", request.Header.Get("Accept"))
+		writer.Header().Set("Content-Type", "application/x-ndjson")
+		writer.WriteHeader(http.StatusOK)
+		writer.Write(export)
+	}))
+	defer server.Close()
+
+	archive := archives.ArchiveRecord{
+		UserId:    "https://lichess.org/@/tigran-c-137",
+		ArchiveId: "https://lichess.org/api/games/user/tigran-c-137/2022/08",
+		Year:      2022,
+		Month:     8,
+	}
+
+	actualGames := []games.GameRecord{}
+	_, err = lichessOf(server).Games(context.Background(), zap.NewNop(), "tigran-c-137", archive, func(gameRecords []games.GameRecord) error {
+		actualGames = append(actualGames, gameRecords...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, actualGames, 2)
+
+	for i := range actualGames {
+		assert.True(t, strings.HasPrefix(actualGames[i].Pgn, "[Event "), "the original pgn is expected")
+		actualGames[i].Pgn = ""
+	}
+
+	expectedGames := []games.GameRecord{
+		{
+			UserId:       archive.UserId,
+			ArchiveId:    archive.ArchiveId,
+			GameId:       "https://lichess.org/q7ZvsdUF",
+			Resource:     "https://lichess.org/q7ZvsdUF",
+			EndTimestamp: 1659429921,
+			White:        "Tigran-C-137",
+			WhiteRating:  1502,
+			WhiteResult:  games.Win,
+			Black:        "Opponent_One",
+			BlackRating:  1488,
+			BlackResult:  games.Loss,
+			Result:       "1-0",
+			TimeClass:    "blitz",
+			TimeControl:  "180+2",
+			Rated:        true,
+			Eco:          "C20",
+			Rules:        "chess",
+			UserColor:    games.White,
+			UserResult:   games.Win,
+			Opponent:     "opponent_one",
+		},
+		{
+			UserId:       archive.UserId,
+			ArchiveId:    archive.ArchiveId,
+			GameId:       "https://lichess.org/Xb3kLq9P",
+			Resource:     "https://lichess.org/Xb3kLq9P",
+			EndTimestamp: 1659430643,
+			White:        "Opponent_Two",
+			WhiteRating:  1611,
+			WhiteResult:  games.Draw,
+			Black:        "Tigran-C-137",
+			BlackRating:  1507,
+			BlackResult:  games.Draw,
+			Result:       "1/2-1/2",
+			TimeClass:    "rapid",
+			TimeControl:  "600",
+			Eco:          "D02",
+			Rules:        "chess",
+			UserColor:    games.Black,
+			UserResult:   games.Draw,
+			Opponent:     "opponent_two",
+		},
+	}
+
+	assert.Equal(t, expectedGames, actualGames)
+}
+
+func Test_Lichess_should_request_the_export_under_the_path_of_the_lichess_url(t *testing.T) {
+	server := lichessStandIn(t, "/proxy/lichess/api/games/user/tigran-c-137", "application/x-ndjson", "")
+	defer server.Close()
+
+	lichess := lichessOf(server)
+	lichess.Url = server.URL + "/proxy/lichess"
+
+	archive := archives.ArchiveRecord{Year: 2022, Month: 8}
+	_, err := lichess.Games(context.Background(), zap.NewNop(), "tigran-c-137", archive, func(gameRecords []games.GameRecord) error {
+		assert.Empty(t, gameRecords)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func Test_LichessGame_should_tell_finished_games_from_aborted_and_ongoing_ones(t *testing.T) {
+	for _, status := range []string{"mate", "resign", "stalemate", "timeout", "draw", "outoftime", "cheat", "variantEnd"} {
+		assert.True(t, LichessGame{Status: status}.IsFinished(), status)
+	}
+	for _, status := range []string{"created", "started", "aborted", "noStart", "unknownFinish"} {
+		assert.False(t, LichessGame{Status: status}.IsFinished(), status)
+	}
+}
+
+func Test_LichessGame_should_write_the_clock_like_a_chess_com_time_control(t *testing.T) {
+	assert.Equal(t, "180+2", LichessGame{Clock: &LichessClock{Initial: 180, Increment: 2}}.timeControl())
+	assert.Equal(t, "600", LichessGame{Clock: &LichessClock{Initial: 600}}.timeControl())
+	assert.Equal(t, "1/259200", LichessGame{DaysPerTurn: 3}.timeControl())
+	assert.Equal(t, "-", LichessGame{}.timeControl())
+}
@@ -0,0 +1,100 @@
+package sources
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// platformRequest is a GET to a platform with the headers it is sent with.
+// record is called with the status code of every attempt, its errors are only logged.
+type platformRequest struct {
+	platform string
+	url      string
+	header   http.Header
+	record   func(statusCode int) error
+}
+
+// retryingGet sends the request and retries 429 Too Many Requests and 5xx answers with the backoff.
+// The body of the 200 OK answer is handed to consume while it is still being read, the errors of consume are returned as they are
+// and the request is not retried then. Any other answer is returned with its body once it is not retried anymore,
+// it is up to the client of the platform to tell what it means.
+func retryingGet(
+	ctx context.Context,
+	logger *zap.Logger,
+	httpClient *http.Client,
+	backoff Backoff,
+	request platformRequest,
+	consume func(responseBody io.Reader) error,
+) (statusCode int, header http.Header, responseBodyBytes []byte, err error) {
+	logger = logger.With(zap.String("platform", request.platform))
+	for attempt := 1; ; attempt++ {
+		logger := logger.With(zap.Int("attempt", attempt))
+
+		statusCode, header, responseBodyBytes, err = getOnce(ctx, logger, httpClient, request, consume)
+		if err != nil || statusCode == http.StatusOK {
+			return
+		}
+
+		if !isRetryable(statusCode) || attempt >= backoff.MaxAttempts {
+			return
+		}
+
+		retryAfter := header.Get("Retry-After")
+		delay, canWait := backoff.delay(attempt, statusCode, retryAfter)
+		if !canWait {
+			logger.Error("the platform asks to retry later than the client can wait", zap.Int("statusCode", statusCode), zap.String("retryAfter", retryAfter))
+			return
+		}
+
+		logger.Warn("retrying the platform", zap.Int("statusCode", statusCode), zap.Duration("delay", delay))
+		backoff.wait(delay)
+	}
+}
+
+func getOnce(
+	ctx context.Context,
+	logger *zap.Logger,
+	httpClient *http.Client,
+	platformRequest platformRequest,
+	consume func(responseBody io.Reader) error,
+) (statusCode int, header http.Header, responseBodyBytes []byte, err error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", platformRequest.url, nil)
+	if err != nil {
+		logger.Error("impossible to create a request to the platform!", zap.Error(err))
+		return
+	}
+	for key, values := range platformRequest.header {
+		request.Header[key] = values
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		logger.Error("impossible to request the platform!", zap.Error(err))
+		return
+	}
+
+	defer response.Body.Close()
+
+	errFromMetricRegistration := platformRequest.record(response.StatusCode)
+	if errFromMetricRegistration != nil {
+		logger.Warn("impossible to register the metric of the platform", zap.Error(errFromMetricRegistration))
+	}
+
+	statusCode = response.StatusCode
+	header = response.Header
+
+	if statusCode == http.StatusOK {
+		err = consume(response.Body)
+		return
+	}
+
+	responseBodyBytes, err = io.ReadAll(response.Body)
+	if err != nil {
+		logger.Error("impossible to read the response body from the platform!", zap.Error(err))
+		return
+	}
+	return
+}
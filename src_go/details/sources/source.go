@@ -0,0 +1,117 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"go.uber.org/zap"
+)
+
+// GameSource is a chess platform the profiles, the archives and the games of the users are downloaded from.
+type GameSource interface {
+	// Profile returns ErrProfileNotFound if the platform does not know the user or the account is closed.
+	Profile(ctx context.Context, logger *zap.Logger, username string) (profile Profile, err error)
+	// Archives lists the archives of the user. The last two segments of every archive are its year and month.
+	Archives(ctx context.Context, logger *zap.Logger, username string, now time.Time) (archiveUrls []string, err error)
+	// Games streams the games of the archive with their original PGN to consume in chunks of at most GamesChunkSize games
+	// and returns the validators of the answer. The errors of consume stop the stream and are returned as they are.
+	// It returns ErrArchiveNotModified if the platform confirms that the archive has not changed since the validators of the archive were taken.
+	Games(
+		ctx context.Context,
+		logger *zap.Logger,
+		username string,
+		archive archives.ArchiveRecord,
+		consume func(gameRecords []games.GameRecord) error,
+	) (validators ArchiveValidators, err error)
+}
+
+// newHttpClient bounds the time to connect and to get the status line and the headers of an answer, but not the time to read its body:
+// the archives are streamed into the tables while they are read, so reading a large one takes as long as persisting its games.
+// The body is bounded by the timeout of the Lambda instead.
+func newHttpClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConns:          100,
+			ForceAttemptHTTP2:     true,
+		},
+	}
+}
+
+// GamesChunkSize bounds the games held in memory while an archive is streamed.
+const GamesChunkSize = 100
+
+type gamesChunker struct {
+	consume func(gameRecords []games.GameRecord) error
+	chunk   []games.GameRecord
+}
+
+func (chunker *gamesChunker) add(gameRecord games.GameRecord) (err error) {
+	chunker.chunk = append(chunker.chunk, gameRecord)
+	if len(chunker.chunk) < GamesChunkSize {
+		return
+	}
+	return chunker.flush()
+}
+
+func (chunker *gamesChunker) flush() (err error) {
+	if len(chunker.chunk) == 0 {
+		return
+	}
+	chunk := chunker.chunk
+	chunker.chunk = nil
+	return chunker.consume(chunk)
+}
+
+// ArchiveValidators are the ETag and Last-Modified headers of the last answer for an archive.
+type ArchiveValidators struct {
+	Etag         string
+	LastModified string
+}
+
+type Profile struct {
+	UserId string
+}
+
+var ErrProfileNotFound = errors.New("profile not found")
+
+var ErrUnexpectedStatus = errors.New("unexpected status code")
+
+var ErrArchiveNotModified = errors.New("archive not modified")
+
+// Platforms are the game sources of all supported platforms.
+func Platforms(
+	chessDotComUrl string,
+	lichessUrl string,
+	metricsNamespace string,
+	cloudWatchClient *cloudwatch.CloudWatch,
+) map[users.Platform]GameSource {
+	return map[users.Platform]GameSource{
+		users.ChessDotCom: ChessDotCom{
+			Url: chessDotComUrl,
+			Client: NewChessDotComClient(metrics.ChessDotComMeter{
+				Namespace:        metricsNamespace,
+				CloudWatchClient: cloudWatchClient,
+			}),
+		},
+		users.Lichess: Lichess{
+			Url: lichessUrl,
+			Client: NewLichessClient(metrics.LichessMeter{
+				Namespace:        metricsNamespace,
+				CloudWatchClient: cloudWatchClient,
+			}),
+		},
+	}
+}
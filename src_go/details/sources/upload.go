@@ -0,0 +1,95 @@
+package sources
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+)
+
+const pgnDateLayout = "2006.01.02"
+
+// SplitGames splits a PGN file into its games. A game starts with its tag pairs and ends with its movetext,
+// so a tag pair after a movetext starts the next game. Tag pairs without a movetext are not a game.
+func SplitGames(pgn string) (pgnGames []string) {
+	lines := strings.Split(strings.ReplaceAll(pgn, "\r\n", "\n"), "\n")
+	game := []string{}
+	hasMovetext := false
+	for _, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmedLine, "[") && hasMovetext {
+			pgnGames = append(pgnGames, strings.TrimSpace(strings.Join(game, "\n")))
+			game = []string{}
+			hasMovetext = false
+		}
+		if trimmedLine != "" && !strings.HasPrefix(trimmedLine, "[") {
+			hasMovetext = true
+		}
+		game = append(game, line)
+	}
+	if hasMovetext {
+		pgnGames = append(pgnGames, strings.TrimSpace(strings.Join(game, "\n")))
+	}
+	return
+}
+
+// UploadedGames makes records of the games of an uploaded archive. The games have no url,
+// so they are identified by their number in the uploaded file, firstGame being the number of games before them.
+// Everything else comes from the tag pairs, the game ending at the start of its Date.
+func UploadedGames(username string, archive archives.ArchiveRecord, pgnGames []string, firstGame int) (gameRecords []games.GameRecord) {
+	gameRecords = make([]games.GameRecord, len(pgnGames))
+	for i, pgn := range pgnGames {
+		tags := pgnTags(pgn)
+		gameId := archive.ArchiveId + "/" + strconv.Itoa(firstGame+i+1)
+		whiteResult, blackResult := uploadedResults(tags["Result"])
+		gameRecord := games.GameRecord{
+			UserId:      archive.UserId,
+			ArchiveId:   archive.ArchiveId,
+			GameId:      gameId,
+			Resource:    gameId,
+			Pgn:         pgn,
+			White:       tags["White"],
+			WhiteResult: whiteResult,
+			Black:       tags["Black"],
+			BlackResult: blackResult,
+			Result:      tags["Result"],
+			TimeControl: tags["TimeControl"],
+			Eco:         tags["ECO"],
+			Rules:       "chess",
+		}
+		gameRecord.WhiteRating, _ = strconv.Atoi(tags["WhiteElo"])
+		gameRecord.BlackRating, _ = strconv.Atoi(tags["BlackElo"])
+		date, err := time.Parse(pgnDateLayout, tags["Date"])
+		if err == nil {
+			gameRecord.EndTimestamp = date.Unix()
+		}
+
+		switch {
+		case strings.EqualFold(gameRecord.White, username):
+			gameRecord.UserColor = games.White
+			gameRecord.UserResult = whiteResult
+			gameRecord.Opponent = strings.ToLower(gameRecord.Black)
+		case strings.EqualFold(gameRecord.Black, username):
+			gameRecord.UserColor = games.Black
+			gameRecord.UserResult = blackResult
+			gameRecord.Opponent = strings.ToLower(gameRecord.White)
+		}
+		gameRecords[i] = gameRecord
+	}
+	return
+}
+
+func uploadedResults(result string) (whiteResult string, blackResult string) {
+	switch result {
+	case "1-0":
+		return games.Win, games.Loss
+	case "0-1":
+		return games.Loss, games.Win
+	case "1/2-1/2":
+		return games.Draw, games.Draw
+	default:
+		return "", ""
+	}
+}
@@ -0,0 +1,77 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/stretchr/testify/assert"
+)
+
+const tournamentPgn = "[Event \"Club Championship\"]\r\n[Site \"Yerevan\"]\r\n[Date \"2023.05.14\"]\r\n[White \"Tigran-C-137\"]\r\n[Black \"Aronian, Levon\"]\r\n[Result \"0-1\"]\r\n[WhiteElo \"1850\"]\r\n[BlackElo \"2100\"]\r\n[ECO \"B01\"]\r\n\r\n1. e4 d5 2. exd5 Qxd5\r\n3. Nc3 Qa5 0-1\r\n\r\n" +
+	"[Event \"Club Championship\"]\r\n[Site \"Yerevan\"]\r\n[Date \"2023.05.??\"]\r\n[White \"Smbatyan, Ani\"]\r\n[Black \"tigran-c-137\"]\r\n[Result \"1/2-1/2\"]\r\n[TimeControl \"5400+30\"]\r\n\r\n1. d4 Nf6 1/2-1/2\r\n\r\n" +
+	"[Event \"Club Championship\"]\r\n[Site \"Yerevan\"]\r\n"
+
+func Test_SplitGames_should_split_a_pgn_file_into_games_with_movetext(t *testing.T) {
+	actualGames := SplitGames(tournamentPgn)
+
+	expectedGames := []string{
+		"[Event \"Club Championship\"]\n[Site \"Yerevan\"]\n[Date \"2023.05.14\"]\n[White \"Tigran-C-137\"]\n[Black \"Aronian, Levon\"]\n[Result \"0-1\"]\n[WhiteElo \"1850\"]\n[BlackElo \"2100\"]\n[ECO \"B01\"]\n\n1. e4 d5 2. exd5 Qxd5\n3. Nc3 Qa5 0-1",
+		"[Event \"Club Championship\"]\n[Site \"Yerevan\"]\n[Date \"2023.05.??\"]\n[White \"Smbatyan, Ani\"]\n[Black \"tigran-c-137\"]\n[Result \"1/2-1/2\"]\n[TimeControl \"5400+30\"]\n\n1. d4 Nf6 1/2-1/2",
+	}
+
+	assert.Equal(t, expectedGames, actualGames)
+}
+
+func Test_UploadedGames_should_number_the_games_of_the_archive_and_read_their_tag_pairs(t *testing.T) {
+	archive := archives.ArchiveRecord{
+		UserId:    "https://api.chess.com/pub/player/tigran-c-137",
+		ArchiveId: "https://api.chess.com/pub/player/tigran-c-137/uploads/5e884898da280471",
+		Uploaded:  true,
+	}
+	pgnGames := SplitGames(tournamentPgn)
+
+	actualGames := UploadedGames("tigran-c-137", archive, pgnGames, 10)
+
+	expectedGames := []games.GameRecord{
+		{
+			UserId:       archive.UserId,
+			ArchiveId:    archive.ArchiveId,
+			GameId:       archive.ArchiveId + "/11",
+			Resource:     archive.ArchiveId + "/11",
+			Pgn:          pgnGames[0],
+			EndTimestamp: 1684022400,
+			White:        "Tigran-C-137",
+			WhiteRating:  1850,
+			WhiteResult:  games.Loss,
+			Black:        "Aronian, Levon",
+			BlackRating:  2100,
+			BlackResult:  games.Win,
+			Result:       "0-1",
+			Eco:          "B01",
+			Rules:        "chess",
+			UserColor:    games.White,
+			UserResult:   games.Loss,
+			Opponent:     "aronian, levon",
+		},
+		{
+			UserId:      archive.UserId,
+			ArchiveId:   archive.ArchiveId,
+			GameId:      archive.ArchiveId + "/12",
+			Resource:    archive.ArchiveId + "/12",
+			Pgn:         pgnGames[1],
+			White:       "Smbatyan, Ani",
+			WhiteResult: games.Draw,
+			Black:       "tigran-c-137",
+			BlackResult: games.Draw,
+			Result:      "1/2-1/2",
+			TimeControl: "5400+30",
+			Rules:       "chess",
+			UserColor:   games.Black,
+			UserResult:  games.Draw,
+			Opponent:    "smbatyan, ani",
+		},
+	}
+
+	assert.Equal(t, expectedGames, actualGames)
+}
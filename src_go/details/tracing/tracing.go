@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+var tracer = otel.Tracer("github.com/chessfinder/chessfinder-faster-backend/src_go")
+
+// MustSetUp makes the spans of the service propagate in the W3C trace context.
+// With OTEL_EXPORTER_OTLP_ENDPOINT set, e.g. to http://localhost:4318, the spans are exported to that OTLP collector,
+// otherwise they only give their ids to the logs. Shutdown flushes the spans not exported yet.
+func MustSetUp(serviceName string) (shutdown func()) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	}
+
+	if _, isExported := os.LookupEnv("OTEL_EXPORTER_OTLP_ENDPOINT"); isExported {
+		exporter, err := otlptracehttp.New(context.Background())
+		if err != nil {
+			panic(err)
+		}
+		if _, isLambda := os.LookupEnv("AWS_LAMBDA_FUNCTION_NAME"); isLambda {
+			// a lambda is frozen between the invocations, a batch may never be exported
+			options = append(options, sdktrace.WithSyncer(exporter))
+		} else {
+			options = append(options, sdktrace.WithBatcher(exporter))
+		}
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(tracerProvider)
+
+	return func() {
+		tracerProvider.Shutdown(context.Background())
+	}
+}
+
+// StartRequest starts the span of an API request, as a child of the trace context in its headers if there is any.
+func StartRequest(event *events.APIGatewayV2HTTPRequest) (ctx context.Context, span trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(event.Headers))
+	return tracer.Start(
+		ctx,
+		event.RequestContext.HTTP.Method+" "+event.RequestContext.HTTP.Path,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("requestId", event.RequestContext.RequestID)),
+	)
+}
+
+// StartMessage starts the span of processing a message, as a child of the trace context in its attributes if there is any.
+func StartMessage(message *events.SQSMessage) (ctx context.Context, span trace.Span) {
+	carrier := propagation.MapCarrier{}
+	for name, messageAttribute := range message.MessageAttributes {
+		if messageAttribute.StringValue != nil {
+			carrier[name] = *messageAttribute.StringValue
+		}
+	}
+	ctx = otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+	return tracer.Start(
+		ctx,
+		"process message",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("messageId", message.MessageId), attribute.String("queue", message.EventSourceARN)),
+	)
+}
+
+// StartCall starts the span of a call to DynamoDB, chess.com, the core and so on.
+func StartCall(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+}
+
+// End ends the span, recording the error if there is any.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject returns the trace context as the attributes of a message.
+func Inject(ctx context.Context) (attributes map[string]string) {
+	attributes = map[string]string{}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(attributes))
+	return
+}
+
+// Logger adds the ids of the current span to the logs.
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logger
+	}
+	return logger.With(zap.String("traceId", spanContext.TraceID().String()), zap.String("spanId", spanContext.SpanID().String()))
+}
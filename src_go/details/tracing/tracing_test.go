@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_StartMessage_should_continue_the_trace_of_the_request_that_published_the_message(t *testing.T) {
+	MustSetUp("chessfinder-test")
+
+	requestCtx, requestSpan := StartRequest(&events.APIGatewayV2HTTPRequest{
+		Headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	})
+	defer requestSpan.End()
+
+	messageAttributes := map[string]events.SQSMessageAttribute{}
+	for name, value := range Inject(requestCtx) {
+		value := value
+		messageAttributes[name] = events.SQSMessageAttribute{DataType: "String", StringValue: &value}
+	}
+
+	messageCtx, messageSpan := StartMessage(&events.SQSMessage{MessageId: "1", MessageAttributes: messageAttributes})
+	End(messageSpan, errors.New("throttled"))
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(requestCtx).TraceID().String())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(messageCtx).TraceID().String())
+	assert.NotEqual(t, trace.SpanContextFromContext(requestCtx).SpanID(), trace.SpanContextFromContext(messageCtx).SpanID())
+}
+
+func Test_StartMessage_should_start_a_new_trace_for_a_message_without_trace_context(t *testing.T) {
+	MustSetUp("chessfinder-test")
+
+	messageCtx, messageSpan := StartMessage(&events.SQSMessage{MessageId: "1"})
+	defer messageSpan.End()
+
+	assert.True(t, trace.SpanContextFromContext(messageCtx).IsValid())
+}
+
+func Test_Logger_should_log_the_ids_of_the_current_span(t *testing.T) {
+	MustSetUp("chessfinder-test")
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	ctx, span := StartCall(context.Background(), "GamesTable.QueryGames")
+	defer span.End()
+	Logger(ctx, zap.New(core)).Info("querying")
+	Logger(context.Background(), zap.New(core)).Info("no span")
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	assert.Equal(t, map[string]interface{}{
+		"traceId": spanContext.TraceID().String(),
+		"spanId":  spanContext.SpanID().String(),
+	}, logs.All()[0].ContextMap())
+	assert.Empty(t, logs.All()[1].ContextMap())
+}
@@ -1,12 +1,10 @@
-package main
+package check_status
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
@@ -14,8 +12,7 @@ import (
 )
 
 type DownloadStatusChecker struct {
-	awsConfig          *aws.Config
-	downloadsTableName string
+	DownloadsTable downloads.DownloadsRepository
 }
 
 func (checker *DownloadStatusChecker) Check(event *events.APIGatewayV2HTTPRequest) (responseEvent events.APIGatewayV2HTTPResponse, err error) {
@@ -24,13 +21,6 @@ func (checker *DownloadStatusChecker) Check(event *events.APIGatewayV2HTTPReques
 	logger = logger.With(zap.String("requestId", event.RequestContext.RequestID))
 	defer logger.Sync()
 
-	awsSession, err := session.NewSession(checker.awsConfig)
-	if err != nil {
-		logger.Panic("impossible to create an AWS session!", zap.Error(err))
-	}
-
-	dynamodbClient := dynamodb.New(awsSession)
-
 	method := event.RequestContext.HTTP.Method
 	path := event.RequestContext.HTTP.Path
 
@@ -48,10 +38,7 @@ func (checker *DownloadStatusChecker) Check(event *events.APIGatewayV2HTTPReques
 
 	logger = logger.With(zap.String("downloadId", downloadId))
 
-	downloadRecordCandidate, err := downloads.DownloadsTable{
-		Name:           checker.downloadsTableName,
-		DynamodbClient: dynamodbClient,
-	}.GetDownloadRecord(downloadId)
+	downloadRecordCandidate, err := checker.DownloadsTable.GetDownloadRecord(context.Background(), downloadId)
 
 	if err != nil {
 		logger.Error("faild to get download record!", zap.Error(err))
@@ -1,6 +1,7 @@
-package main
+package check_status
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -23,18 +24,17 @@ var awsConfig = aws.Config{
 	DisableSSL: aws.Bool(true),
 }
 
-var statusChecker = DownloadStatusChecker{
-	awsConfig:          &awsConfig,
-	downloadsTableName: "chessfinder_dynamodb-downloads",
-}
-
 var awsSession = session.Must(session.NewSession(&awsConfig))
 var dynamodbClient = dynamodb.New(awsSession)
 var downloadsTable = downloads.DownloadsTable{
-	Name:           statusChecker.downloadsTableName,
+	Name:           "chessfinder_dynamodb-downloads",
 	DynamodbClient: dynamodbClient,
 }
 
+var statusChecker = DownloadStatusChecker{
+	DownloadsTable: downloadsTable,
+}
+
 func Test_download_task_status_is_delivered_if_there_is_a_task_for_given_id(t *testing.T) {
 	var err error
 	userId := uuid.New().String()
@@ -69,7 +69,7 @@ func Test_download_task_status_is_delivered_if_there_is_a_task_for_given_id(t *t
 		Total:            10,
 	}
 
-	err = downloadsTable.PutDownloadRecord(dowloadRecord)
+	err = downloadsTable.PutDownloadRecord(context.Background(), dowloadRecord)
 
 	assert.NoError(t, err)
 
@@ -119,3 +119,46 @@ func Test_download_request_not_found_is_responded_if_there_is_no_task_for_given_
 	assert.JSONEq(t, expectedResponseBody, actualResponse.Body, "Expected error is not met!")
 	assert.Equal(t, 422, actualResponse.StatusCode, "Expected status code is not met!")
 }
+
+func Test_download_task_status_is_delivered_from_any_downloads_repository(t *testing.T) {
+	inMemoryDownloadsTable := downloads.NewInMemoryDownloadsTable()
+	checker := DownloadStatusChecker{DownloadsTable: inMemoryDownloadsTable}
+
+	downloadId := downloads.NewDownloadId("https://api.chess.com/pub/player/tigran-c-137")
+	startAt := time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC)
+	err := inMemoryDownloadsTable.PutDownloadRecord(context.Background(), downloads.NewDownloadRecord(downloadId, 3, startAt, time.Hour))
+	assert.NoError(t, err)
+	err = inMemoryDownloadsTable.IncrementFailure(context.Background(), downloadId.String(), db.Zuludatetime(startAt.Add(time.Minute)), time.Hour)
+	assert.NoError(t, err)
+
+	event := events.APIGatewayV2HTTPRequest{
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "GET",
+				Path:   "/api/faster/game",
+			},
+		},
+		QueryStringParameters: map[string]string{
+			"downloadId": downloadId.String(),
+		},
+	}
+
+	actualResponse, err := checker.Check(&event)
+	assert.NoError(t, err)
+
+	expectedResponseBody := fmt.Sprintf(
+		`{
+			"downloadId": "%v",
+			"startAt": "2023-10-01T11:30:17.123Z",
+			"lastDownloadedAt": "2023-10-01T11:31:17.123Z",
+			"failed": 1,
+			"succeed": 0,
+			"done": 1,
+			"pending": 2,
+			"total": 3
+		}`,
+		downloadId,
+	)
+	assert.JSONEq(t, expectedResponseBody, actualResponse.Body)
+	assert.Equal(t, 200, actualResponse.StatusCode)
+}
@@ -6,7 +6,11 @@ import (
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/download/check_status"
 )
 
 func main() {
@@ -20,10 +24,14 @@ func main() {
 		panic(errors.New("AWS_REGION is missing"))
 	}
 
-	checker := DownloadStatusChecker{
-		downloadsTableName: downloadsTableName,
-		awsConfig: &aws.Config{
-			Region: &awsRegion,
+	dynamodbClient := dynamodb.New(session.Must(session.NewSession(&aws.Config{
+		Region: &awsRegion,
+	})))
+
+	checker := check_status.DownloadStatusChecker{
+		DownloadsTable: downloads.DownloadsTable{
+			Name:           downloadsTableName,
+			DynamodbClient: dynamodbClient,
 		},
 	}
 
@@ -1,58 +1,44 @@
-package main
+package initiate
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"io"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/cloudwatch"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
-	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
 	"go.uber.org/zap"
 )
 
 type ArchiveDownloader struct {
-	chessDotComUrl        string
-	awsConfig             *aws.Config
-	usersTableName        string
-	archivesTableName     string
-	downloadsTableName    string
-	downloadGamesQueueUrl string
-	metricsNamespace      string
-	downloadInfoExpiresIn time.Duration
+	GameSources           map[users.Platform]sources.GameSource
+	UsersTable            users.UsersRepository
+	ArchivesTable         archives.ArchivesRepository
+	DownloadsTable        downloads.DownloadsRepository
+	DownloadGamesQueue    queue.Publisher
+	MetricsNamespace      string
+	DownloadInfoExpiresIn time.Duration
 }
 
 func (downloader *ArchiveDownloader) DownloadArchiveAndDistributeDownloadGameCommands(
 	event *events.APIGatewayV2HTTPRequest,
 ) (responseEvent events.APIGatewayV2HTTPResponse, err error) {
-	logger := logging.MustCreateZuluTimeLogger()
+	ctx, span := tracing.StartRequest(event)
+	defer func() { tracing.End(span, err) }()
+
+	logger := tracing.Logger(ctx, logging.MustCreateZuluTimeLogger())
 	logger = logger.With(zap.String("requestId", event.RequestContext.RequestID))
 	defer logger.Sync()
 
-	awsSession, err := session.NewSession(downloader.awsConfig)
-	if err != nil {
-		logger.Panic("impossible to create an AWS session!")
-		return
-	}
-	dynamodbClient := dynamodb.New(awsSession)
-	sqsClient := sqs.New(awsSession)
-	cloudWatchClient := cloudwatch.New(awsSession)
-	chessDotComClient := &http.Client{}
-
 	method := event.RequestContext.HTTP.Method
 	path := event.RequestContext.HTTP.Path
 
@@ -75,37 +61,41 @@ func (downloader *ArchiveDownloader) DownloadArchiveAndDistributeDownloadGameCom
 
 	downloadRequest.Username = strings.ToLower(downloadRequest.Username)
 
-	logger = logger.With(zap.String("username", downloadRequest.Username), zap.String("platform", downloadRequest.Platform))
+	platform := users.Platform(strings.ToUpper(downloadRequest.Platform))
+	if platform == "" {
+		platform = users.ChessDotCom
+	}
 
-	usersTable := users.UsersTable{
-		Name:           downloader.usersTableName,
-		DynamodbClient: dynamodbClient,
+	gameSource, isSupported := downloader.GameSources[platform]
+	if !isSupported {
+		logger.Info("unsupported platform", zap.String("platform", downloadRequest.Platform))
+		err = UnsupportedPlatform(downloadRequest.Platform)
+		return
 	}
 
+	logger = logger.With(zap.String("username", downloadRequest.Username), zap.String("platform", downloadRequest.Platform))
+
 	logger.Info("checking for existing user record...")
 
 	var profile users.UserRecord
 
-	profileCandidate, err := usersTable.GetUserRecord(downloadRequest.Username, users.ChessDotCom)
+	usersCtx, usersSpan := tracing.StartCall(ctx, "UsersTable.GetUserRecord")
+	profileCandidate, err := downloader.UsersTable.GetUserRecord(usersCtx, downloadRequest.Username, platform)
+	tracing.End(usersSpan, err)
 
 	if err != nil {
 		logger.Error("impossible to get the user from the database", zap.Error(err))
 		return
 	}
 
-	chessDotComMeter := metrics.ChessDotComMeter{
-		Namespace:        downloader.metricsNamespace,
-		CloudWatchClient: cloudWatchClient,
-	}
-
 	if profileCandidate == nil {
-		logger.Info("user not found in the database. Downloading from chess.com ...")
-		profileCandidate, err = downloader.getAndPersistUser(dynamodbClient, chessDotComClient, chessDotComMeter, logger, downloadRequest)
+		logger.Info("user not found in the database. Downloading from the platform ...")
+		profileCandidate, err = downloader.getAndPersistUser(ctx, gameSource, logger, downloadRequest, platform)
 		if err != nil {
 			return
 		}
 		if profileCandidate == nil {
-			logger.Error("impossible to get the user from chess.com!")
+			logger.Error("impossible to get the user from the platform!")
 			err = api.ServiceOverloaded
 			return
 		}
@@ -117,10 +107,9 @@ func (downloader *ArchiveDownloader) DownloadArchiveAndDistributeDownloadGameCom
 
 	logger.Info("checking for existing download record...")
 
-	existingDownloadRecord, err := downloads.DownloadsTable{
-		Name:           downloader.downloadsTableName,
-		DynamodbClient: dynamodbClient,
-	}.GetDownloadRecord(downloads.NewDownloadId(profile.UserId).String())
+	downloadsCtx, downloadsSpan := tracing.StartCall(ctx, "DownloadsTable.GetDownloadRecord")
+	existingDownloadRecord, err := downloader.DownloadsTable.GetDownloadRecord(downloadsCtx, downloads.NewDownloadId(profile.UserId).String())
+	tracing.End(downloadsSpan, err)
 
 	if err != nil {
 		logger.Error("impossible to get the latest download record!", zap.Error(err))
@@ -140,8 +129,11 @@ func (downloader *ArchiveDownloader) DownloadArchiveAndDistributeDownloadGameCom
 
 	logger.Info("no download record found. Downloading...")
 
-	archivesFromChessDotCom, err := downloader.getArchivesFromChessDotCom(chessDotComClient, chessDotComMeter, logger, profile)
+	archivesCtx, archivesSpan := tracing.StartCall(ctx, string(platform)+" Archives")
+	archiveUrls, err := gameSource.Archives(archivesCtx, logger, profile.Username, now)
+	tracing.End(archivesSpan, err)
 	if err != nil {
+		err = gameSourceError(err, downloadRequest)
 		return
 	}
 
@@ -152,15 +144,14 @@ func (downloader *ArchiveDownloader) DownloadArchiveAndDistributeDownloadGameCom
 	if profile.DownloadFromScratch {
 		logger.Info("downloading from scratch is enabled. all archives will be downloaded. archives from database will be ignored")
 
-		missingArchiveUrls = archivesFromChessDotCom.Archives
+		missingArchiveUrls = archiveUrls
 		partaillyDownloadedArchives = []archives.ArchiveRecord{}
 	} else {
 		var archivesFromDb []archives.ArchiveRecord
 
-		archivesFromDb, err = archives.ArchivesTable{
-			Name:           downloader.archivesTableName,
-			DynamodbClient: dynamodbClient,
-		}.GetArchiveRecords(profile.UserId)
+		archivesFromDbCtx, archivesFromDbSpan := tracing.StartCall(ctx, "ArchivesTable.GetArchiveRecords")
+		archivesFromDb, err = downloader.ArchivesTable.GetArchiveRecords(archivesFromDbCtx, profile.UserId)
+		tracing.End(archivesFromDbSpan, err)
 
 		if err != nil {
 			logger.Error("impossible to get archives!", zap.Error(err))
@@ -169,38 +160,36 @@ func (downloader *ArchiveDownloader) DownloadArchiveAndDistributeDownloadGameCom
 
 		logger.Info("archives found from database", zap.Int("totalArchivesCount", len(archivesFromDb)))
 
-		missingArchiveUrls = resolveMissingArchives(archivesFromChessDotCom, archivesFromDb)
+		missingArchiveUrls = resolveMissingArchives(archiveUrls, archivesFromDb)
 		partaillyDownloadedArchives = resolvePartiallyDownloadedArchives(archivesFromDb)
 	}
 
-	missingArchives, err := downloader.persistMissingArchives(dynamodbClient, logger, profile, missingArchiveUrls)
+	missingArchives, err := downloader.persistMissingArchives(ctx, logger, profile, missingArchiveUrls)
 	if err != nil {
 		return
 	}
 
 	downloadId := downloads.NewDownloadId(profile.UserId)
 	total := len(missingArchives) + len(partaillyDownloadedArchives)
-	downloadRecord := downloads.NewDownloadRecord(downloadId, total, now, downloader.downloadInfoExpiresIn)
+	downloadRecord := downloads.NewDownloadRecord(downloadId, total, now, downloader.DownloadInfoExpiresIn)
 
-	err = downloads.DownloadsTable{
-		Name:           downloader.downloadsTableName,
-		DynamodbClient: dynamodbClient,
-	}.PutDownloadRecord(downloadRecord)
+	putDownloadCtx, putDownloadSpan := tracing.StartCall(ctx, "DownloadsTable.PutDownloadRecord")
+	err = downloader.DownloadsTable.PutDownloadRecord(putDownloadCtx, downloadRecord)
+	tracing.End(putDownloadSpan, err)
 
 	if err != nil {
 		logger.Error("impossible to persist the download record!", zap.Error(err))
 		return
 	}
 
-	err = downloader.publishDownloadGameCommands(logger, sqsClient, profile, downloadRecord, missingArchives, partaillyDownloadedArchives)
+	err = downloader.publishDownloadGameCommands(ctx, logger, event.RequestContext.RequestID, profile, downloadRecord, missingArchives, partaillyDownloadedArchives)
 	if err != nil {
 		return
 	}
 
-	err = users.UsersTable{
-		Name:           downloader.usersTableName,
-		DynamodbClient: dynamodbClient,
-	}.DownloadInitiated(profile.Username, profile.Platform)
+	initiatedCtx, initiatedSpan := tracing.StartCall(ctx, "UsersTable.DownloadInitiated")
+	err = downloader.UsersTable.DownloadInitiated(initiatedCtx, profile.Username, profile.Platform)
+	tracing.End(initiatedSpan, err)
 
 	if err != nil {
 		logger.Error("impossible to update the user record!", zap.Error(err))
@@ -216,80 +205,32 @@ func (downloader *ArchiveDownloader) DownloadArchiveAndDistributeDownloadGameCom
 }
 
 func (downloader ArchiveDownloader) getAndPersistUser(
-	dynamodbClient *dynamodb.DynamoDB,
-	chessDotComClient *http.Client,
-	chessDotComMeter metrics.ChessDotComMeter,
+	ctx context.Context,
+	gameSource sources.GameSource,
 	logger *zap.Logger,
 	downloadRequest DownloadRequest,
+	platform users.Platform,
 ) (userRecord *users.UserRecord, err error) {
-	url := downloader.chessDotComUrl + "/pub/player/" + downloadRequest.Username
-	logger = logger.With(zap.String("url", url))
-
-	request, err := http.NewRequest("GET", url, bytes.NewBuffer([]byte{}))
-
-	if err != nil {
-		logger.Error("impossible to create a request to chess.com!")
-		return
-	}
-
-	response, err := chessDotComClient.Do(request)
-	if err != nil {
-		return
-	}
-
-	defer response.Body.Close()
-
-	errFromMetricRegistration := chessDotComMeter.ChessDotComStatistics(metrics.GetProfile, response.StatusCode)
-	if errFromMetricRegistration != nil {
-		logger.Warn("impossible to register the metric ChessDotComStatistics", zap.Error(errFromMetricRegistration))
-	}
-
-	responseBodyBytes, err := io.ReadAll(response.Body)
-	if err != nil {
-		logger.Error("impossible to read the response body from chess.com!", zap.Error(err))
-		return
-	}
-
-	responseBodyString := string(responseBodyBytes)
-
-	if response.StatusCode == 404 {
-		logger.Error("profile not found on chess.com!", zap.String("responseBody", responseBodyString))
-		err = ProfileNotFound(downloadRequest)
-		return
-	}
-
-	if response.StatusCode != 200 {
-		logger.Error("unexpected status code from chess.com!", zap.Int("statusCode", response.StatusCode), zap.String("responseBody", responseBodyString))
-		err = api.ServiceOverloaded
-		return
-	}
-
-	profile := ChessDotComProfile{}
-	err = json.Unmarshal([]byte(responseBodyString), &profile)
+	profileCtx, profileSpan := tracing.StartCall(ctx, string(platform)+" Profile")
+	profile, err := gameSource.Profile(profileCtx, logger, downloadRequest.Username)
+	tracing.End(profileSpan, err)
 	if err != nil {
-		logger.Error("impossible to unmarshal the response body from chess.com!", zap.Error(err), zap.String("responseBody", responseBodyString), zap.String("url", url))
+		err = gameSourceError(err, downloadRequest)
 		return
 	}
 
-	logger.Info("profile found in chess.com")
-
-	usersTable := users.UsersTable{
-		Name:           downloader.usersTableName,
-		DynamodbClient: dynamodbClient,
-	}
-
-	logger.Info("user not found in the database")
-
 	userRecordCandidate := users.UserRecord{
 		Username:            downloadRequest.Username,
 		UserId:              profile.UserId,
-		Platform:            users.ChessDotCom,
+		Platform:            platform,
 		DownloadFromScratch: true,
 	}
 
 	logger.Info("persisting the user record in the database")
 
-	err = usersTable.PutUserRecord(userRecordCandidate)
+	usersCtx, usersSpan := tracing.StartCall(ctx, "UsersTable.PutUserRecord")
+	err = downloader.UsersTable.PutUserRecord(usersCtx, userRecordCandidate)
+	tracing.End(usersSpan, err)
 	if err != nil {
 		logger.Error("impossible to persist the user record in the database", zap.Error(err))
 		return
@@ -300,62 +241,8 @@ func (downloader ArchiveDownloader) getAndPersistUser(
 	return
 }
 
-func (downloader ArchiveDownloader) getArchivesFromChessDotCom(
-	chessDotComClient *http.Client,
-	chessDotComMeter metrics.ChessDotComMeter,
-	logger *zap.Logger,
-	user users.UserRecord,
-) (archives ChessDotComArchives, err error) {
-	url := downloader.chessDotComUrl + "/pub/player/" + user.Username + "/games/archives"
-	logger = logger.With(zap.String("url", url))
-	logger.Info("requesting chess.com for archives")
-	request, err := http.NewRequest("GET", url, bytes.NewBuffer([]byte{}))
-	if err != nil {
-		logger.Error("impossible to create a request to chess.com!")
-		return
-	}
-
-	response, err := chessDotComClient.Do(request)
-	if err != nil {
-		logger.Error("impossible to request chess.com!")
-		return
-	}
-
-	defer response.Body.Close()
-
-	errFromMetricRegistration := chessDotComMeter.ChessDotComStatistics(metrics.GetArchives, response.StatusCode)
-	if errFromMetricRegistration != nil {
-		logger.Error("impossible to register the metric ChessDotComStatistics", zap.Error(errFromMetricRegistration))
-	}
-
-	responseBodyBytes, err := io.ReadAll(response.Body)
-	if err != nil {
-		logger.Error("impossible to read the response body from chess.com!", zap.Error(err))
-		return
-	}
-
-	responseBodyString := string(responseBodyBytes)
-
-	if response.StatusCode != 200 {
-		logger.Error("unexpected status code from chess.com", zap.Int("statusCode", response.StatusCode), zap.String("responseBody", responseBodyString))
-		err = api.ServiceOverloaded
-		return
-	}
-
-	archives = ChessDotComArchives{}
-	err = json.Unmarshal([]byte(responseBodyString), &archives)
-	if err != nil {
-		logger.Error("impossible to unmarshal the response body from chess.com!", zap.Error(err), zap.String("responseBody", responseBodyString))
-		return
-	}
-
-	logger.Info("archives found from chess.com", zap.Int("existingArchivesCount", len(archives.Archives)))
-
-	return
-}
-
 func resolveMissingArchives(
-	archivesFromChessDotCom ChessDotComArchives,
+	archiveUrls []string,
 	archivesFromDb []archives.ArchiveRecord,
 ) (missingArchives []string) {
 	existingArchives := make(map[string]archives.ArchiveRecord, len(archivesFromDb))
@@ -364,9 +251,9 @@ func resolveMissingArchives(
 	}
 
 	missingArchives = make([]string, 0)
-	for _, archiveFromChessDotCom := range archivesFromChessDotCom.Archives {
-		if _, ok := existingArchives[archiveFromChessDotCom]; !ok {
-			missingArchives = append(missingArchives, archiveFromChessDotCom)
+	for _, archiveUrl := range archiveUrls {
+		if _, ok := existingArchives[archiveUrl]; !ok {
+			missingArchives = append(missingArchives, archiveUrl)
 		}
 	}
 	return
@@ -377,6 +264,9 @@ func resolvePartiallyDownloadedArchives(
 ) (archivesToDownload []archives.ArchiveRecord) {
 	archivesToDownload = make([]archives.ArchiveRecord, 0)
 	for _, archiveFromDb := range archivesFromDb {
+		if archiveFromDb.Uploaded {
+			continue
+		}
 		if archiveFromDb.DownloadedAt == nil {
 			archivesToDownload = append(archivesToDownload, archiveFromDb)
 			continue
@@ -390,7 +280,7 @@ func resolvePartiallyDownloadedArchives(
 }
 
 func (downloader ArchiveDownloader) persistMissingArchives(
-	dynamodbClient *dynamodb.DynamoDB,
+	ctx context.Context,
 	logger *zap.Logger,
 	user users.UserRecord,
 	missingArchiveUrls []string,
@@ -430,10 +320,9 @@ func (downloader ArchiveDownloader) persistMissingArchives(
 		missingArchiveRecords = append(missingArchiveRecords, missingArchiveRecord)
 	}
 
-	err = archives.ArchivesTable{
-		Name:           downloader.archivesTableName,
-		DynamodbClient: dynamodbClient,
-	}.PutArchiveRecords(missingArchiveRecords)
+	archivesCtx, archivesSpan := tracing.StartCall(ctx, "ArchivesTable.PutArchiveRecords")
+	err = downloader.ArchivesTable.PutArchiveRecords(archivesCtx, missingArchiveRecords)
+	tracing.End(archivesSpan, err)
 
 	if err != nil {
 		logger.Error("impossible to persist the missing archive records", zap.Error(err))
@@ -445,8 +334,9 @@ func (downloader ArchiveDownloader) persistMissingArchives(
 }
 
 func (downloader ArchiveDownloader) publishDownloadGameCommands(
+	ctx context.Context,
 	logger *zap.Logger,
-	svc *sqs.SQS,
+	requestId string,
 	user users.UserRecord,
 	downloadRecords downloads.DownloadRecord,
 	missingArchives []archives.ArchiveRecord,
@@ -460,23 +350,23 @@ func (downloader ArchiveDownloader) publishDownloadGameCommands(
 		logger := logger.With(zap.String("archiveId", archive.ArchiveId))
 		command := queue.DownloadGamesCommand{
 			Username:   user.Username,
-			Platform:   "CHESS_DOT_COM",
+			Platform:   queue.Platform(user.Platform),
 			ArchiveId:  archive.ArchiveId,
 			UserId:     archive.UserId,
 			DownloadId: downloadRecords.DownloadId.String(),
 		}
-		var jsonBody []byte
-		jsonBody, err = json.Marshal(command)
+		var body string
+		body, err = queue.DownloadGamesCodec.Encode(requestId, command)
 		if err != nil {
 			logger.Error("impossible to marshal the download game command!", zap.Error(err))
 			return err
 		}
 
-		_, err = svc.SendMessage(&sqs.SendMessageInput{
-			QueueUrl:               aws.String(downloader.downloadGamesQueueUrl),
-			MessageBody:            aws.String(string(jsonBody)),
-			MessageDeduplicationId: aws.String(archive.ArchiveId),
-			MessageGroupId:         aws.String(archive.UserId),
+		err = downloader.DownloadGamesQueue.Publish(queue.Message{
+			Body:            body,
+			DeduplicationId: archive.ArchiveId,
+			GroupId:         archive.UserId,
+			Attributes:      tracing.Inject(ctx),
 		})
 		if err != nil {
 			logger.Error("impossible to publish the download game command!", zap.Error(err))
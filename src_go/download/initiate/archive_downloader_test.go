@@ -1,6 +1,7 @@
-package main
+package initiate
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -20,6 +22,7 @@ import (
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/wiremock/go-wiremock"
@@ -32,13 +35,12 @@ var awsConfig = aws.Config{
 }
 
 var downloader = ArchiveDownloader{
-	downloadGamesQueueUrl: "http://localhost:4566/000000000000/chessfinder_sqs-DownloadGames.fifo",
-	chessDotComUrl:        "http://0.0.0.0:18443",
-	usersTableName:        "chessfinder_dynamodb-users",
-	archivesTableName:     "chessfinder_dynamodb-archives",
-	downloadsTableName:    "chessfinder_dynamodb-downloads",
-	downloadInfoExpiresIn: 24 * time.Hour,
-	awsConfig:             &awsConfig,
+	DownloadGamesQueue:    downloadGamesQueue,
+	GameSources:           sources.Platforms("http://0.0.0.0:18443", "http://0.0.0.0:18443", "", cloudwatch.New(awsSession)),
+	UsersTable:            usersTable,
+	ArchivesTable:         archivesTable,
+	DownloadsTable:        downloadsTable,
+	DownloadInfoExpiresIn: 24 * time.Hour,
 }
 
 var awsSession = session.Must(session.NewSession(&awsConfig))
@@ -46,20 +48,25 @@ var awsSession = session.Must(session.NewSession(&awsConfig))
 var dynamodbClient = dynamodb.New(awsSession)
 var svc = sqs.New(awsSession)
 
+var downloadGamesQueue = queue.SqsQueue{
+	QueueUrl:  "http://localhost:4566/000000000000/chessfinder_sqs-DownloadGames.fifo",
+	SqsClient: svc,
+}
+
 var wiremockClient = wiremock.NewClient("http://0.0.0.0:18443")
 
 var usersTable = users.UsersTable{
-	Name:           downloader.usersTableName,
+	Name:           "chessfinder_dynamodb-users",
 	DynamodbClient: dynamodbClient,
 }
 
 var downloadsTable = downloads.DownloadsTable{
-	Name:           downloader.downloadsTableName,
+	Name:           "chessfinder_dynamodb-downloads",
 	DynamodbClient: dynamodbClient,
 }
 
 var archivesTable = archives.ArchivesTable{
-	Name:           downloader.archivesTableName,
+	Name:           "chessfinder_dynamodb-archives",
 	DynamodbClient: dynamodbClient,
 }
 
@@ -148,7 +155,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_fo
 
 	downloadId := actualDownloadResponse.DownloadId
 
-	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(downloadId)
+	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualDownloadRecord)
 
@@ -162,8 +169,8 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_fo
 	assert.True(t, startOfTest.Before(actualDownloadRecord.LastDownloadedAt.ToTime()))
 	assert.True(t, startOfCheck.After(actualDownloadRecord.LastDownloadedAt.ToTime()))
 
-	assert.True(t, startOfTest.Add(downloader.downloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
-	assert.True(t, startOfCheck.Add(downloader.downloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfTest.Add(downloader.DownloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfCheck.Add(downloader.DownloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
 
 	verifyGetUsersProfileStub, err := wiremockClient.Verify(getUsersProfileStub.Request(), 1)
 	assert.NoError(t, err)
@@ -173,7 +180,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_fo
 	assert.NoError(t, err)
 	assert.Equal(t, true, verifyGetArchivesStub, "Stub of getting archives was not called!")
 
-	actualUserRecord, err := usersTable.GetUserRecord(username, users.ChessDotCom)
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, users.ChessDotCom)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualUserRecord)
 
@@ -187,7 +194,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_fo
 	assert.Equal(t, expectedUserRecord, *actualUserRecord)
 
 	archiveId_2021_10 := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
-	archive_2021_10, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_10)
+	archive_2021_10, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_10)
 	assert.NoError(t, err)
 	assert.NotNil(t, archive_2021_10)
 
@@ -204,7 +211,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_fo
 	assert.Equal(t, expectedArchive_2021_10, *archive_2021_10)
 
 	archiveId_2021_11 := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/11", username)
-	archive_2021_11, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_11)
+	archive_2021_11, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_11)
 	assert.NoError(t, err)
 	assert.NotNil(t, archive_2021_11)
 
@@ -221,7 +228,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_fo
 	assert.Equal(t, expectedArchive_2021_11, *archive_2021_11)
 
 	archiveId_2021_12 := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/12", username)
-	archive_2021_12, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_12)
+	archive_2021_12, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_12)
 	assert.NoError(t, err)
 	assert.NotNil(t, archive_2021_12)
 
@@ -235,15 +242,15 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_fo
 		Downloaded:   0,
 	}
 
-	assert.Equal(t, expectedArchive_2021_12, *archive_2021_12, fmt.Sprintf("Archive %v is not present in %v table!", archiveId_2021_12, downloader.archivesTableName))
+	assert.Equal(t, expectedArchive_2021_12, *archive_2021_12, fmt.Sprintf("Archive %v is not present in %v table!", archiveId_2021_12, archivesTable.Name))
 
-	lastThreeCommands, err := queue.GetLastNCommands(svc, downloader.downloadGamesQueueUrl, 3)
+	lastThreeCommands, err := queue.GetLastNCommands(svc, downloadGamesQueue.QueueUrl, 3)
 	assert.NoError(t, err)
 
 	actualCommands := make([]queue.DownloadGamesCommand, len(lastThreeCommands))
 	for i, message := range lastThreeCommands {
 		var command queue.DownloadGamesCommand
-		err = json.Unmarshal([]byte(*message.Body), &command)
+		_, err = queue.DownloadGamesCodec.Decode(*message.Body, &command)
 		if err != nil {
 			return
 		}
@@ -344,7 +351,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 		existingArchive_2021_11,
 		existingArchive_2021_12,
 	}
-	err = archivesTable.PutArchiveRecords(allArchives)
+	err = archivesTable.PutArchiveRecords(context.Background(), allArchives)
 	assert.NoError(t, err)
 
 	usersProfileReponseBody := fmt.Sprintf(
@@ -419,7 +426,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 
 	downloadId := actualDownloadResponse.DownloadId
 
-	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(downloadId)
+	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualDownloadRecord)
 
@@ -432,8 +439,8 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 	assert.True(t, startOfCheck.After(actualDownloadRecord.StartAt.ToTime()))
 	assert.True(t, startOfTest.Before(actualDownloadRecord.LastDownloadedAt.ToTime()))
 	assert.True(t, startOfCheck.After(actualDownloadRecord.LastDownloadedAt.ToTime()))
-	assert.True(t, startOfTest.Add(downloader.downloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
-	assert.True(t, startOfCheck.Add(downloader.downloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfTest.Add(downloader.DownloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfCheck.Add(downloader.DownloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
 
 	verifyGetUsersProfileStub, err := wiremockClient.Verify(getUsersProfileStub.Request(), 1)
 	assert.NoError(t, err)
@@ -443,7 +450,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 	assert.NoError(t, err)
 	assert.Equal(t, true, verifyGetArchivesStub, "Stub of getting archives was not called!")
 
-	actualUserRecord, err := usersTable.GetUserRecord(username, users.ChessDotCom)
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, users.ChessDotCom)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualUserRecord)
 
@@ -452,7 +459,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 
 	assert.Equal(t, expectedUserRecord, *actualUserRecord)
 
-	actualArchive_2021_10, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_10)
+	actualArchive_2021_10, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_10)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualArchive_2021_10)
 
@@ -468,7 +475,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 
 	assert.Equal(t, expectedArchive_2021_10, *actualArchive_2021_10)
 
-	actualArchive_2021_11, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_11)
+	actualArchive_2021_11, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_11)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualArchive_2021_11)
 
@@ -484,7 +491,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 
 	assert.Equal(t, expectedArchive_2021_11, *actualArchive_2021_11)
 
-	actualArchive_2021_12, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_12)
+	actualArchive_2021_12, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_12)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualArchive_2021_12)
 
@@ -498,15 +505,15 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 		Downloaded:   0,
 	}
 
-	assert.Equal(t, expectedArchive_2021_12, *actualArchive_2021_12, fmt.Sprintf("Archive %v is not present in %v table!", archiveId_2021_12, downloader.archivesTableName))
+	assert.Equal(t, expectedArchive_2021_12, *actualArchive_2021_12, fmt.Sprintf("Archive %v is not present in %v table!", archiveId_2021_12, archivesTable.Name))
 
-	lastThreeCommands, err := queue.GetLastNCommands(svc, downloader.downloadGamesQueueUrl, 3)
+	lastThreeCommands, err := queue.GetLastNCommands(svc, downloadGamesQueue.QueueUrl, 3)
 	assert.NoError(t, err)
 
 	actualCommands := make([]queue.DownloadGamesCommand, len(lastThreeCommands))
 	for i, message := range lastThreeCommands {
 		var command queue.DownloadGamesCommand
-		err = json.Unmarshal([]byte(*message.Body), &command)
+		_, err = queue.DownloadGamesCodec.Decode(*message.Body, &command)
 		if err != nil {
 			return
 		}
@@ -566,7 +573,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 		DownloadFromScratch: true,
 	}
 
-	err = usersTable.PutUserRecord(existingUserRecord)
+	err = usersTable.PutUserRecord(context.Background(), existingUserRecord)
 	assert.NoError(t, err)
 
 	archiveId_2021_10 := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
@@ -609,7 +616,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 		existingArchive_2021_11,
 		existingArchive_2021_12,
 	}
-	err = archivesTable.PutArchiveRecords(allArchives)
+	err = archivesTable.PutArchiveRecords(context.Background(), allArchives)
 	assert.NoError(t, err)
 
 	usersProfileReponseBody := fmt.Sprintf(
@@ -684,7 +691,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 
 	downloadId := actualDownloadResponse.DownloadId
 
-	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(downloadId)
+	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualDownloadRecord)
 
@@ -697,8 +704,8 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 	assert.True(t, startOfCheck.After(actualDownloadRecord.StartAt.ToTime()))
 	assert.True(t, startOfTest.Before(actualDownloadRecord.LastDownloadedAt.ToTime()))
 	assert.True(t, startOfCheck.After(actualDownloadRecord.LastDownloadedAt.ToTime()))
-	assert.True(t, startOfTest.Add(downloader.downloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
-	assert.True(t, startOfCheck.Add(downloader.downloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfTest.Add(downloader.DownloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfCheck.Add(downloader.DownloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
 
 	verifyGetUsersProfileStub, err := wiremockClient.Verify(getUsersProfileStub.Request(), 0)
 	assert.NoError(t, err)
@@ -708,7 +715,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 	assert.NoError(t, err)
 	assert.Equal(t, true, verifyGetArchivesStub, "Stub of getting archives was not called!")
 
-	actualUserRecord, err := usersTable.GetUserRecord(username, users.ChessDotCom)
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, users.ChessDotCom)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualUserRecord)
 
@@ -717,7 +724,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 
 	assert.Equal(t, expectedUserRecord, *actualUserRecord)
 
-	actualArchive_2021_10, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_10)
+	actualArchive_2021_10, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_10)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualArchive_2021_10)
 
@@ -733,7 +740,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 
 	assert.Equal(t, expectedArchive_2021_10, *actualArchive_2021_10)
 
-	actualArchive_2021_11, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_11)
+	actualArchive_2021_11, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_11)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualArchive_2021_11)
 
@@ -749,7 +756,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 
 	assert.Equal(t, expectedArchive_2021_11, *actualArchive_2021_11)
 
-	actualArchive_2021_12, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_12)
+	actualArchive_2021_12, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_12)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualArchive_2021_12)
 
@@ -763,15 +770,15 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_archives_as
 		Downloaded:   0,
 	}
 
-	assert.Equal(t, expectedArchive_2021_12, *actualArchive_2021_12, fmt.Sprintf("Archive %v is not present in %v table!", archiveId_2021_12, downloader.archivesTableName))
+	assert.Equal(t, expectedArchive_2021_12, *actualArchive_2021_12, fmt.Sprintf("Archive %v is not present in %v table!", archiveId_2021_12, archivesTable.Name))
 
-	lastThreeCommands, err := queue.GetLastNCommands(svc, downloader.downloadGamesQueueUrl, 3)
+	lastThreeCommands, err := queue.GetLastNCommands(svc, downloadGamesQueue.QueueUrl, 3)
 	assert.NoError(t, err)
 
 	actualCommands := make([]queue.DownloadGamesCommand, len(lastThreeCommands))
 	for i, message := range lastThreeCommands {
 		var command queue.DownloadGamesCommand
-		err = json.Unmarshal([]byte(*message.Body), &command)
+		_, err = queue.DownloadGamesCodec.Decode(*message.Body, &command)
 		if err != nil {
 			return
 		}
@@ -904,7 +911,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_missing_arc
 
 	downloadId := actualDownloadResponse.DownloadId
 
-	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(downloadId)
+	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualDownloadRecord)
 
@@ -917,8 +924,8 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_missing_arc
 	assert.True(t, startOfCheck.After(actualDownloadRecord.StartAt.ToTime()))
 	assert.True(t, startOfTest.Before(actualDownloadRecord.LastDownloadedAt.ToTime()))
 	assert.True(t, startOfCheck.After(actualDownloadRecord.LastDownloadedAt.ToTime()))
-	assert.True(t, startOfTest.Add(downloader.downloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
-	assert.True(t, startOfCheck.Add(downloader.downloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfTest.Add(downloader.DownloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfCheck.Add(downloader.DownloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
 
 	verifyGetUsersProfileStub, err := wiremockClient.Verify(getUsersProfileStub.Request(), 1)
 	assert.NoError(t, err)
@@ -928,7 +935,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_missing_arc
 	assert.NoError(t, err)
 	assert.Equal(t, true, verifyGetArchivesStub, "Stub of getting archives was not called!")
 
-	actualUserRecord, err := usersTable.GetUserRecord(username, users.ChessDotCom)
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, users.ChessDotCom)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualUserRecord)
 
@@ -937,7 +944,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_missing_arc
 	assert.Equal(t, expectedUserRecord, *actualUserRecord)
 
 	archiveId_2021_10 := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
-	archive_2021_10, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_10)
+	archive_2021_10, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_10)
 	assert.NoError(t, err)
 	assert.NotNil(t, archive_2021_10)
 
@@ -954,7 +961,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_missing_arc
 	assert.Equal(t, expectedArchive_2021_10, *archive_2021_10)
 
 	archiveId_2021_11 := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/11", username)
-	archive_2021_11, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_11)
+	archive_2021_11, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_11)
 	assert.NoError(t, err)
 	assert.NotNil(t, archive_2021_11)
 
@@ -971,7 +978,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_missing_arc
 	assert.Equal(t, expectedArchive_2021_11, *archive_2021_11)
 
 	archiveId_2021_12 := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/12", username)
-	archive_2021_12, err := archivesTable.GetArchiveRecord(userId, archiveId_2021_12)
+	archive_2021_12, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId_2021_12)
 	assert.NoError(t, err)
 	assert.NotNil(t, archive_2021_12)
 
@@ -985,15 +992,15 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_missing_arc
 		Downloaded:   0,
 	}
 
-	assert.Equal(t, expectedArchive_2021_12, *archive_2021_12, fmt.Sprintf("Archive %v is not present in %v table!", archiveId_2021_12, downloader.archivesTableName))
+	assert.Equal(t, expectedArchive_2021_12, *archive_2021_12, fmt.Sprintf("Archive %v is not present in %v table!", archiveId_2021_12, archivesTable.Name))
 
-	lastThreeCommands, err := queue.GetLastNCommands(svc, downloader.downloadGamesQueueUrl, 3)
+	lastThreeCommands, err := queue.GetLastNCommands(svc, downloadGamesQueue.QueueUrl, 3)
 	assert.NoError(t, err)
 
 	actualCommands := make([]queue.DownloadGamesCommand, len(lastThreeCommands))
 	for i, message := range lastThreeCommands {
 		var command queue.DownloadGamesCommand
-		err = json.Unmarshal([]byte(*message.Body), &command)
+		_, err = queue.DownloadGamesCodec.Decode(*message.Body, &command)
 		if err != nil {
 			return
 		}
@@ -1054,7 +1061,7 @@ func Test_ArchiveDownloader_should_return_id_of_the_previous_download_process_an
 		DownloadFromScratch: false,
 	}
 
-	err = usersTable.PutUserRecord(existingUserRecord)
+	err = usersTable.PutUserRecord(context.Background(), existingUserRecord)
 	assert.NoError(t, err)
 
 	previousDownloadId := downloads.NewDownloadId(userId)
@@ -1066,12 +1073,12 @@ func Test_ArchiveDownloader_should_return_id_of_the_previous_download_process_an
 		Done:             0,
 		Pending:          3,
 		Total:            3,
-		StartAt:          db.Zuludatetime(startOfTest.Add(-downloader.downloadInfoExpiresIn - time.Second)),
-		LastDownloadedAt: db.Zuludatetime(startOfTest.Add(-downloader.downloadInfoExpiresIn - time.Second)),
+		StartAt:          db.Zuludatetime(startOfTest.Add(-downloader.DownloadInfoExpiresIn - time.Second)),
+		LastDownloadedAt: db.Zuludatetime(startOfTest.Add(-downloader.DownloadInfoExpiresIn - time.Second)),
 		ExpiresAt:        dynamodbattribute.UnixTime(previouseDownloadExpiresAt),
 	}
 
-	err = downloadsTable.PutDownloadRecord(previousDownload)
+	err = downloadsTable.PutDownloadRecord(context.Background(), previousDownload)
 	assert.NoError(t, err)
 
 	usersProfileReponseBody := fmt.Sprintf(
@@ -1145,7 +1152,7 @@ func Test_ArchiveDownloader_should_return_id_of_the_previous_download_process_an
 
 	downloadId := actualDownloadResponse.DownloadId
 
-	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(downloadId)
+	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualDownloadRecord)
 
@@ -1189,7 +1196,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_partially_downl
 		DownloadFromScratch: false,
 	}
 
-	err = usersTable.PutUserRecord(existingUserRecord)
+	err = usersTable.PutUserRecord(context.Background(), existingUserRecord)
 	assert.NoError(t, err)
 
 	archiveId_2021_10 := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
@@ -1232,7 +1239,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_partially_downl
 		existingArchive_2021_11,
 		existingArchive_2021_12,
 	}
-	err = archivesTable.PutArchiveRecords(allArchives)
+	err = archivesTable.PutArchiveRecords(context.Background(), allArchives)
 	assert.NoError(t, err)
 
 	usersProfileReponseBody := fmt.Sprintf(
@@ -1307,7 +1314,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_partially_downl
 
 	downloadId := actualDownloadResponse.DownloadId
 
-	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(downloadId)
+	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualDownloadRecord)
 
@@ -1320,8 +1327,8 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_partially_downl
 	assert.True(t, startOfCheck.After(actualDownloadRecord.StartAt.ToTime()))
 	assert.True(t, startOfTest.Before(actualDownloadRecord.LastDownloadedAt.ToTime()))
 	assert.True(t, startOfCheck.After(actualDownloadRecord.LastDownloadedAt.ToTime()))
-	assert.True(t, startOfTest.Add(downloader.downloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
-	assert.True(t, startOfCheck.Add(downloader.downloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfTest.Add(downloader.DownloadInfoExpiresIn-time.Second).Before(time.Time(actualDownloadRecord.ExpiresAt)))
+	assert.True(t, startOfCheck.Add(downloader.DownloadInfoExpiresIn+time.Second).After(time.Time(actualDownloadRecord.ExpiresAt)))
 
 	verifyGetUsersProfileStub, err := wiremockClient.Verify(getUsersProfileStub.Request(), 0)
 	assert.NoError(t, err)
@@ -1332,7 +1339,7 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_partially_downl
 
 	assert.Equal(t, true, verifyGetArchivesStub, "Stub of getting archives was not called!")
 
-	actualUserRecord, err := usersTable.GetUserRecord(username, users.ChessDotCom)
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, users.ChessDotCom)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualUserRecord)
 
@@ -1340,13 +1347,13 @@ func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_partially_downl
 
 	assert.Equal(t, expectedUser, *actualUserRecord)
 
-	lastTwoCommands, err := queue.GetLastNCommands(svc, downloader.downloadGamesQueueUrl, 2)
+	lastTwoCommands, err := queue.GetLastNCommands(svc, downloadGamesQueue.QueueUrl, 2)
 	assert.NoError(t, err)
 
 	actualCommands := make([]queue.DownloadGamesCommand, len(lastTwoCommands))
 	for i, message := range lastTwoCommands {
 		var command queue.DownloadGamesCommand
-		err = json.Unmarshal([]byte(*message.Body), &command)
+		_, err = queue.DownloadGamesCodec.Decode(*message.Body, &command)
 		if err != nil {
 			return
 		}
@@ -1482,16 +1489,45 @@ func Test_ArchiveDownloader_should_return_error_if_user_is_not_found_in_chess_co
 
 }
 
+func Test_resolvePartiallyDownloadedArchives_should_never_download_uploaded_archives(t *testing.T) {
+	downloadedAt := db.Zuludatetime(time.Date(2023, time.October, 12, 0, 0, 0, 0, time.UTC))
+
+	partiallyDownloadedArchive := archives.ArchiveRecord{
+		UserId:       "https://api.chess.com/pub/player/tigran-c-137",
+		ArchiveId:    "https://api.chess.com/pub/player/tigran-c-137/games/2023/10",
+		Resource:     "https://api.chess.com/pub/player/tigran-c-137/games/2023/10",
+		Year:         2023,
+		Month:        10,
+		Downloaded:   12,
+		DownloadedAt: &downloadedAt,
+	}
+
+	uploadedArchive := archives.ArchiveRecord{
+		UserId:       "https://api.chess.com/pub/player/tigran-c-137",
+		ArchiveId:    "https://api.chess.com/pub/player/tigran-c-137/uploads/5e884898da280471",
+		Resource:     "https://api.chess.com/pub/player/tigran-c-137/uploads/5e884898da280471",
+		Year:         2023,
+		Month:        10,
+		Downloaded:   40,
+		DownloadedAt: &downloadedAt,
+		Uploaded:     true,
+	}
+
+	actualArchives := resolvePartiallyDownloadedArchives([]archives.ArchiveRecord{partiallyDownloadedArchive, uploadedArchive})
+
+	assert.Equal(t, []archives.ArchiveRecord{partiallyDownloadedArchive}, actualArchives)
+}
+
 func deleteAllDownloads() (err error) {
 	output, err := dynamodbClient.Scan(&dynamodb.ScanInput{
-		TableName: aws.String(downloader.downloadsTableName),
+		TableName: aws.String(downloadsTable.Name),
 	})
 	if err != nil {
 		return
 	}
 	for _, item := range output.Items {
 		_, err = dynamodbClient.DeleteItem(&dynamodb.DeleteItemInput{
-			TableName: aws.String(downloader.downloadsTableName),
+			TableName: aws.String(downloadsTable.Name),
 			Key: map[string]*dynamodb.AttributeValue{
 				"download_id": {
 					S: item["download_id"].S,
@@ -1,9 +0,0 @@
-package main
-
-type ChessDotComArchives struct {
-	Archives []string `json:"archives"`
-}
-
-type ChessDotComProfile struct {
-	UserId string `json:"@id"`
-}
@@ -1,9 +1,11 @@
-package main
+package initiate
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
 )
 
 type DownloadRequest struct {
@@ -22,6 +24,25 @@ func ProfileNotFound(user DownloadRequest) api.BusinessError {
 	}
 }
 
+func UnsupportedPlatform(platform string) api.BusinessError {
+	return api.BusinessError{
+		Message: fmt.Sprintf("Platform %v is not supported!", platform),
+		Code:    "UNSUPPORTED_PLATFORM",
+	}
+}
+
+// gameSourceError turns the errors of the platforms into the errors of the api.
+func gameSourceError(err error, downloadRequest DownloadRequest) error {
+	switch {
+	case errors.Is(err, sources.ErrProfileNotFound):
+		return ProfileNotFound(downloadRequest)
+	case errors.Is(err, sources.ErrUnexpectedStatus):
+		return api.ServiceOverloaded
+	default:
+		return err
+	}
+}
+
 var UserNameCannotBeEmpty = api.BusinessError{
 	Code:    "INVALID_USERNAME",
 	Message: "Username cannot be empty!",
@@ -3,6 +3,7 @@ module github.com/chessfinder/chessfinder-faster-backend/src_go/download/initiat
 go 1.21.1
 
 require (
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing v0.0.0-00010101000000-000000000000
 	github.com/aws/aws-lambda-go v1.41.0
 	github.com/aws/aws-sdk-go v1.45.24
 	github.com/chessfinder/chessfinder-faster-backend/src_go/details/api v0.0.0-20230921201148-2f6c15cfb0c9
@@ -11,6 +12,7 @@ require (
 	github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging v0.0.0-00010101000000-000000000000
 	github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics v0.0.0-00010101000000-000000000000
 	github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.8.4
 	github.com/wiremock/go-wiremock v1.8.0
 	go.uber.org/zap v1.26.0
@@ -39,3 +41,7 @@ replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/db => .
 replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics => ../../details/metrics
 
 replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging => ../../details/logging
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources => ../../details/sources
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing => ../../details/tracing
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/download/initiate"
+)
+
+func main() {
+
+	downloadsTableName, downloadsTableNameExists := os.LookupEnv("DOWNLOADS_TABLE_NAME")
+	if !downloadsTableNameExists {
+		panic(errors.New("DOWNLOADS_TABLE_NAME is missing"))
+	}
+
+	archivesTableName, archivesTableNameExists := os.LookupEnv("ARCHIVES_TABLE_NAME")
+	if !archivesTableNameExists {
+		panic(errors.New("ARCHIVES_TABLE_NAME is missing"))
+	}
+
+	usersTableName, usersTableNameExists := os.LookupEnv("USERS_TABLE_NAME")
+	if !usersTableNameExists {
+		panic(errors.New("USERS_TABLE_NAME is missing"))
+	}
+
+	chessDotComUrl, chessDotComUrlExists := os.LookupEnv("CHESS_DOT_COM_URL")
+	if !chessDotComUrlExists {
+		panic(errors.New("CHESS_DOT_COM_URL is missing"))
+	}
+
+	lichessUrl, lichessUrlExists := os.LookupEnv("LICHESS_URL")
+	if !lichessUrlExists {
+		panic(errors.New("LICHESS_URL is missing"))
+	}
+
+	downloadGamesQueueUrl, downloadGamesQueueUrlExists := os.LookupEnv("DOWNLOAD_GAMES_QUEUE_URL")
+	if !downloadGamesQueueUrlExists {
+		panic(errors.New("DOWNLOAD_GAMES_QUEUE_URL is missing"))
+	}
+
+	downloadInfoExpiresInCadidate, downloadInfoExpiresInExists := os.LookupEnv("DOWNLOAD_INFO_EXPIRES_IN_SECONDS")
+	if !downloadInfoExpiresInExists {
+		panic(errors.New("DOWNLOAD_INFO_EXPIRES_IN_SECONDS is missing"))
+	}
+
+	downloadInfoExpiresIn, err := time.ParseDuration(downloadInfoExpiresInCadidate + "s")
+	if err != nil {
+		panic(err)
+	}
+
+	theStackName, theStackNameExists := os.LookupEnv("THE_STACK_NAME")
+	if !theStackNameExists {
+		panic(errors.New("THE_STACK_NAME is missing"))
+	}
+
+	awsRegion, awsRegionExists := os.LookupEnv("AWS_REGION")
+	if !awsRegionExists {
+		panic(errors.New("AWS_REGION is missing"))
+	}
+
+	awsConfig := &aws.Config{
+		Region: &awsRegion,
+	}
+	awsSession := session.Must(session.NewSession(awsConfig))
+	cloudWatchClient := cloudwatch.New(awsSession)
+	dynamodbClient := dynamodb.New(awsSession)
+
+	checker := initiate.ArchiveDownloader{
+		GameSources: sources.Platforms(chessDotComUrl, lichessUrl, theStackName, cloudWatchClient),
+		DownloadsTable: downloads.DownloadsTable{
+			Name:           downloadsTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		UsersTable: users.UsersTable{
+			Name:           usersTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		ArchivesTable: archives.ArchivesTable{
+			Name:           archivesTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		DownloadGamesQueue: queue.SqsQueue{
+			QueueUrl:  downloadGamesQueueUrl,
+			SqsClient: sqs.New(awsSession),
+		},
+		MetricsNamespace:      theStackName,
+		DownloadInfoExpiresIn: downloadInfoExpiresIn,
+	}
+
+	tracing.MustSetUp("chessfinder-download-initiate")
+
+	lambda.Start(api.WithRecover(checker.DownloadArchiveAndDistributeDownloadGameCommands))
+}
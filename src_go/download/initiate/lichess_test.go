@@ -0,0 +1,161 @@
+package initiate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/wiremock/go-wiremock"
+)
+
+func Test_ArchiveDownloader_should_emit_DownloadGameCommands_for_all_months_of_a_new_lichess_user(t *testing.T) {
+	var err error
+
+	err = deleteAllDownloads()
+	assert.NoError(t, err)
+
+	defer wiremockClient.Reset()
+
+	username := strings.ReplaceAll(uuid.New().String(), "-", "")
+	userId := fmt.Sprintf("http://0.0.0.0:18443/@/%v", username)
+	now := time.Now().UTC()
+	createdAt := time.Date(now.Year(), now.Month(), 1, 12, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+
+	accountResponseBody := fmt.Sprintf(
+		`{
+			"id": "%v",
+			"username": "%v",
+			"createdAt": %v,
+			"seenAt": %v,
+			"playTime": {"total": 3600, "tv": 0}
+		}`,
+		username,
+		username,
+		createdAt.UnixMilli(),
+		now.UnixMilli(),
+	)
+
+	getAccountStub := wiremock.Get(wiremock.URLPathEqualTo(fmt.Sprintf("/api/user/%v", username))).
+		WillReturnResponse(
+			wiremock.NewResponse().
+				WithBody(accountResponseBody).
+				WithHeader("Content-Type", "application/json").
+				WithStatus(http.StatusOK),
+		)
+	err = wiremockClient.StubFor(getAccountStub)
+	assert.NoError(t, err)
+
+	event := events.APIGatewayV2HTTPRequest{
+		Body: fmt.Sprintf(`{"username":"%v", "platform": "LICHESS"}`, username),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/game",
+			},
+		},
+	}
+
+	actualResponse, err := downloader.DownloadArchiveAndDistributeDownloadGameCommands(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, actualResponse.StatusCode, "Response status code is not 200!")
+
+	actualDownloadResponse := DownloadResponse{}
+	err = json.Unmarshal([]byte(actualResponse.Body), &actualDownloadResponse)
+	assert.NoError(t, err)
+
+	downloadId := actualDownloadResponse.DownloadId
+
+	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
+	assert.NoError(t, err)
+	assert.NotNil(t, actualDownloadRecord)
+	assert.Equal(t, 2, actualDownloadRecord.Pending)
+	assert.Equal(t, 2, actualDownloadRecord.Total)
+
+	verifyGetAccountStub, err := wiremockClient.Verify(getAccountStub.Request(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, true, verifyGetAccountStub, fmt.Sprintf("Stub of getting lichess account %v was not called!", username))
+
+	actualUserRecord, err := usersTable.GetUserRecord(context.Background(), username, users.Lichess)
+	assert.NoError(t, err)
+	assert.NotNil(t, actualUserRecord)
+
+	expectedUserRecord := users.UserRecord{
+		UserId:              userId,
+		Platform:            users.Lichess,
+		Username:            username,
+		DownloadFromScratch: false,
+	}
+
+	assert.Equal(t, expectedUserRecord, *actualUserRecord)
+
+	expectedCommands := []queue.DownloadGamesCommand{}
+	for _, month := range []time.Time{createdAt, now} {
+		archiveId := fmt.Sprintf("http://0.0.0.0:18443/api/games/user/%v/%04d/%02d", username, month.Year(), int(month.Month()))
+
+		actualArchive, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId)
+		assert.NoError(t, err)
+		assert.NotNil(t, actualArchive)
+
+		expectedArchive := archives.ArchiveRecord{
+			UserId:       userId,
+			ArchiveId:    archiveId,
+			Resource:     archiveId,
+			Year:         month.Year(),
+			Month:        int(month.Month()),
+			DownloadedAt: nil,
+			Downloaded:   0,
+		}
+		assert.Equal(t, expectedArchive, *actualArchive)
+
+		expectedCommands = append(expectedCommands, queue.DownloadGamesCommand{
+			Username:   username,
+			Platform:   queue.Lichess,
+			UserId:     userId,
+			ArchiveId:  archiveId,
+			DownloadId: downloadId,
+		})
+	}
+
+	lastTwoCommands, err := queue.GetLastNCommands(svc, downloadGamesQueue.QueueUrl, 2)
+	assert.NoError(t, err)
+
+	actualCommands := make([]queue.DownloadGamesCommand, len(lastTwoCommands))
+	for i, message := range lastTwoCommands {
+		var command queue.DownloadGamesCommand
+		_, err = queue.DownloadGamesCodec.Decode(*message.Body, &command)
+		assert.NoError(t, err)
+		actualCommands[i] = command
+	}
+
+	assert.ElementsMatch(t, expectedCommands, actualCommands)
+}
+
+func Test_ArchiveDownloader_should_return_error_if_the_platform_is_not_supported(t *testing.T) {
+	event := events.APIGatewayV2HTTPRequest{
+		Body: `{"username":"tigran-c-137", "platform": "FICS"}`,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/game",
+			},
+		},
+	}
+
+	actualResponse, err := api.WithRecover(downloader.DownloadArchiveAndDistributeDownloadGameCommands)(&event)
+	assert.NoError(t, err)
+	expectedResponseBody := `{"code": "UNSUPPORTED_PLATFORM", "message":"Platform FICS is not supported!"}`
+
+	assert.Equal(t, http.StatusUnprocessableEntity, actualResponse.StatusCode, "Response status code is not 422!")
+	assert.JSONEq(t, expectedResponseBody, actualResponse.Body, "Response body is not as expected!")
+}
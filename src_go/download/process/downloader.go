@@ -1,69 +1,64 @@
-package main
+package process
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"io"
-	"net/http"
-	"net/url"
-	"strconv"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
 	"go.uber.org/zap"
 )
 
 type GameDownloader struct {
-	chessDotComUrl               string
-	downloadsTableName           string
-	archivesTableName            string
-	gamesTableName               string
-	gamesByEndTimestampIndexName string
-	metricsNamespace             string
-	pgnFilter                    PgnFilter
-	downloadInfoExpiresIn        time.Duration
-	awsConfig                    *aws.Config
+	GameSources           map[users.Platform]sources.GameSource
+	DownloadsTable        downloads.DownloadsRepository
+	ArchivesTable         archives.ArchivesRepository
+	GamesTable            games.GamesRepository
+	LatestGameIndex       games.LatestGameRepository
+	MetricsNamespace      string
+	PgnFilter             PgnFilter
+	EncodeMoves           bool
+	DownloadInfoExpiresIn time.Duration
+	CloudWatchClient      *cloudwatch.CloudWatch
+	Concurrency           int
+	DeadLetters           queue.Publisher
+	MaxReceiveCount       int // of the redrive policy, a failure is counted in the download on the last delivery only
 }
 
 func (downloader *GameDownloader) Download(commands events.SQSEvent) (events.SQSEventResponse, error) {
 	logger := logging.MustCreateZuluTimeLogger()
 	defer logger.Sync()
-	failedEvents := queue.ProcessMultiple(commands, downloader, logger)
+	failedEvents := queue.BatchProcessor{Concurrency: downloader.Concurrency, DeadLetters: downloader.DeadLetters}.Process(commands, downloader, logger)
 	return failedEvents, nil
 }
 
 func (downloader *GameDownloader) ProcessSingle(
+	ctx context.Context,
 	message *events.SQSMessage,
 	logger *zap.Logger,
-) (commandProcessed *events.SQSBatchItemFailure, err error) {
-
-	awsSession, err := session.NewSession(downloader.awsConfig)
-	if err != nil {
-		logger.Error("impossible to create an AWS session!")
-		return
-	}
-	dynamodbClient := dynamodb.New(awsSession)
-	chessDotComClient := &http.Client{}
-	cloudWatchClient := cloudwatch.New(awsSession)
+) (err error) {
 
 	command := queue.DownloadGamesCommand{}
-	err = json.Unmarshal([]byte(message.Body), &command)
+	envelope, err := queue.DownloadGamesCodec.Decode(message.Body, &command)
 	if err != nil {
 		logger.Error("impossible to unmarshal the command", zap.Error(err))
 		return
 	}
 
+	logger = logger.With(zap.String("correlationId", envelope.CorrelationId))
+
 	logger = logger.With(zap.String("userId", command.UserId))
 	logger = logger.With(zap.String("archiveId", command.ArchiveId))
 	logger = logger.With(zap.String("downloadId", command.DownloadId))
@@ -72,55 +67,105 @@ func (downloader *GameDownloader) ProcessSingle(
 
 	logger.Info("Processing command")
 
-	downloadsTable := downloads.DownloadsTable{
-		Name:           downloader.downloadsTableName,
-		DynamodbClient: dynamodbClient,
-	}
-
 	now := time.Now()
 	nowInZulu := db.Zuludatetime(now)
 
 	incrementDownloadStatus := func(incrementSuccess bool) (err error) {
 
 		logger.Info("incrementing the download status")
-		downloadRecord, err := downloadsTable.GetDownloadRecord(command.DownloadId)
 
-		if err != nil {
-			logger.Error("impossible to get the download record", zap.Error(err))
-			return
+		callCtx, span := tracing.StartCall(ctx, "DownloadsTable.Increment")
+		if incrementSuccess {
+			err = downloader.DownloadsTable.IncrementSuccess(callCtx, command.DownloadId, nowInZulu, downloader.DownloadInfoExpiresIn)
+		} else {
+			err = downloader.DownloadsTable.IncrementFailure(callCtx, command.DownloadId, nowInZulu, downloader.DownloadInfoExpiresIn)
 		}
+		tracing.End(span, err)
 
-		if downloadRecord == nil {
-			logger.Error("download record not found")
+		if errors.Is(err, downloads.ErrDownloadAlreadyDone) {
+			// the download is gone or every archive is already counted, a retry of the command cannot fix it
+			logger.Error("inconsistent download record", zap.Error(err))
+			err = nil
 			return
 		}
 
-		if downloadRecord.Total <= downloadRecord.Done {
-			logger.Error("inconsitent download record", zap.Int("total", downloadRecord.Total), zap.Int("done", downloadRecord.Done))
+		if err != nil {
+			logger.Error("impossible to update download record", zap.Error(err))
 			return
 		}
 
-		if incrementSuccess {
-			err = downloadsTable.IncrementSuccess(*downloadRecord, nowInZulu, downloader.downloadInfoExpiresIn)
-		} else {
-			err = downloadsTable.IncrementFailure(*downloadRecord, nowInZulu, downloader.downloadInfoExpiresIn)
+		return
+	}
+
+	putGames := func(gameRecords []games.GameRecord) (err error) {
+		for i, gameRecord := range gameRecords {
+			pgnString, errFromFiltering := downloader.PgnFilter.Filter(gameRecord.Pgn)
+			if errFromFiltering != nil {
+				logger.Warn("impossible to filter the pgn", zap.Error(errFromFiltering))
+				pgnString = gameRecord.Pgn
+			}
+			gameRecords[i].Pgn = pgnString
+
+			if downloader.EncodeMoves {
+				// the original pgn still has the FEN tag of a game from a non standard setup
+				encodedMoves, errFromEncoding := chess.EncodePgn(gameRecord.Pgn)
+				if errFromEncoding != nil {
+					logger.Warn("impossible to encode the moves, the pgn is kept", zap.String("gameId", gameRecord.GameId), zap.Error(errFromEncoding))
+					continue
+				}
+				gameRecords[i].Moves = encodedMoves
+				gameRecords[i].Pgn = ""
+			}
+		}
+
+		if len(gameRecords) > 0 {
+			callCtx, span := tracing.StartCall(ctx, "GamesTable.PutGameRecords")
+			err = downloader.GamesTable.PutGameRecords(callCtx, gameRecords)
+			tracing.End(span, err)
+
+			if err != nil {
+				logger.Error("impossible to persist the missing game records", zap.Error(err))
+				return
+			}
 		}
+		return
+	}
+
+	completeArchive := func(archiveRecord *archives.ArchiveRecord, persistedGames int) (err error) {
+		archiveRecord.DownloadedAt = &nowInZulu
+		archiveRecord.Downloaded += persistedGames
+
+		logger.Info("updating the archive record")
+
+		callCtx, span := tracing.StartCall(ctx, "ArchivesTable.PutArchiveRecord")
+		err = downloader.ArchivesTable.PutArchiveRecord(callCtx, *archiveRecord)
+		tracing.End(span, err)
 
 		if err != nil {
-			logger.Error("impossible to update download record", zap.Error(err))
+			logger.Error("impossible to update the archive record", zap.Error(err))
 			return
 		}
 
+		errOfIncrement := incrementDownloadStatus(true)
+		if errOfIncrement != nil {
+			logger.Error("impossible to increment the download status", zap.Error(err))
+		}
+
 		return
 	}
 
-	archivesTable := archives.ArchivesTable{
-		Name:           downloader.archivesTableName,
-		DynamodbClient: dynamodbClient,
+	persistGames := func(archiveRecord *archives.ArchiveRecord, gameRecords []games.GameRecord) (err error) {
+		err = putGames(gameRecords)
+		if err != nil {
+			return
+		}
+		return completeArchive(archiveRecord, len(gameRecords))
 	}
 
 	unsafeProcessSingle := func() (err error) {
-		archiveRecord, err := archivesTable.GetArchiveRecord(command.UserId, command.ArchiveId)
+		archiveCtx, archiveSpan := tracing.StartCall(ctx, "ArchivesTable.GetArchiveRecord")
+		archiveRecord, err := downloader.ArchivesTable.GetArchiveRecord(archiveCtx, command.UserId, command.ArchiveId)
+		tracing.End(archiveSpan, err)
 
 		if err != nil {
 			logger.Error("impossible to get the archive record", zap.Error(err))
@@ -136,6 +181,15 @@ func (downloader *GameDownloader) ProcessSingle(
 			return
 		}
 
+		if archiveRecord.Uploaded {
+			uploadedGames := sources.UploadedGames(command.Username, *archiveRecord, sources.SplitGames(command.Pgn), command.FirstGame)
+			logger = logger.With(zap.Int("firstGame", command.FirstGame), zap.Int("uploadedGames", len(uploadedGames)))
+			logger.Info("persisiting uploaded games")
+
+			err = persistGames(archiveRecord, uploadedGames)
+			return
+		}
+
 		archiveHasGamesTill := time.Date(archiveRecord.Year, time.Month(archiveRecord.Month), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
 		if archiveRecord.DownloadedAt != nil && !archiveRecord.DownloadedAt.ToTime().Before(archiveHasGamesTill) {
 			logger.Info("archive already downloaded")
@@ -146,72 +200,81 @@ func (downloader *GameDownloader) ProcessSingle(
 			return
 		}
 
-		chessDotComGames := ChessDotComGames{}
-		// make this validation while reading envarionment variables
-		requestUrl, err := url.ParseRequestURI(downloader.chessDotComUrl)
-		if err != nil {
-			logger.Error("impossible to parse the chess.com url", zap.Error(err))
-			return
-		}
-		monthInString := strconv.Itoa(archiveRecord.Month)
-		if len(monthInString) == 1 {
-			monthInString = "0" + monthInString
+		platform := users.Platform(command.Platform)
+		if platform == "" {
+			platform = users.ChessDotCom
 		}
-		requestUrl.Path = "/pub/player/" + command.Username + "/games/" + strconv.Itoa(archiveRecord.Year) + "/" + monthInString
-		url := requestUrl.String()
-		logger.Info("requesting games", zap.String("url", url))
 
-		downloadGamesRequest, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			logger.Error("Error while creating request to download games", zap.Error(err))
+		gameSource, isSupported := downloader.GameSources[platform]
+		if !isSupported {
+			logger.Error("unsupported platform")
+			err = queue.Permanent(errors.New("unsupported platform"))
 			return
 		}
-		downloadGamesRequest.Header["Accept"] = []string{"application/json"}
-		response, err := chessDotComClient.Do(downloadGamesRequest)
-		if err != nil {
-			logger.Error("impossible to get the games", zap.Error(err))
-			return
-		}
-		defer response.Body.Close()
 
-		chessDotComMeter := metrics.ChessDotComMeter{
-			Namespace:        downloader.metricsNamespace,
-			CloudWatchClient: cloudWatchClient,
-		}
+		// the watermark only moves once the archive is complete, the games of a run that failed halfway are downloaded again
+		watermark := archiveRecord.LastGameEndTimestamp
+		if watermark == 0 && archiveRecord.Downloaded > 0 {
+			// the archive was downloaded before the watermark was kept on its record
+			callCtx, span := tracing.StartCall(ctx, "LatestGameIndex.QueryByEndTimestamp")
+			latestDownloadedGameRecord, errFromQuery := downloader.LatestGameIndex.QueryByEndTimestamp(callCtx, command.ArchiveId)
+			tracing.End(span, errFromQuery)
 
-		errFromMetricRegistration := chessDotComMeter.ChessDotComStatistics(metrics.GetGames, response.StatusCode)
-		if errFromMetricRegistration != nil {
-			logger.Error("impossible to register the metric ChessDotComStatistics", zap.Error(errFromMetricRegistration))
+			if errFromQuery != nil {
+				err = errFromQuery
+				logger.Error("impossible to get the latest downloaded game", zap.Error(err))
+				return
+			}
+			if latestDownloadedGameRecord != nil {
+				watermark = latestDownloadedGameRecord.EndTimestamp
+			}
 		}
+		lastGameEndTimestamp := watermark
+
+		allDownloadedGames := 0
+		missingGames := 0
+		gamesCtx, gamesSpan := tracing.StartCall(ctx, string(platform)+" Games")
+		validators, err := gameSource.Games(gamesCtx, logger, command.Username, *archiveRecord, func(downloadedGames []games.GameRecord) (err error) {
+			allDownloadedGames += len(downloadedGames)
+
+			missingGameRecords := []games.GameRecord{}
+			for _, gameRecord := range downloadedGames {
+				if gameRecord.EndTimestamp > watermark {
+					missingGameRecords = append(missingGameRecords, gameRecord)
+				}
+				if gameRecord.EndTimestamp > lastGameEndTimestamp {
+					lastGameEndTimestamp = gameRecord.EndTimestamp
+				}
+			}
 
-		downloadedGamesMeter := metrics.DownloadMeter{
-			Namespace:        downloader.metricsNamespace,
-			CloudWatchClient: cloudWatchClient,
+			missingGames += len(missingGameRecords)
+			return putGames(missingGameRecords)
+		})
+		tracing.End(gamesSpan, err)
+		if errors.Is(err, sources.ErrArchiveNotModified) {
+			logger.Info("archive already up to date")
+			err = completeArchive(archiveRecord, 0)
+			return
+		}
+		if errors.As(err, &sources.NotFoundError{}) {
+			err = queue.Permanent(err)
 		}
-
-		responseBodyBytes, err := io.ReadAll(response.Body)
 		if err != nil {
-			logger.Error("impossible to read the response body from chess.com!", zap.Error(err))
 			return
 		}
 
-		responseBodyString := string(responseBodyBytes)
+		archiveRecord.Etag = validators.Etag
+		archiveRecord.LastModified = validators.LastModified
+		archiveRecord.LastGameEndTimestamp = lastGameEndTimestamp
 
-		if response.StatusCode != 200 {
-			logger.Error("unexpected status code from chess.com", zap.Int("statusCode", response.StatusCode), zap.String("responseBody", responseBodyString))
-			err = errors.New("unexpected status code from chess.com")
-			return
-		}
-
-		err = json.Unmarshal(responseBodyBytes, &chessDotComGames)
-		if err != nil {
-			logger.Error("impossible to unmarshal the games", zap.Error(err))
-			return
+		downloadedGamesMeter := metrics.DownloadMeter{
+			Namespace:        downloader.MetricsNamespace,
+			CloudWatchClient: downloader.CloudWatchClient,
 		}
 
-		logger = logger.With(zap.Int("allDownloadedGames", len(chessDotComGames.Games)))
+		logger = logger.With(zap.Int("allDownloadedGames", allDownloadedGames))
 
-		if len(chessDotComGames.Games) == 0 {
+		if allDownloadedGames == 0 {
 			logger.Info("no games found")
 			errOfIncrement := incrementDownloadStatus(true)
 			if errOfIncrement != nil {
@@ -220,83 +283,26 @@ func (downloader *GameDownloader) ProcessSingle(
 			return
 		}
 
-		errOfDownloadedGamesMetricRegistration := downloadedGamesMeter.SearchStatistics(len(chessDotComGames.Games))
+		errOfDownloadedGamesMetricRegistration := downloadedGamesMeter.SearchStatistics(allDownloadedGames)
 		if errOfDownloadedGamesMetricRegistration != nil {
 			logger.Error("error while registering amount of downloaded games metric", zap.Error(errOfDownloadedGamesMetricRegistration))
 		}
 
-		var latestDownloadedGameRecord *games.GameRecord
-		if archiveRecord.Downloaded > 0 {
-			latestDownloadedGameRecord, err =
-				games.LatestGameIndex{
-					Name:           downloader.gamesByEndTimestampIndexName,
-					TableName:      downloader.gamesTableName,
-					DynamodbClient: dynamodbClient,
-				}.QueryByEndTimestamp(command.ArchiveId)
-
-			if err != nil {
-				logger.Error("impossible to get the latest downloaded game", zap.Error(err))
-				return
-			}
-		}
-
-		missingGameRecords := []games.GameRecord{}
-		for _, chessDotComGame := range chessDotComGames.Games {
-			isGameMissing := latestDownloadedGameRecord == nil || (latestDownloadedGameRecord != nil && chessDotComGame.EndTime > latestDownloadedGameRecord.EndTimestamp)
-			if isGameMissing {
-				pgnString, errFromFiltering := downloader.pgnFilter.Filter(chessDotComGame.Pgn)
-				if errFromFiltering != nil {
-					logger.Warn("impossible to filter the pgn", zap.Error(errFromFiltering))
-					pgnString = chessDotComGame.Pgn
-				}
-				gameRecord := games.GameRecord{
-					UserId:       command.UserId,
-					ArchiveId:    command.ArchiveId,
-					GameId:       chessDotComGame.Url,
-					Resource:     chessDotComGame.Url,
-					Pgn:          pgnString,
-					EndTimestamp: chessDotComGame.EndTime,
-				}
-				missingGameRecords = append(missingGameRecords, gameRecord)
-			}
-		}
-
-		logger = logger.With(zap.Int("missingGames", len(missingGameRecords)))
-		logger.Info("persisiting missing games")
-
-		err = games.GamesTable{
-			Name:           downloader.gamesTableName,
-			DynamodbClient: dynamodbClient,
-		}.PutGameRecords(missingGameRecords)
-
-		if err != nil {
-			logger.Error("impossible to persist the missing game records", zap.Error(err))
-			return
-		}
-
-		archiveRecord.DownloadedAt = &nowInZulu
-		archiveRecord.Downloaded += int(len(missingGameRecords))
-
-		logger.Info("updating the archive record")
-
-		err = archivesTable.PutArchiveRecord(*archiveRecord)
-
-		if err != nil {
-			logger.Error("impossible to update the archive record", zap.Error(err))
-			return
-		}
-
-		errOfIncrement := incrementDownloadStatus(true)
-		if errOfIncrement != nil {
-			logger.Error("impossible to increment the download status", zap.Error(err))
-		}
+		logger = logger.With(zap.Int("missingGames", missingGames))
+		logger.Info("missing games persisted")
 
+		err = completeArchive(archiveRecord, missingGames)
 		return
 	}
 
 	err = unsafeProcessSingle()
+	if err != nil && !queue.IsPermanent(err) && !queue.IsLastDelivery(message, downloader.MaxReceiveCount) {
+		logger.Warn("impossible to process the command, it will be retried", zap.Error(err))
+		return
+	}
 	if err != nil {
 		logger.Error("impossible to process the command", zap.Error(err))
+		err = queue.Permanent(err)
 		errOfIncrement := incrementDownloadStatus(false)
 		if errOfIncrement != nil {
 			logger.Error("impossible to increment the download status", zap.Error(err))
@@ -3,12 +3,16 @@ module github.com/chessfinder/chessfinder-faster-backend/src_go/download/process
 go 1.21.1
 
 require (
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing v0.0.0-00010101000000-000000000000
 	github.com/aws/aws-lambda-go v1.41.0
 	github.com/aws/aws-sdk-go v1.46.1
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess v0.0.0-00010101000000-000000000000
 	github.com/chessfinder/chessfinder-faster-backend/src_go/details/db v0.0.0-20231013195809-b1378607bcce
 	github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging v0.0.0-00010101000000-000000000000
 	github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics v0.0.0-00010101000000-000000000000
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/pgn v0.0.0-00010101000000-000000000000
 	github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue v0.0.0-20231013195809-b1378607bcce
+	github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources v0.0.0-00010101000000-000000000000
 	github.com/google/uuid v1.3.1
 	github.com/stretchr/testify v1.8.4
 	github.com/wiremock/go-wiremock v1.8.0
@@ -36,3 +40,11 @@ replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/db => .
 replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics => ../../details/metrics
 
 replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging => ../../details/logging
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources => ../../details/sources
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/pgn => ../../details/pgn
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess => ../../details/chess
+
+replace github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing => ../../details/tracing
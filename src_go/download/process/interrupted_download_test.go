@@ -0,0 +1,154 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// interruptedGameSource hands the first chunk of the games and then fails on its first stream, the next streams hand all the games.
+type interruptedGameSource struct {
+	sources.GameSource
+	chunks  [][]games.GameRecord
+	streams int
+}
+
+func (source *interruptedGameSource) Games(
+	ctx context.Context,
+	logger *zap.Logger,
+	username string,
+	archive archives.ArchiveRecord,
+	consume func(gameRecords []games.GameRecord) error,
+) (validators sources.ArchiveValidators, err error) {
+	source.streams++
+	for i, chunk := range source.chunks {
+		if source.streams == 1 && i == 1 {
+			err = errors.New("the connection is reset")
+			return
+		}
+		err = consume(chunk)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func Test_when_the_stream_fails_halfway_GameDownloader_should_persist_every_missing_game_on_the_retry(t *testing.T) {
+	userId := uuid.New().String()
+	archiveId := uuid.New().String()
+	username := "tigran-c-137"
+
+	gameRecord := func(number int) games.GameRecord {
+		return games.GameRecord{
+			UserId:       userId,
+			ArchiveId:    archiveId,
+			GameId:       fmt.Sprintf("https://www.chess.com/game/live/%d", number),
+			Resource:     fmt.Sprintf("https://www.chess.com/game/live/%d", number),
+			Pgn:          fmt.Sprintf("[Event \"Live Chess\"]\n\n1. e4 e5 %d. Nf3 1-0", number),
+			EndTimestamp: int64(1659312000 + number),
+		}
+	}
+
+	gamesTable := games.NewInMemoryGamesTable()
+	err := gamesTable.PutGameRecords(context.Background(), []games.GameRecord{gameRecord(1), gameRecord(2)})
+	assert.NoError(t, err)
+
+	downloadedAt := db.Zuludatetime(time.Date(2022, 8, 15, 0, 0, 0, 0, time.UTC))
+	archivesTable := archives.NewInMemoryArchivesTable()
+	err = archivesTable.PutArchiveRecord(context.Background(), archives.ArchiveRecord{
+		UserId:               userId,
+		ArchiveId:            archiveId,
+		Resource:             archiveId,
+		Year:                 2022,
+		Month:                8,
+		DownloadedAt:         &downloadedAt,
+		Downloaded:           2,
+		LastGameEndTimestamp: gameRecord(2).EndTimestamp,
+	})
+	assert.NoError(t, err)
+
+	downloadId := downloads.NewDownloadId(userId)
+	downloadsTable := downloads.NewInMemoryDownloadsTable()
+	err = downloadsTable.PutDownloadRecord(context.Background(), downloads.NewDownloadRecord(downloadId, 1, time.Now(), 24*time.Hour))
+	assert.NoError(t, err)
+
+	gameSource := &interruptedGameSource{
+		chunks: [][]games.GameRecord{
+			{gameRecord(1), gameRecord(2), gameRecord(3), gameRecord(4)},
+			{gameRecord(5), gameRecord(6)},
+		},
+	}
+
+	interruptedDownloader := GameDownloader{
+		GameSources:           map[users.Platform]sources.GameSource{users.ChessDotCom: gameSource},
+		DownloadsTable:        downloadsTable,
+		ArchivesTable:         archivesTable,
+		GamesTable:            gamesTable,
+		LatestGameIndex:       gamesTable,
+		PgnFilter:             IdentityPgnFilter{},
+		DownloadInfoExpiresIn: 24 * time.Hour,
+		MaxReceiveCount:       2,
+	}
+
+	command := queue.DownloadGamesCommand{
+		Username:   username,
+		UserId:     userId,
+		Platform:   queue.ChessDotCom,
+		ArchiveId:  archiveId,
+		DownloadId: downloadId.String(),
+	}
+	commandBody, err := json.Marshal(command)
+	assert.NoError(t, err)
+
+	failedCommands, err := interruptedDownloader.Download(events.SQSEvent{Records: []events.SQSMessage{{
+		Body:       string(commandBody),
+		MessageId:  "1",
+		Attributes: map[string]string{"ApproximateReceiveCount": "1"},
+	}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "1"}}, failedCommands.BatchItemFailures)
+
+	interruptedArchive, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, interruptedArchive.Downloaded)
+	assert.Equal(t, gameRecord(2).EndTimestamp, interruptedArchive.LastGameEndTimestamp)
+
+	retriedCommands, err := interruptedDownloader.Download(events.SQSEvent{Records: []events.SQSMessage{{
+		Body:       string(commandBody),
+		MessageId:  "1",
+		Attributes: map[string]string{"ApproximateReceiveCount": "2"},
+	}}})
+	assert.NoError(t, err)
+	assert.Equal(t, events.SQSEventResponse{BatchItemFailures: nil}, retriedCommands)
+
+	actualArchive, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, actualArchive.Downloaded)
+	assert.Equal(t, gameRecord(6).EndTimestamp, actualArchive.LastGameEndTimestamp)
+
+	var noKey games.PageKey
+	actualGames, _, err := gamesTable.QueryGames(context.Background(), userId, games.GameFilter{}, noKey, 1000)
+	assert.NoError(t, err)
+	assert.Len(t, actualGames, 6)
+
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, actualDownload.Succeed)
+	assert.Equal(t, 0, actualDownload.Failed)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/blobs"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/download/process"
+)
+
+func main() {
+
+	chessDotComUrl, chessDotComUrlExists := os.LookupEnv("CHESS_DOT_COM_URL")
+	if !chessDotComUrlExists {
+		panic(errors.New("CHESS_DOT_COM_URL is missing"))
+	}
+
+	lichessUrl, lichessUrlExists := os.LookupEnv("LICHESS_URL")
+	if !lichessUrlExists {
+		panic(errors.New("LICHESS_URL is missing"))
+	}
+
+	downloadsTableName, downloadsTableNameExists := os.LookupEnv("DOWNLOADS_TABLE_NAME")
+	if !downloadsTableNameExists {
+		panic(errors.New("DOWNLOADS_TABLE_NAME is missing"))
+	}
+
+	archivesTableName, archivesTableNameExists := os.LookupEnv("ARCHIVES_TABLE_NAME")
+	if !archivesTableNameExists {
+		panic(errors.New("ARCHIVES_TABLE_NAME is missing"))
+	}
+
+	gamesTableName, gamesTableNameExists := os.LookupEnv("GAMES_TABLE_NAME")
+	if !gamesTableNameExists {
+		panic(errors.New("GAMES_TABLE_NAME is missing"))
+	}
+
+	gamesByEndTimestampIndexName, gamesByEndTimestampIndexNameExists := os.LookupEnv("GAMES_BY_END_TIMESTAMP_INDEX_NAME")
+	if !gamesByEndTimestampIndexNameExists {
+		panic(errors.New("GAMES_BY_END_TIMESTAMP_INDEX_NAME is missing"))
+	}
+
+	downloadInfoExpiresInCadidate, downloadInfoExpiresInExists := os.LookupEnv("DOWNLOAD_INFO_EXPIRES_IN_SECONDS")
+	if !downloadInfoExpiresInExists {
+		panic(errors.New("DOWNLOAD_INFO_EXPIRES_IN_SECONDS is missing"))
+	}
+
+	downloadInfoExpiresIn, err := time.ParseDuration(downloadInfoExpiresInCadidate + "s")
+	if err != nil {
+		panic(err)
+	}
+
+	theStackName, theStackNameExists := os.LookupEnv("THE_STACK_NAME")
+	if !theStackNameExists {
+		panic(errors.New("THE_STACK_NAME is missing"))
+	}
+
+	// the games are stored as squeezed PGN unless their moves are to be encoded
+	encodeMoves := os.Getenv("ENCODE_MOVES") == "true"
+
+	concurrency := 1
+	if concurrencyCandidate := os.Getenv("PROCESSING_CONCURRENCY"); concurrencyCandidate != "" {
+		concurrency, err = strconv.Atoi(concurrencyCandidate)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	maxReceiveCountCandidate, maxReceiveCountExists := os.LookupEnv("MAX_RECEIVE_COUNT")
+	if !maxReceiveCountExists {
+		panic(errors.New("MAX_RECEIVE_COUNT is missing"))
+	}
+
+	maxReceiveCount, err := strconv.Atoi(maxReceiveCountCandidate)
+	if err != nil {
+		panic(err)
+	}
+	if maxReceiveCount <= 0 {
+		panic(errors.New("MAX_RECEIVE_COUNT must be positive"))
+	}
+
+	awsRegion, awsRegionExists := os.LookupEnv("AWS_REGION")
+	if !awsRegionExists {
+		panic(errors.New("AWS_REGION is missing"))
+	}
+
+	awsConfig := &aws.Config{
+		Region: &awsRegion,
+	}
+	awsSession := session.Must(session.NewSession(awsConfig))
+	cloudWatchClient := cloudwatch.New(awsSession)
+	dynamodbClient := dynamodb.New(awsSession)
+
+	// without the bucket the oversized pgns are written inline and fail as before
+	var pgnBlobs blobs.BlobStore
+	if pgnBucketName := os.Getenv("PGN_BUCKET_NAME"); pgnBucketName != "" {
+		pgnBlobs = blobs.S3BlobStore{Bucket: pgnBucketName, S3Client: s3.New(awsSession)}
+	}
+
+	// without the queue the messages failed permanently are only logged
+	var deadLetters queue.Publisher
+	if deadLetterQueueUrl := os.Getenv("DEAD_LETTER_QUEUE_URL"); deadLetterQueueUrl != "" {
+		deadLetters = queue.SqsQueue{QueueUrl: deadLetterQueueUrl, SqsClient: sqs.New(awsSession)}
+	}
+
+	downloader := process.GameDownloader{
+		GameSources: sources.Platforms(chessDotComUrl, lichessUrl, theStackName, cloudWatchClient),
+		DownloadsTable: downloads.DownloadsTable{
+			Name:           downloadsTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		ArchivesTable: archives.ArchivesTable{
+			Name:           archivesTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		GamesTable: games.GamesTable{
+			Name:           gamesTableName,
+			DynamodbClient: dynamodbClient,
+			PgnBlobs:       pgnBlobs,
+		},
+		LatestGameIndex: games.LatestGameIndex{
+			Name:           gamesByEndTimestampIndexName,
+			TableName:      gamesTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		MetricsNamespace:      theStackName,
+		PgnFilter:             process.PgnSqueezer{},
+		EncodeMoves:           encodeMoves,
+		DownloadInfoExpiresIn: downloadInfoExpiresIn,
+		CloudWatchClient:      cloudWatchClient,
+		Concurrency:           concurrency,
+		DeadLetters:           deadLetters,
+		MaxReceiveCount:       maxReceiveCount,
+	}
+
+	tracing.MustSetUp("chessfinder-download-process")
+
+	lambda.Start(downloader.Download)
+
+}
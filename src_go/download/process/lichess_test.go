@@ -0,0 +1,173 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// lichessStandIn serves the NDJSON export of August 2022 for the given user and counts the requests it got.
+func lichessStandIn(t *testing.T, username string) (server *httptest.Server, requests *int) {
+	requests = new(int)
+	server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		*requests++
+		assert.Equal(t, "/api/games/user/"+username, request.URL.Path)
+		assert.Equal(t, "1659312000000", request.URL.Query().Get("since"))
+		assert.Equal(t, "1661990399999", request.URL.Query().Get("until"))
+		assert.Equal(t, "true", request.URL.Query().Get("pgnInJson"))
+		assert.Equal(t, "application/x-ndjson", request.Header.Get("Accept"))
+
+		body, err := os.ReadFile("testdata/lichess_2022-08.ndjson")
+		assert.NoError(t, err)
+		writer.Header().Set("Content-Type", "application/x-ndjson")
+		writer.WriteHeader(http.StatusOK)
+		writer.Write(body)
+	}))
+	return
+}
+
+var lichessGame1 = games.GameRecord{
+	GameId:       "https://lichess.org/q7ZvsdUF",
+	Resource:     "https://lichess.org/q7ZvsdUF",
+	Pgn:          "1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6?? 4. Qxf7# 1-0",
+	EndTimestamp: 1659429921,
+	White:        "Tigran-C-137",
+	WhiteRating:  1502,
+	WhiteResult:  games.Win,
+	Black:        "Opponent_One",
+	BlackRating:  1488,
+	BlackResult:  games.Loss,
+	Result:       "1-0",
+	TimeClass:    "blitz",
+	TimeControl:  "180+2",
+	Rated:        true,
+	Eco:          "C20",
+	Rules:        "chess",
+	UserColor:    games.White,
+	UserResult:   games.Win,
+	Opponent:     "opponent_one",
+}
+
+var lichessGame2 = games.GameRecord{
+	GameId:       "https://lichess.org/Xb3kLq9P",
+	Resource:     "https://lichess.org/Xb3kLq9P",
+	Pgn:          "1. d4 d5 2. Nf3 Nf6 1/2-1/2",
+	EndTimestamp: 1659430643,
+	White:        "Opponent_Two",
+	WhiteRating:  1611,
+	WhiteResult:  games.Draw,
+	Black:        "Tigran-C-137",
+	BlackRating:  1507,
+	BlackResult:  games.Draw,
+	Result:       "1/2-1/2",
+	TimeClass:    "rapid",
+	TimeControl:  "600",
+	Eco:          "D02",
+	Rules:        "chess",
+	UserColor:    games.Black,
+	UserResult:   games.Draw,
+	Opponent:     "opponent_two",
+}
+
+func Test_when_platform_is_lichess_GameDownloader_should_stream_the_games_of_the_month_from_the_export(t *testing.T) {
+	server, requests := lichessStandIn(t, "tigran-c-137")
+	defer server.Close()
+
+	lichess := downloader.GameSources[users.Lichess]
+	defer func() { downloader.GameSources[users.Lichess] = lichess }()
+	lichessClient := sources.NewLichessClient(metrics.LichessMeter{CloudWatchClient: cloudwatch.New(awsSession)})
+	lichessClient.HttpClient = server.Client()
+	downloader.GameSources[users.Lichess] = sources.Lichess{Url: server.URL, Client: lichessClient}
+	downloader.PgnFilter = PgnSqueezer{}
+
+	startOfTest := time.Now().UTC()
+
+	var err error
+	userId := "https://lichess.org/@/tigran-c-137#" + uuid.New().String()
+	archiveId := fmt.Sprintf("%s/api/games/user/tigran-c-137/2022/08#%s", server.URL, uuid.New().String())
+
+	archiveRecord := archives.ArchiveRecord{
+		UserId:       userId,
+		ArchiveId:    archiveId,
+		Resource:     archiveId,
+		Year:         2022,
+		Month:        8,
+		DownloadedAt: nil,
+		Downloaded:   0,
+	}
+
+	err = archivesTable.PutArchiveRecord(context.Background(), archiveRecord)
+	assert.NoError(t, err)
+
+	downloadId := downloads.NewDownloadId(userId)
+	expiresAt := startOfTest.Add(14 * time.Hour)
+	downloadRecord := downloads.DownloadRecord{
+		DownloadId:       downloadId,
+		StartAt:          db.Zuludatetime(startOfTest.Add(-1 * time.Hour)),
+		LastDownloadedAt: db.Zuludatetime(startOfTest.Add(-1 * time.Hour)),
+		Succeed:          0,
+		Failed:           0,
+		Done:             0,
+		Pending:          1,
+		Total:            1,
+		ExpiresAt:        dynamodbattribute.UnixTime(expiresAt),
+	}
+
+	err = downloadsTable.PutDownloadRecord(context.Background(), downloadRecord)
+	assert.NoError(t, err)
+
+	command := events.SQSMessage{
+		Body: fmt.Sprintf(
+			`{"username": "tigran-c-137", "userId": "%s", "platform": "LICHESS", "archiveId": "%s", "downloadId": "%s"}`,
+			userId,
+			archiveId,
+			downloadId,
+		),
+		MessageId: "1",
+	}
+
+	actualCommandsProcessed, err := downloader.Download(events.SQSEvent{Records: []events.SQSMessage{command}})
+	assert.NoError(t, err)
+	assert.Equal(t, events.SQSEventResponse{BatchItemFailures: nil}, actualCommandsProcessed)
+	assert.Equal(t, 1, *requests)
+
+	actualArchive, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId)
+	assert.NoError(t, err)
+	assert.NotNil(t, actualArchive)
+	assert.Equal(t, 2, actualArchive.Downloaded)
+
+	var noKey games.PageKey
+	actualGames, _, err := gamesTable.QueryGames(context.Background(), userId, games.GameFilter{}, noKey, 1000)
+	assert.NoError(t, err)
+
+	expectedGame1 := lichessGame1
+	expectedGame1.UserId = userId
+	expectedGame1.ArchiveId = archiveId
+	expectedGame2 := lichessGame2
+	expectedGame2.UserId = userId
+	expectedGame2.ArchiveId = archiveId
+	assert.ElementsMatch(t, []games.GameRecord{expectedGame1, expectedGame2}, actualGames)
+
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId.String())
+	assert.NoError(t, err)
+	assert.NotNil(t, actualDownload)
+	assert.Equal(t, 1, actualDownload.Succeed)
+	assert.Equal(t, 1, actualDownload.Done)
+}
@@ -1,8 +1,7 @@
-package main
+package process
 
 import (
-	"regexp"
-	"strings"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/pgn"
 )
 
 type PgnFilter interface {
@@ -16,38 +15,15 @@ func (filter IdentityPgnFilter) Filter(pgn string) (filteredPgn string, err erro
 	return
 }
 
-const commentsPattern = `(\{[^}]*\})|\([^)]*\)`
-
-var compiledCommentsPattern = regexp.MustCompile(commentsPattern)
-
-const balkcsMovePattern = `\d+\.\.\.\s+`
-
-var compiledBalkcsMovePattern = regexp.MustCompile(balkcsMovePattern)
-
+// PgnSqueezer keeps only the mainline of the game: the numbered SAN moves and the result.
 type PgnSqueezer struct {
 }
 
-func (squeezer PgnSqueezer) Filter(pgn string) (squeezedPgn string, err error) {
-	squeezedPgn = stripTagPairs(pgn)
-	squeezedPgn = compiledCommentsPattern.ReplaceAllString(squeezedPgn, "")
-	squeezedPgn = compiledBalkcsMovePattern.ReplaceAllString(squeezedPgn, "")
-	squeezedPgn = strings.ReplaceAll(squeezedPgn, "\n", " ")
-	squeezedPgn = strings.ReplaceAll(squeezedPgn, "\r", " ")
-
-	fields := strings.Fields(squeezedPgn)
-	squeezedPgn = strings.Join(fields, " ")
-	squeezedPgn = strings.TrimSpace(squeezedPgn)
-	return
-}
-
-func stripTagPairs(pgn string) string {
-	lines := strings.Split(pgn, "\n")
-	cp := []string{}
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "[") {
-			cp = append(cp, line)
-		}
+func (squeezer PgnSqueezer) Filter(pgnString string) (squeezedPgn string, err error) {
+	game, err := pgn.ParseGame(pgnString)
+	if err != nil {
+		return
 	}
-	return strings.Join(cp, "\n")
+	squeezedPgn = game.Mainline()
+	return
 }
@@ -0,0 +1,125 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_when_archive_is_uploaded_GameDownloader_should_persist_the_squeezed_games_of_the_command(t *testing.T) {
+	downloader.PgnFilter = PgnSqueezer{}
+
+	startOfTest := time.Now().UTC()
+
+	var err error
+	username := "tigran-c-137"
+	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v#%v", username, uuid.New().String())
+	archiveId := userId + "/uploads/5e884898da280471"
+
+	uploadedAt := db.Zuludatetime(startOfTest.AddDate(0, 0, -40))
+	archiveRecord := archives.ArchiveRecord{
+		UserId:       userId,
+		ArchiveId:    archiveId,
+		Resource:     archiveId,
+		Year:         2022,
+		Month:        8,
+		DownloadedAt: &uploadedAt,
+		Downloaded:   10,
+		Uploaded:     true,
+	}
+
+	err = archivesTable.PutArchiveRecord(context.Background(), archiveRecord)
+	assert.NoError(t, err)
+
+	downloadId := downloads.NewDownloadId(userId)
+	downloadRecord := downloads.NewDownloadRecord(downloadId, 2, startOfTest.Add(-1*time.Minute), 24*time.Hour)
+	downloadRecord.Succeed = 1
+	downloadRecord.Done = 1
+	downloadRecord.Pending = 1
+
+	err = downloadsTable.PutDownloadRecord(context.Background(), downloadRecord)
+	assert.NoError(t, err)
+
+	command := queue.DownloadGamesCommand{
+		Username:   username,
+		UserId:     userId,
+		Platform:   queue.ChessDotCom,
+		ArchiveId:  archiveId,
+		DownloadId: downloadId.String(),
+		Pgn: "[Event \"Club Championship\"]\n[Date \"2023.05.14\"]\n[White \"Tigran-C-137\"]\n[Black \"Aronian, Levon\"]\n[Result \"0-1\"]\n\n1. e4 d5 2. exd5 {a comment} Qxd5 0-1\n\n" +
+			"[Event \"Club Championship\"]\n[Date \"2023.05.15\"]\n[White \"Smbatyan, Ani\"]\n[Black \"tigran-c-137\"]\n[Result \"1/2-1/2\"]\n\n1. d4 Nf6 1/2-1/2",
+		FirstGame: 10,
+	}
+	commandBody, err := json.Marshal(command)
+	assert.NoError(t, err)
+
+	actualCommandsProcessed, err := downloader.Download(events.SQSEvent{Records: []events.SQSMessage{{Body: string(commandBody), MessageId: "1"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, events.SQSEventResponse{BatchItemFailures: nil}, actualCommandsProcessed)
+
+	actualArchive, err := archivesTable.GetArchiveRecord(context.Background(), userId, archiveId)
+	assert.NoError(t, err)
+	assert.NotNil(t, actualArchive)
+	assert.Equal(t, 12, actualArchive.Downloaded)
+	assert.True(t, actualArchive.Uploaded)
+
+	var noKey games.PageKey
+	actualGames, _, err := gamesTable.QueryGames(context.Background(), userId, games.GameFilter{}, noKey, 1000)
+	assert.NoError(t, err)
+
+	expectedGames := []games.GameRecord{
+		{
+			UserId:       userId,
+			ArchiveId:    archiveId,
+			GameId:       archiveId + "/11",
+			Resource:     archiveId + "/11",
+			Pgn:          "1. e4 d5 2. exd5 Qxd5 0-1",
+			EndTimestamp: 1684022400,
+			White:        "Tigran-C-137",
+			WhiteResult:  games.Loss,
+			Black:        "Aronian, Levon",
+			BlackResult:  games.Win,
+			Result:       "0-1",
+			Rules:        "chess",
+			UserColor:    games.White,
+			UserResult:   games.Loss,
+			Opponent:     "aronian, levon",
+		},
+		{
+			UserId:       userId,
+			ArchiveId:    archiveId,
+			GameId:       archiveId + "/12",
+			Resource:     archiveId + "/12",
+			Pgn:          "1. d4 Nf6 1/2-1/2",
+			EndTimestamp: 1684108800,
+			White:        "Smbatyan, Ani",
+			WhiteResult:  games.Draw,
+			Black:        "tigran-c-137",
+			BlackResult:  games.Draw,
+			Result:       "1/2-1/2",
+			Rules:        "chess",
+			UserColor:    games.Black,
+			UserResult:   games.Draw,
+			Opponent:     "smbatyan, ani",
+		},
+	}
+	assert.ElementsMatch(t, expectedGames, actualGames)
+
+	actualDownload, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId.String())
+	assert.NoError(t, err)
+	assert.NotNil(t, actualDownload)
+	assert.Equal(t, 2, actualDownload.Succeed)
+	assert.Equal(t, 2, actualDownload.Done)
+	assert.Equal(t, 0, actualDownload.Pending)
+}
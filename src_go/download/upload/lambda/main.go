@@ -7,7 +7,16 @@ import (
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/download/upload"
 )
 
 func main() {
@@ -27,11 +36,6 @@ func main() {
 		panic(errors.New("USERS_TABLE_NAME is missing"))
 	}
 
-	chessDotComUrl, chessDotComUrlExists := os.LookupEnv("CHESS_DOT_COM_URL")
-	if !chessDotComUrlExists {
-		panic(errors.New("CHESS_DOT_COM_URL is missing"))
-	}
-
 	downloadGamesQueueUrl, downloadGamesQueueUrlExists := os.LookupEnv("DOWNLOAD_GAMES_QUEUE_URL")
 	if !downloadGamesQueueUrlExists {
 		panic(errors.New("DOWNLOAD_GAMES_QUEUE_URL is missing"))
@@ -47,28 +51,38 @@ func main() {
 		panic(err)
 	}
 
-	theStackName, theStackNameExists := os.LookupEnv("THE_STACK_NAME")
-	if !theStackNameExists {
-		panic(errors.New("THE_STACK_NAME is missing"))
-	}
-
 	awsRegion, awsRegionExists := os.LookupEnv("AWS_REGION")
 	if !awsRegionExists {
 		panic(errors.New("AWS_REGION is missing"))
 	}
 
-	checker := ArchiveDownloader{
-		downloadsTableName:    downloadsTableName,
-		usersTableName:        usersTableName,
-		archivesTableName:     archivesTableName,
-		downloadGamesQueueUrl: downloadGamesQueueUrl,
-		chessDotComUrl:        chessDotComUrl,
-		metricsNamespace:      theStackName,
-		downloadInfoExpiresIn: downloadInfoExpiresIn,
-		awsConfig: &aws.Config{
-			Region: &awsRegion,
+	awsConfig := &aws.Config{
+		Region: &awsRegion,
+	}
+	awsSession := session.Must(session.NewSession(awsConfig))
+	dynamodbClient := dynamodb.New(awsSession)
+
+	uploader := upload.PgnUploader{
+		DownloadsTable: downloads.DownloadsTable{
+			Name:           downloadsTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		UsersTable: users.UsersTable{
+			Name:           usersTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		ArchivesTable: archives.ArchivesTable{
+			Name:           archivesTableName,
+			DynamodbClient: dynamodbClient,
 		},
+		DownloadGamesQueue: queue.SqsQueue{
+			QueueUrl:  downloadGamesQueueUrl,
+			SqsClient: sqs.New(awsSession),
+		},
+		DownloadInfoExpiresIn: downloadInfoExpiresIn,
 	}
 
-	lambda.Start(api.WithRecover(checker.DownloadArchiveAndDistributeDownloadGameCommands))
+	tracing.MustSetUp("chessfinder-download-upload")
+
+	lambda.Start(api.WithRecover(uploader.UploadPgn))
 }
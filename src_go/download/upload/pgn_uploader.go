@@ -0,0 +1,345 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/sources"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
+	"go.uber.org/zap"
+)
+
+// maxChunkSize keeps a chunk of games with its escaping well below the 256 KB limit of an SQS message.
+// A game larger than that cannot be sent through the download queue at all.
+const maxChunkSize = 100 * 1024
+
+// maxFormMemory is the part of an uploaded file kept in memory, the rest is written to temporary files.
+const maxFormMemory = 10 << 20
+
+type PgnUploader struct {
+	UsersTable            users.UsersRepository
+	ArchivesTable         archives.ArchivesRepository
+	DownloadsTable        downloads.DownloadsRepository
+	DownloadGamesQueue    queue.Publisher
+	DownloadInfoExpiresIn time.Duration
+}
+
+// UploadPgn stores the games of a PGN file in an uploaded archive of a cached user.
+// The file is the pgn part of a multipart/form-data body with the username and the platform as form fields,
+// or the pgn string of a JSON body.
+// The games are sent in chunks through the download queue and the progress is reported by the download record of the user,
+// so the uploaded games are found by the searches started after the upload.
+// The chunks that cannot be published are counted as failed, and an upload whose games are not all stored can be sent again.
+func (uploader *PgnUploader) UploadPgn(
+	event *events.APIGatewayV2HTTPRequest,
+) (responseEvent events.APIGatewayV2HTTPResponse, err error) {
+	ctx, span := tracing.StartRequest(event)
+	defer func() { tracing.End(span, err) }()
+
+	logger := tracing.Logger(ctx, logging.MustCreateZuluTimeLogger())
+	logger = logger.With(zap.String("requestId", event.RequestContext.RequestID))
+	defer logger.Sync()
+
+	method := event.RequestContext.HTTP.Method
+	path := event.RequestContext.HTTP.Path
+
+	if path != "/api/faster/upload" || method != "POST" {
+		logger.Panic("pgn uploader is attached to a wrong route!")
+	}
+
+	uploadRequest, err := readUploadRequest(event)
+	if err != nil {
+		logger.Error("impossible to read the request body!", zap.Error(err))
+		err = api.InvalidBody
+		return
+	}
+
+	if uploadRequest.Username == "" {
+		logger.Info("username cannot be empty")
+		err = UserNameCannotBeEmpty
+		return
+	}
+
+	uploadRequest.Username = strings.ToLower(uploadRequest.Username)
+	platform := users.Platform(strings.ToUpper(uploadRequest.Platform))
+	if platform == "" {
+		platform = users.ChessDotCom
+	}
+
+	logger = logger.With(zap.String("username", uploadRequest.Username), zap.String("platform", string(platform)))
+
+	pgnGames := sources.SplitGames(uploadRequest.Pgn)
+	if len(pgnGames) == 0 {
+		logger.Info("pgn does not have any game")
+		err = PgnHasNoGames
+		return
+	}
+
+	logger = logger.With(zap.Int("uploadedGames", len(pgnGames)))
+
+	for i, pgnGame := range pgnGames {
+		if len(pgnGame) > maxChunkSize {
+			logger.Info("game is too large for the download queue", zap.Int("gameNumber", i+1), zap.Int("gameSize", len(pgnGame)))
+			err = PgnGameIsTooLarge(i + 1)
+			return
+		}
+	}
+
+	usersCtx, usersSpan := tracing.StartCall(ctx, "UsersTable.GetUserRecord")
+	user, err := uploader.UsersTable.GetUserRecord(usersCtx, uploadRequest.Username, platform)
+	tracing.End(usersSpan, err)
+
+	if err != nil {
+		logger.Error("impossible to get the user from the database", zap.Error(err))
+		return
+	}
+
+	if user == nil {
+		logger.Info("profile is not cached")
+		err = ProfileIsNotCached(uploadRequest.Username, string(platform))
+		return
+	}
+
+	logger = logger.With(zap.String("userId", user.UserId))
+
+	downloadId := downloads.NewDownloadId(user.UserId)
+	downloadsCtx, downloadsSpan := tracing.StartCall(ctx, "DownloadsTable.GetDownloadRecord")
+	existingDownloadRecord, err := uploader.DownloadsTable.GetDownloadRecord(downloadsCtx, downloadId.String())
+	tracing.End(downloadsSpan, err)
+	if err != nil {
+		logger.Error("impossible to get the latest download record!", zap.Error(err))
+		return
+	}
+
+	if existingDownloadRecord != nil && existingDownloadRecord.Done < existingDownloadRecord.Total {
+		logger.Info("download is in progress", zap.String("downloadId", downloadId.String()))
+		err = DownloadIsInProgress(downloadId.String())
+		return
+	}
+
+	hash := sha256.Sum256([]byte(uploadRequest.Pgn))
+	archiveId := user.UserId + "/uploads/" + hex.EncodeToString(hash[:8])
+	logger = logger.With(zap.String("archiveId", archiveId))
+
+	archivesCtx, archivesSpan := tracing.StartCall(ctx, "ArchivesTable.GetArchiveRecord")
+	existingArchive, err := uploader.ArchivesTable.GetArchiveRecord(archivesCtx, user.UserId, archiveId)
+	tracing.End(archivesSpan, err)
+	if err != nil {
+		logger.Error("impossible to get the archive record", zap.Error(err))
+		return
+	}
+
+	if existingArchive != nil && existingArchive.Downloaded >= len(pgnGames) {
+		logger.Info("pgn is already uploaded")
+		err = PgnIsAlreadyUploaded
+		return
+	}
+
+	if existingArchive != nil {
+		logger.Info("pgn is uploaded again as not all of its games are stored", zap.Int("downloaded", existingArchive.Downloaded))
+	}
+
+	now := time.Now()
+	archiveRecord := archives.ArchiveRecord{
+		UserId:       user.UserId,
+		ArchiveId:    archiveId,
+		Resource:     archiveId,
+		Year:         now.UTC().Year(),
+		Month:        int(now.UTC().Month()),
+		Downloaded:   0,
+		DownloadedAt: nil,
+		Uploaded:     true,
+	}
+
+	putArchiveCtx, putArchiveSpan := tracing.StartCall(ctx, "ArchivesTable.PutArchiveRecord")
+	err = uploader.ArchivesTable.PutArchiveRecord(putArchiveCtx, archiveRecord)
+	tracing.End(putArchiveSpan, err)
+	if err != nil {
+		logger.Error("impossible to persist the archive record", zap.Error(err))
+		return
+	}
+
+	chunks := chunkGames(pgnGames, maxChunkSize)
+	downloadRecord := downloads.NewDownloadRecord(downloadId, len(chunks), now, uploader.DownloadInfoExpiresIn)
+
+	putDownloadCtx, putDownloadSpan := tracing.StartCall(ctx, "DownloadsTable.PutDownloadRecord")
+	err = uploader.DownloadsTable.PutDownloadRecord(putDownloadCtx, downloadRecord)
+	tracing.End(putDownloadSpan, err)
+	if err != nil {
+		logger.Error("impossible to persist the download record!", zap.Error(err))
+		return
+	}
+
+	published, err := uploader.publishUploadedGames(ctx, logger, event.RequestContext.RequestID, *user, archiveRecord, downloadRecord, chunks)
+	if err != nil {
+		uploader.failUnpublishedChunks(ctx, logger, downloadId, len(chunks)-published, now)
+		return
+	}
+
+	jsonBody, err := json.Marshal(UploadResponse{DownloadId: downloadId.String()})
+	if err != nil {
+		logger.Error("impossible to create the response event!", zap.Error(err))
+		return
+	}
+
+	responseEvent = events.APIGatewayV2HTTPResponse{
+		StatusCode: 200,
+		Body:       string(jsonBody),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}
+
+	return
+}
+
+func (uploader PgnUploader) publishUploadedGames(
+	ctx context.Context,
+	logger *zap.Logger,
+	requestId string,
+	user users.UserRecord,
+	archiveRecord archives.ArchiveRecord,
+	downloadRecord downloads.DownloadRecord,
+	chunks [][]string,
+) (published int, err error) {
+	logger = logger.With(zap.Int("chunks", len(chunks)))
+	logger.Info("publishing the chunks of uploaded games ...")
+
+	firstGame := 0
+	for _, chunk := range chunks {
+		logger := logger.With(zap.Int("firstGame", firstGame))
+		command := queue.DownloadGamesCommand{
+			Username:   user.Username,
+			Platform:   queue.Platform(user.Platform),
+			ArchiveId:  archiveRecord.ArchiveId,
+			UserId:     user.UserId,
+			DownloadId: downloadRecord.DownloadId.String(),
+			Pgn:        strings.Join(chunk, "\n\n"),
+			FirstGame:  firstGame,
+		}
+		var body string
+		body, err = queue.DownloadGamesCodec.Encode(requestId, command)
+		if err != nil {
+			logger.Error("impossible to marshal the download game command!", zap.Error(err))
+			return
+		}
+
+		err = uploader.DownloadGamesQueue.Publish(queue.Message{
+			Body:            body,
+			DeduplicationId: archiveRecord.ArchiveId + "#" + strconv.Itoa(firstGame),
+			GroupId:         user.UserId,
+			Attributes:      tracing.Inject(ctx),
+		})
+		if err != nil {
+			logger.Error("impossible to publish the download game command!", zap.Error(err))
+			return
+		}
+		firstGame += len(chunk)
+		published++
+	}
+
+	logger.Info("chunks of uploaded games published")
+	return
+}
+
+// failUnpublishedChunks counts the chunks that are never going to be downloaded as failed,
+// so that the download is done once the published ones are and the user can upload the pgn again.
+func (uploader PgnUploader) failUnpublishedChunks(ctx context.Context, logger *zap.Logger, downloadId downloads.DownloadId, unpublished int, now time.Time) {
+	logger = logger.With(zap.Int("unpublishedChunks", unpublished))
+	for i := 0; i < unpublished; i++ {
+		incrementCtx, incrementSpan := tracing.StartCall(ctx, "DownloadsTable.IncrementFailure")
+		err := uploader.DownloadsTable.IncrementFailure(incrementCtx, downloadId.String(), db.Zuludatetime(now), uploader.DownloadInfoExpiresIn)
+		tracing.End(incrementSpan, err)
+		if err != nil {
+			logger.Error("impossible to count the unpublished chunks as failed!", zap.Error(err))
+			return
+		}
+	}
+	logger.Info("unpublished chunks counted as failed")
+}
+
+// readUploadRequest reads the body of a multipart/form-data upload, or the JSON body if the upload is not a form.
+func readUploadRequest(event *events.APIGatewayV2HTTPRequest) (uploadRequest UploadRequest, err error) {
+	body := []byte(event.Body)
+	if event.IsBase64Encoded {
+		body, err = base64.StdEncoding.DecodeString(event.Body)
+		if err != nil {
+			return
+		}
+	}
+
+	mediaType, params, errOfMediaType := mime.ParseMediaType(event.Headers["content-type"])
+	if errOfMediaType != nil || mediaType != "multipart/form-data" {
+		err = json.Unmarshal(body, &uploadRequest)
+		return
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(maxFormMemory)
+	if err != nil {
+		return
+	}
+	defer form.RemoveAll()
+
+	if values := form.Value["username"]; len(values) > 0 {
+		uploadRequest.Username = values[0]
+	}
+	if values := form.Value["platform"]; len(values) > 0 {
+		uploadRequest.Platform = values[0]
+	}
+
+	files := form.File["pgn"]
+	if len(files) == 0 {
+		err = errors.New("the pgn file is missing")
+		return
+	}
+	file, err := files[0].Open()
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	pgn, err := io.ReadAll(file)
+	if err != nil {
+		return
+	}
+	uploadRequest.Pgn = string(pgn)
+	return
+}
+
+// chunkGames groups the games in their order so that a chunk is not bigger than maxChunkSize, unless it is a single game.
+func chunkGames(pgnGames []string, maxChunkSize int) (chunks [][]string) {
+	chunk := []string{}
+	chunkSize := 0
+	for _, pgnGame := range pgnGames {
+		if len(chunk) > 0 && chunkSize+len(pgnGame) > maxChunkSize {
+			chunks = append(chunks, chunk)
+			chunk = []string{}
+			chunkSize = 0
+		}
+		chunk = append(chunk, pgnGame)
+		chunkSize += len(pgnGame)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return
+}
@@ -0,0 +1,318 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var awsConfig = aws.Config{
+	Region:     aws.String("us-east-1"),
+	Endpoint:   aws.String("http://localhost:4566"), // this is the LocalStack endpoint for all services
+	DisableSSL: aws.Bool(true),
+}
+
+var uploader = PgnUploader{
+	DownloadGamesQueue:    downloadGamesQueue,
+	UsersTable:            usersTable,
+	ArchivesTable:         archivesTable,
+	DownloadsTable:        downloadsTable,
+	DownloadInfoExpiresIn: 24 * time.Hour,
+}
+
+var awsSession = session.Must(session.NewSession(&awsConfig))
+
+var dynamodbClient = dynamodb.New(awsSession)
+var svc = sqs.New(awsSession)
+
+var downloadGamesQueue = queue.SqsQueue{
+	QueueUrl:  "http://localhost:4566/000000000000/chessfinder_sqs-DownloadGames.fifo",
+	SqsClient: svc,
+}
+
+var usersTable = users.UsersTable{
+	Name:           "chessfinder_dynamodb-users",
+	DynamodbClient: dynamodbClient,
+}
+
+var downloadsTable = downloads.DownloadsTable{
+	Name:           "chessfinder_dynamodb-downloads",
+	DynamodbClient: dynamodbClient,
+}
+
+var archivesTable = archives.ArchivesTable{
+	Name:           "chessfinder_dynamodb-archives",
+	DynamodbClient: dynamodbClient,
+}
+
+const tournamentPgn = "[Event \"Club Championship\"]\n[Date \"2023.05.14\"]\n[White \"%[1]v\"]\n[Black \"Aronian, Levon\"]\n[Result \"0-1\"]\n\n1. e4 d5 2. exd5 Qxd5 0-1\n\n" +
+	"[Event \"Club Championship\"]\n[Date \"2023.05.15\"]\n[White \"Smbatyan, Ani\"]\n[Black \"%[1]v\"]\n[Result \"1/2-1/2\"]\n\n1. d4 Nf6 1/2-1/2\n"
+
+func uploadEvent(body string) events.APIGatewayV2HTTPRequest {
+	return events.APIGatewayV2HTTPRequest{
+		Body: body,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/upload",
+			},
+		},
+	}
+}
+
+func Test_PgnUploader_should_publish_the_games_of_the_pgn_for_a_cached_user(t *testing.T) {
+	var err error
+	startOfTest := time.Now()
+
+	username := uuid.New().String()
+	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
+
+	err = usersTable.PutUserRecord(context.Background(), users.UserRecord{
+		Username: username,
+		UserId:   userId,
+		Platform: users.ChessDotCom,
+	})
+	assert.NoError(t, err)
+
+	pgn := fmt.Sprintf(tournamentPgn, username)
+	requestBody, err := json.Marshal(UploadRequest{Username: username, Platform: "CHESS_DOT_COM", Pgn: pgn})
+	assert.NoError(t, err)
+	event := uploadEvent(string(requestBody))
+
+	actualResponse, err := uploader.UploadPgn(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, actualResponse.StatusCode, "Response status code is not 200!")
+
+	actualUploadResponse := UploadResponse{}
+	err = json.Unmarshal([]byte(actualResponse.Body), &actualUploadResponse)
+	assert.NoError(t, err)
+
+	downloadId := actualUploadResponse.DownloadId
+	assert.Equal(t, downloads.NewDownloadId(userId).String(), downloadId)
+
+	actualDownloadRecord, err := downloadsTable.GetDownloadRecord(context.Background(), downloadId)
+	assert.NoError(t, err)
+	assert.NotNil(t, actualDownloadRecord)
+	assert.Equal(t, 0, actualDownloadRecord.Done)
+	assert.Equal(t, 1, actualDownloadRecord.Pending)
+	assert.Equal(t, 1, actualDownloadRecord.Total)
+	assert.True(t, startOfTest.Before(actualDownloadRecord.StartAt.ToTime()))
+
+	actualArchives, err := archivesTable.GetArchiveRecords(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.Len(t, actualArchives, 1)
+
+	archiveId := actualArchives[0].ArchiveId
+	assert.True(t, strings.HasPrefix(archiveId, userId+"/uploads/"))
+
+	expectedArchive := archives.ArchiveRecord{
+		UserId:       userId,
+		ArchiveId:    archiveId,
+		Resource:     archiveId,
+		Year:         startOfTest.UTC().Year(),
+		Month:        int(startOfTest.UTC().Month()),
+		Downloaded:   0,
+		DownloadedAt: nil,
+		Uploaded:     true,
+	}
+	assert.Equal(t, expectedArchive, actualArchives[0])
+
+	lastCommands, err := queue.GetLastNCommands(svc, downloadGamesQueue.QueueUrl, 1)
+	assert.NoError(t, err)
+	assert.Len(t, lastCommands, 1)
+
+	actualCommand := queue.DownloadGamesCommand{}
+	_, err = queue.DownloadGamesCodec.Decode(*lastCommands[0].Body, &actualCommand)
+	assert.NoError(t, err)
+
+	expectedCommand := queue.DownloadGamesCommand{
+		Username:   username,
+		Platform:   queue.ChessDotCom,
+		UserId:     userId,
+		ArchiveId:  archiveId,
+		DownloadId: downloadId,
+		Pgn:        strings.TrimSpace(pgn),
+		FirstGame:  0,
+	}
+	assert.Equal(t, expectedCommand, actualCommand)
+
+	actualResponse, err = api.WithRecover(uploader.UploadPgn)(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, actualResponse.StatusCode, "Response status code is not 422!")
+	assert.JSONEq(t, fmt.Sprintf(`{"code": "DOWNLOAD_IS_IN_PROGRESS", "message": "Download %v is still in progress!"}`, downloadId), actualResponse.Body)
+}
+
+func Test_PgnUploader_should_return_error_if_the_profile_is_not_cached(t *testing.T) {
+	username := uuid.New().String()
+	event := uploadEvent(fmt.Sprintf(`{"username": "%v", "platform": "LICHESS", "pgn": "1. e4 e5 *"}`, username))
+
+	actualResponse, err := api.WithRecover(uploader.UploadPgn)(&event)
+	assert.NoError(t, err)
+	expectedResponseBody := fmt.Sprintf(`{"code": "PROFILE_IS_NOT_CACHED", "message": "Profile %v from LICHESS is not cached!"}`, username)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, actualResponse.StatusCode, "Response status code is not 422!")
+	assert.JSONEq(t, expectedResponseBody, actualResponse.Body, "Response body is not as expected!")
+}
+
+func Test_PgnUploader_should_return_error_if_the_pgn_has_no_games(t *testing.T) {
+	event := uploadEvent(`{"username": "tigran-c-137", "pgn": "[Event \"Club Championship\"]\n[Site \"Yerevan\"]\n"}`)
+
+	actualResponse, err := api.WithRecover(uploader.UploadPgn)(&event)
+	assert.NoError(t, err)
+	expectedResponseBody := `{"code": "INVALID_PGN", "message": "PGN does not have any game!"}`
+
+	assert.Equal(t, http.StatusUnprocessableEntity, actualResponse.StatusCode, "Response status code is not 422!")
+	assert.JSONEq(t, expectedResponseBody, actualResponse.Body, "Response body is not as expected!")
+}
+
+// failingPublisher publishes the given number of messages and fails to publish the rest.
+type failingPublisher struct {
+	publishable int
+	published   []queue.Message
+}
+
+func (publisher *failingPublisher) Publish(message queue.Message) error {
+	if len(publisher.published) >= publisher.publishable {
+		return errors.New("the queue is not available")
+	}
+	publisher.published = append(publisher.published, message)
+	return nil
+}
+
+func inMemoryUploader(t *testing.T, username string, publisher queue.Publisher) (inMemoryUploader PgnUploader, userId string) {
+	userId = fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
+	inMemoryUsersTable := users.NewInMemoryUsersTable()
+	err := inMemoryUsersTable.PutUserRecord(context.Background(), users.UserRecord{Username: username, UserId: userId, Platform: users.ChessDotCom})
+	assert.NoError(t, err)
+
+	inMemoryUploader = PgnUploader{
+		UsersTable:            inMemoryUsersTable,
+		ArchivesTable:         archives.NewInMemoryArchivesTable(),
+		DownloadsTable:        downloads.NewInMemoryDownloadsTable(),
+		DownloadGamesQueue:    publisher,
+		DownloadInfoExpiresIn: 24 * time.Hour,
+	}
+	return
+}
+
+func Test_PgnUploader_should_count_the_unpublished_chunks_as_failed_and_accept_the_pgn_again(t *testing.T) {
+	username := "tigran-c-137"
+	publisher := &failingPublisher{publishable: 1}
+	inMemoryUploader, userId := inMemoryUploader(t, username, publisher)
+
+	longComment := "{" + strings.Repeat("a", maxChunkSize/2) + "}"
+	pgn := fmt.Sprintf(tournamentPgn, username)
+	pgn = strings.ReplaceAll(pgn, "1. e4", longComment+" 1. e4")
+	pgn = strings.ReplaceAll(pgn, "1. d4", longComment+" 1. d4")
+	requestBody, err := json.Marshal(UploadRequest{Username: username, Pgn: pgn})
+	assert.NoError(t, err)
+	event := uploadEvent(string(requestBody))
+
+	_, err = inMemoryUploader.UploadPgn(&event)
+	assert.Error(t, err)
+	assert.Len(t, publisher.published, 1)
+
+	actualDownloadRecord, err := inMemoryUploader.DownloadsTable.GetDownloadRecord(context.Background(), downloads.NewDownloadId(userId).String())
+	assert.NoError(t, err)
+	if assert.NotNil(t, actualDownloadRecord) {
+		assert.Equal(t, 2, actualDownloadRecord.Total)
+		assert.Equal(t, 1, actualDownloadRecord.Done)
+		assert.Equal(t, 1, actualDownloadRecord.Failed)
+		assert.Equal(t, 1, actualDownloadRecord.Pending)
+	}
+
+	err = inMemoryUploader.DownloadsTable.IncrementSuccess(context.Background(), downloads.NewDownloadId(userId).String(), db.Zuludatetime(time.Now()), time.Hour)
+	assert.NoError(t, err)
+	publisher.publishable = 3
+
+	actualResponse, err := inMemoryUploader.UploadPgn(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, actualResponse.StatusCode, "Response status code is not 200!")
+	assert.Len(t, publisher.published, 3)
+	assert.Equal(t, publisher.published[0].DeduplicationId, publisher.published[1].DeduplicationId)
+}
+
+func Test_PgnUploader_should_reject_a_game_too_large_for_the_download_queue(t *testing.T) {
+	username := "tigran-c-137"
+	publisher := &failingPublisher{publishable: 1}
+	inMemoryUploader, _ := inMemoryUploader(t, username, publisher)
+
+	pgn := fmt.Sprintf(tournamentPgn, username)
+	pgn = strings.ReplaceAll(pgn, "1. d4", "{"+strings.Repeat("a", maxChunkSize)+"} 1. d4")
+	requestBody, err := json.Marshal(UploadRequest{Username: username, Pgn: pgn})
+	assert.NoError(t, err)
+	event := uploadEvent(string(requestBody))
+
+	actualResponse, err := api.WithRecover(inMemoryUploader.UploadPgn)(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, actualResponse.StatusCode, "Response status code is not 422!")
+	assert.JSONEq(t, `{"code": "PGN_GAME_IS_TOO_LARGE", "message": "Game 2 of the PGN is larger than 100 KB!"}`, actualResponse.Body)
+	assert.Empty(t, publisher.published)
+}
+
+func Test_PgnUploader_should_read_the_pgn_file_of_a_form(t *testing.T) {
+	username := "tigran-c-137"
+	publisher := &failingPublisher{publishable: 1}
+	inMemoryUploader, userId := inMemoryUploader(t, username, publisher)
+	pgn := fmt.Sprintf(tournamentPgn, username)
+
+	body := bytes.Buffer{}
+	form := multipart.NewWriter(&body)
+	assert.NoError(t, form.WriteField("username", username))
+	assert.NoError(t, form.WriteField("platform", "CHESS_DOT_COM"))
+	file, err := form.CreateFormFile("pgn", "club_championship.pgn")
+	assert.NoError(t, err)
+	_, err = file.Write([]byte(pgn))
+	assert.NoError(t, err)
+	assert.NoError(t, form.Close())
+
+	event := uploadEvent(body.String())
+	event.Headers = map[string]string{"content-type": form.FormDataContentType()}
+
+	actualResponse, err := inMemoryUploader.UploadPgn(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, actualResponse.StatusCode, "Response status code is not 200!")
+	assert.JSONEq(t, fmt.Sprintf(`{"downloadId": "%v"}`, downloads.NewDownloadId(userId).String()), actualResponse.Body)
+
+	if assert.Len(t, publisher.published, 1) {
+		actualCommand := queue.DownloadGamesCommand{}
+		_, err = queue.DownloadGamesCodec.Decode(publisher.published[0].Body, &actualCommand)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(pgn), actualCommand.Pgn)
+	}
+}
+
+func Test_chunkGames_should_group_the_games_in_their_order_without_exceeding_the_chunk_size(t *testing.T) {
+	pgnGames := []string{"1. e4 e5 *", "1. d4 d5 *", "1. c4 *", "1. Nf3 Nf6 2. g3 g6 *"}
+
+	actualChunks := chunkGames(pgnGames, 20)
+
+	expectedChunks := [][]string{
+		{"1. e4 e5 *", "1. d4 d5 *"},
+		{"1. c4 *"},
+		{"1. Nf3 Nf6 2. g3 g6 *"},
+	}
+
+	assert.Equal(t, expectedChunks, actualChunks)
+}
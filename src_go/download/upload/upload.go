@@ -0,0 +1,53 @@
+package upload
+
+import (
+	"fmt"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+)
+
+type UploadRequest struct {
+	Username string `json:"username"`
+	Platform string `json:"platform"`
+	Pgn      string `json:"pgn"`
+}
+
+type UploadResponse struct {
+	DownloadId string `json:"downloadId"`
+}
+
+var UserNameCannotBeEmpty = api.BusinessError{
+	Code:    "INVALID_USERNAME",
+	Message: "Username cannot be empty!",
+}
+
+var PgnHasNoGames = api.BusinessError{
+	Code:    "INVALID_PGN",
+	Message: "PGN does not have any game!",
+}
+
+func ProfileIsNotCached(username string, platform string) api.BusinessError {
+	return api.BusinessError{
+		Code:    "PROFILE_IS_NOT_CACHED",
+		Message: fmt.Sprintf("Profile %s from %s is not cached!", username, platform),
+	}
+}
+
+func DownloadIsInProgress(downloadId string) api.BusinessError {
+	return api.BusinessError{
+		Code:    "DOWNLOAD_IS_IN_PROGRESS",
+		Message: fmt.Sprintf("Download %s is still in progress!", downloadId),
+	}
+}
+
+func PgnGameIsTooLarge(gameNumber int) api.BusinessError {
+	return api.BusinessError{
+		Code:    "PGN_GAME_IS_TOO_LARGE",
+		Message: fmt.Sprintf("Game %d of the PGN is larger than %d KB!", gameNumber, maxChunkSize/1024),
+	}
+}
+
+var PgnIsAlreadyUploaded = api.BusinessError{
+	Code:    "PGN_IS_ALREADY_UPLOADED",
+	Message: "PGN is already uploaded!",
+}
@@ -1,12 +1,10 @@
-package main
+package check_status
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
@@ -14,8 +12,7 @@ import (
 )
 
 type SearchResultChecker struct {
-	awsConfig         *aws.Config
-	searchesTableName string
+	SearchesTable searches.SearchesRepository
 }
 
 func (checker *SearchResultChecker) Check(event *events.APIGatewayV2HTTPRequest) (responseEvent events.APIGatewayV2HTTPResponse, err error) {
@@ -25,12 +22,6 @@ func (checker *SearchResultChecker) Check(event *events.APIGatewayV2HTTPRequest)
 	logger = logger.With(zap.String("requestId", event.RequestContext.RequestID))
 	defer logger.Sync()
 
-	awsSession, err := session.NewSession(checker.awsConfig)
-	if err != nil {
-		logger.Panic("impossible to create an AWS session!", zap.Error(err))
-	}
-	dynamodbClient := dynamodb.New(awsSession)
-
 	method := event.RequestContext.HTTP.Method
 	path := event.RequestContext.HTTP.Path
 
@@ -49,10 +40,7 @@ func (checker *SearchResultChecker) Check(event *events.APIGatewayV2HTTPRequest)
 
 	logger = logger.With(zap.String("searchId", searchId))
 
-	searchRecordCandidate, err := searches.SearchesTable{
-		Name:           checker.searchesTableName,
-		DynamodbClient: dynamodbClient,
-	}.GetSearchRecord(searchId)
+	searchRecordCandidate, err := checker.SearchesTable.GetSearchRecord(context.Background(), searchId)
 
 	if err != nil {
 		logger.Error("faild to get search!")
@@ -74,6 +62,7 @@ func (checker *SearchResultChecker) Check(event *events.APIGatewayV2HTTPRequest)
 		LastExaminedAt: searchRecord.LastExaminedAt.ToTime(),
 		Examined:       searchRecord.Examined,
 		Matched:        searchRecord.Matched,
+		Matches:        NewMatchResponses(searchRecord),
 		Status:         SearchStatus(string(searchRecord.Status)),
 	}
 	responseBody, err := json.Marshal(searchResultResponse)
@@ -1,6 +1,7 @@
-package main
+package check_status
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -23,15 +25,19 @@ var awsConfig = aws.Config{
 	DisableSSL: aws.Bool(true),
 }
 
-var statusChecker = SearchResultChecker{
-	awsConfig:         &awsConfig,
-	searchesTableName: "chessfinder_dynamodb-searches",
-}
-
-var awsSession = session.Must(session.NewSession(statusChecker.awsConfig))
+var awsSession = session.Must(session.NewSession(&awsConfig))
 
 var dynamodbClient = dynamodb.New(awsSession)
 
+var searchesTable = searches.SearchesTable{
+	Name:           "chessfinder_dynamodb-searches",
+	DynamodbClient: dynamodbClient,
+}
+
+var statusChecker = SearchResultChecker{
+	SearchesTable: searchesTable,
+}
+
 func Test_search_result_is_delivered_if_there_is_a_search_for_given_id(t *testing.T) {
 	var err error
 
@@ -40,7 +46,7 @@ func Test_search_result_is_delivered_if_there_is_a_search_for_given_id(t *testin
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
 	board := uuid.New().String()
-	searchId := searches.NewSearchId(userId, &downloadStartedAt, board)
+	searchId := searches.NewSearchId(userId, &downloadStartedAt, board, searches.BoardConstraints{}, games.GameFilter{})
 
 	event := events.APIGatewayV2HTTPRequest{
 		RequestContext: events.APIGatewayV2HTTPRequestContext{
@@ -70,21 +76,102 @@ func Test_search_result_is_delivered_if_there_is_a_search_for_given_id(t *testin
 		ExpiresAt:      dynamodbattribute.UnixTime(startOfTest.Add(24 * time.Hour)),
 	}
 
-	err = searches.SearchesTable{
-		Name:           statusChecker.searchesTableName,
-		DynamodbClient: dynamodbClient,
-	}.PutSearchRecord(searchRecord)
+	err = searchesTable.PutSearchRecord(context.Background(), searchRecord)
 	assert.NoError(t, err)
 
 	actualResponse, err := statusChecker.Check(&event)
 	assert.NoError(t, err)
 
-	expectedResponseBody := fmt.Sprintf(`{"searchId":"%v","startAt":"2021-01-01T00:00:00Z","lastExaminedAt":"2021-02-01T00:11:24Z","examined":15,"total":100,"matched":["https://www.chess.com/game/live/88624306385","https://www.chess.com/game/live/88704743803"],"status":"SEARCHED_ALL"}`, searchId)
+	expectedResponseBody := fmt.Sprintf(`{"searchId":"%v","startAt":"2021-01-01T00:00:00Z","lastExaminedAt":"2021-02-01T00:11:24Z","examined":15,"total":100,"matched":["https://www.chess.com/game/live/88624306385","https://www.chess.com/game/live/88704743803"],"matches":[{"resource":"https://www.chess.com/game/live/88624306385"},{"resource":"https://www.chess.com/game/live/88704743803"}],"status":"SEARCHED_ALL"}`, searchId)
 
 	assert.JSONEq(t, expectedResponseBody, actualResponse.Body, "Expected download status is not met!")
 	assert.Equal(t, 200, actualResponse.StatusCode, "Expected status code is not met!")
 }
 
+func Test_search_result_contains_the_location_of_every_match(t *testing.T) {
+	var err error
+
+	startOfTest := time.Now()
+
+	userId := uuid.New().String()
+	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
+	board := uuid.New().String()
+	searchId := searches.NewSearchId(userId, &downloadStartedAt, board, searches.BoardConstraints{}, games.GameFilter{})
+
+	event := events.APIGatewayV2HTTPRequest{
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "GET",
+				Path:   "/api/faster/board",
+			},
+		},
+		QueryStringParameters: map[string]string{
+			"searchId": searchId.String(),
+		},
+	}
+
+	startAt, err := db.ZuluDateTimeFromString("2021-01-01T00:00:00.000Z")
+	assert.NoError(t, err)
+	lastExaminedAt, err := db.ZuluDateTimeFromString("2021-02-01T00:11:24.000Z")
+	assert.NoError(t, err)
+
+	searchRecord := searches.SearchRecord{
+		SearchId:       searchId,
+		LastExaminedAt: lastExaminedAt,
+		StartAt:        startAt,
+		Examined:       15,
+		Total:          100,
+		Matched:        []string{"https://www.chess.com/game/live/88704743803", "https://www.chess.com/game/live/88624306385"},
+		Matches: []searches.Match{
+			{
+				Resource: "https://www.chess.com/game/live/88704743803",
+				Location: &searches.MatchLocation{
+					Ply:        3,
+					MoveNumber: 2,
+					SideToMove: "black",
+					Fen:        "rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2",
+				},
+			},
+			{
+				Resource: "https://www.chess.com/game/live/88624306385",
+				Location: &searches.MatchLocation{
+					Ply:        1,
+					MoveNumber: 1,
+					SideToMove: "black",
+					Fen:        "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+				},
+				Steps: []searches.MatchLocation{
+					{
+						Ply:        1,
+						MoveNumber: 1,
+						SideToMove: "black",
+						Fen:        "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+					},
+					{
+						Ply:        2,
+						MoveNumber: 2,
+						SideToMove: "white",
+						Fen:        "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2",
+					},
+				},
+			},
+		},
+		Status:    "SEARCHED_ALL",
+		ExpiresAt: dynamodbattribute.UnixTime(startOfTest.Add(24 * time.Hour)),
+	}
+
+	err = searchesTable.PutSearchRecord(context.Background(), searchRecord)
+	assert.NoError(t, err)
+
+	actualResponse, err := statusChecker.Check(&event)
+	assert.NoError(t, err)
+
+	expectedResponseBody := fmt.Sprintf(`{"searchId":"%v","startAt":"2021-01-01T00:00:00Z","lastExaminedAt":"2021-02-01T00:11:24Z","examined":15,"total":100,"matched":["https://www.chess.com/game/live/88624306385","https://www.chess.com/game/live/88704743803"],"matches":[{"resource":"https://www.chess.com/game/live/88704743803","location":{"ply":3,"moveNumber":2,"sideToMove":"black","fen":"rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2"}},{"resource":"https://www.chess.com/game/live/88624306385","location":{"ply":1,"moveNumber":1,"sideToMove":"black","fen":"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"},"steps":[{"ply":1,"moveNumber":1,"sideToMove":"black","fen":"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"},{"ply":2,"moveNumber":2,"sideToMove":"white","fen":"rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2"}]}],"status":"SEARCHED_ALL"}`, searchId)
+
+	assert.JSONEq(t, expectedResponseBody, actualResponse.Body, "Expected search result is not met!")
+	assert.Equal(t, 200, actualResponse.StatusCode, "Expected status code is not met!")
+}
+
 func Test_search_result_not_found_is_responded_if_there_is_no_search_for_given_id(t *testing.T) {
 
 	searchId := uuid.New().String()
@@ -107,3 +194,34 @@ func Test_search_result_not_found_is_responded_if_there_is_no_search_for_given_i
 	assert.JSONEq(t, expectedResponseBody, actualResponse.Body, "Expected error is not met!")
 	assert.Equal(t, 422, actualResponse.StatusCode, "Expected status code is not met!")
 }
+
+func Test_search_result_is_delivered_from_any_searches_repository(t *testing.T) {
+	inMemorySearchesTable := searches.NewInMemorySearchesTable()
+	checker := SearchResultChecker{SearchesTable: inMemorySearchesTable}
+
+	startAt := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	searchId := searches.NewSearchId("https://api.chess.com/pub/player/tigran-c-137", nil, "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????", searches.BoardConstraints{}, games.GameFilter{})
+	err := inMemorySearchesTable.PutSearchRecord(context.Background(), searches.NewSearchRecord(searchId, startAt, 100, time.Hour))
+	assert.NoError(t, err)
+	err = inMemorySearchesTable.UpdateMatchings(context.Background(), searchId.String(), 15, nil, db.Zuludatetime(startAt.Add(11*time.Minute)), time.Hour)
+	assert.NoError(t, err)
+
+	event := events.APIGatewayV2HTTPRequest{
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "GET",
+				Path:   "/api/faster/board",
+			},
+		},
+		QueryStringParameters: map[string]string{
+			"searchId": searchId.String(),
+		},
+	}
+
+	actualResponse, err := checker.Check(&event)
+	assert.NoError(t, err)
+
+	expectedResponseBody := fmt.Sprintf(`{"searchId":"%v","startAt":"2021-01-01T00:00:00Z","lastExaminedAt":"2021-01-01T00:11:00Z","examined":15,"total":100,"matched":null,"matches":[],"status":"IN_PROGRESS"}`, searchId)
+	assert.JSONEq(t, expectedResponseBody, actualResponse.Body)
+	assert.Equal(t, 200, actualResponse.StatusCode)
+}
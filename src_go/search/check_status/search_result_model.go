@@ -1,10 +1,11 @@
-package main
+package check_status
 
 import (
 	"fmt"
 	"time"
 
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
 )
 
 type SearchStatus string
@@ -16,13 +17,51 @@ const (
 )
 
 type SearchResultResponse struct {
-	SearchId       string       `json:"searchId"`
-	StartAt        time.Time    `json:"startAt"`
-	LastExaminedAt time.Time    `json:"lastExaminedAt"`
-	Examined       int          `json:"examined"`
-	Total          int          `json:"total"`
-	Matched        []string     `json:"matched"`
-	Status         SearchStatus `json:"status"`
+	SearchId       string          `json:"searchId"`
+	StartAt        time.Time       `json:"startAt"`
+	LastExaminedAt time.Time       `json:"lastExaminedAt"`
+	Examined       int             `json:"examined"`
+	Total          int             `json:"total"`
+	Matched        []string        `json:"matched"`
+	Matches        []MatchResponse `json:"matches"`
+	Status         SearchStatus    `json:"status"`
+}
+
+type MatchResponse struct {
+	Resource string                  `json:"resource"`
+	Location *MatchLocationResponse  `json:"location,omitempty"`
+	Steps    []MatchLocationResponse `json:"steps,omitempty"`
+}
+
+type MatchLocationResponse struct {
+	Ply        int    `json:"ply"`
+	MoveNumber int    `json:"moveNumber"`
+	SideToMove string `json:"sideToMove"`
+	Fen        string `json:"fen"`
+}
+
+// NewMatchResponses prefers the matches with locations and falls back to the bare resources
+// of the searches recorded before the locations were tracked.
+func NewMatchResponses(searchRecord searches.SearchRecord) []MatchResponse {
+	responses := []MatchResponse{}
+	if len(searchRecord.Matches) == 0 {
+		for _, resource := range searchRecord.Matched {
+			responses = append(responses, MatchResponse{Resource: resource})
+		}
+		return responses
+	}
+	for _, match := range searchRecord.Matches {
+		response := MatchResponse{Resource: match.Resource}
+		if match.Location != nil {
+			location := newMatchLocationResponse(*match.Location)
+			response.Location = &location
+		}
+		for _, step := range match.Steps {
+			response.Steps = append(response.Steps, newMatchLocationResponse(step))
+		}
+		responses = append(responses, response)
+	}
+	return responses
 }
 
 func SearchNotFound(searchId string) api.BusinessError {
@@ -31,3 +70,12 @@ func SearchNotFound(searchId string) api.BusinessError {
 		Code:    "SEARCH_RESULT_NOT_FOUND",
 	}
 }
+
+func newMatchLocationResponse(location searches.MatchLocation) MatchLocationResponse {
+	return MatchLocationResponse{
+		Ply:        location.Ply,
+		MoveNumber: location.MoveNumber,
+		SideToMove: location.SideToMove,
+		Fen:        location.Fen,
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package initiate
 
 import (
 	"encoding/json"
@@ -9,12 +9,28 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess"
 )
 
 type BoardValidator interface {
 	Validate(requestId string, board string, logger *zap.Logger) (bool, *string, error)
 }
 
+type PpnBoardValidator struct{}
+
+func (validator PpnBoardValidator) Validate(requestId string, board string, logger *zap.Logger) (isValid bool, comment *string, err error) {
+	_, errOfParsing := chess.ParsePpn(board)
+	if errOfParsing != nil {
+		logger.Info("board is not a valid PPN", zap.Error(errOfParsing))
+		reason := errOfParsing.Error()
+		comment = &reason
+		return
+	}
+
+	isValid = true
+	return
+}
+
 type DelegatedBoardValidator struct {
 	FunctionName string
 	AwsConfig    *aws.Config
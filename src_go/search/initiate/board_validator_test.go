@@ -0,0 +1,31 @@
+package initiate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_PpnBoardValidator_should_accept_a_valid_board(t *testing.T) {
+	isValid, comment, err := PpnBoardValidator{}.Validate("requestId", "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????", zap.NewNop())
+	assert.NoError(t, err)
+	assert.True(t, isValid)
+	assert.Nil(t, comment)
+}
+
+func Test_PpnBoardValidator_should_reject_an_invalid_board_and_explain_why(t *testing.T) {
+	isValid, comment, err := PpnBoardValidator{}.Validate("requestId", "????R?r?/?????kq?/????Q???/????????/???x????/????????/????????/????????", zap.NewNop())
+	assert.NoError(t, err)
+	assert.False(t, isValid)
+	if assert.NotNil(t, comment) {
+		assert.Equal(t, "invalid PPN at rank 4, file d: unknown symbol 'x'", *comment)
+	}
+}
+
+func Test_PpnBoardValidator_should_reject_a_string_that_is_not_a_board(t *testing.T) {
+	isValid, comment, err := PpnBoardValidator{}.Validate("requestId", "this is not a searchfen!", zap.NewNop())
+	assert.NoError(t, err)
+	assert.False(t, isValid)
+	assert.NotNil(t, comment)
+}
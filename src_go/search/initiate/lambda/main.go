@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/search/initiate"
+)
+
+func main() {
+
+	usersTableName, usersTableExists := os.LookupEnv("USERS_TABLE_NAME")
+	if !usersTableExists {
+		panic(errors.New("USERS_TABLE_NAME is missing"))
+	}
+
+	archivesTableName, archivesTableNameExists := os.LookupEnv("ARCHIVES_TABLE_NAME")
+	if !archivesTableNameExists {
+		panic(errors.New("ARCHIVES_TABLE_NAME is missing"))
+	}
+
+	downloadsTableName, downloadsTableNameExists := os.LookupEnv("DOWNLOADS_TABLE_NAME")
+	if !downloadsTableNameExists {
+		panic(errors.New("DOWNLOADS_TABLE_NAME is missing"))
+	}
+
+	searchesTableName, searchesTableNameExists := os.LookupEnv("SEARCHES_TABLE_NAME")
+	if !searchesTableNameExists {
+		panic(errors.New("SEARCHES_TABLE_NAME is missing"))
+	}
+
+	gamesTableName, gamesTableNameExists := os.LookupEnv("GAMES_TABLE_NAME")
+	if !gamesTableNameExists {
+		panic(errors.New("GAMES_TABLE_NAME is missing"))
+	}
+
+	searchBoardQueueUrl, searchBoardQueueUrlExists := os.LookupEnv("SEARCH_BOARD_QUEUE_URL")
+	if !searchBoardQueueUrlExists {
+		panic(errors.New("SEARCH_BOARD_QUEUE_URL is missing"))
+	}
+
+	searchInfoExpiresInCadidate, searchInfoExpiresInExists := os.LookupEnv("SEARCH_INFO_EXPIRES_IN_SECONDS")
+	if !searchInfoExpiresInExists {
+		panic(errors.New("SEARCH_INFO_EXPIRES_IN_SECONDS is missing"))
+	}
+
+	searchInfoExpiresIn, err := time.ParseDuration(searchInfoExpiresInCadidate + "s")
+	if err != nil {
+		panic(err)
+	}
+
+	theStackName, theStackNameExists := os.LookupEnv("THE_STACK_NAME")
+	if !theStackNameExists {
+		panic(errors.New("THE_STACK_NAME is missing"))
+	}
+
+	awsRegion, awsRegionExists := os.LookupEnv("AWS_REGION")
+	if !awsRegionExists {
+		panic(errors.New("AWS_REGION is missing"))
+	}
+
+	awsConfig := &aws.Config{
+		Region: &awsRegion,
+	}
+
+	awsSession := session.Must(session.NewSession(awsConfig))
+	dynamodbClient := dynamodb.New(awsSession)
+
+	registrar := initiate.SearchRegistrar{
+		UsersTable: users.UsersTable{
+			Name:           usersTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		ArchivesTable: archives.ArchivesTable{
+			Name:           archivesTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		DownloadsTable: downloads.DownloadsTable{
+			Name:           downloadsTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		SearchesTable: searches.SearchesTable{
+			Name:           searchesTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		GamesTable: games.GamesTable{
+			Name:           gamesTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		SearchBoardQueue: queue.SqsQueue{
+			QueueUrl:  searchBoardQueueUrl,
+			SqsClient: sqs.New(awsSession),
+		},
+		SearchInfoExpiresIn: searchInfoExpiresIn,
+		MetricsNamespace:    theStackName,
+		CloudWatchClient:    cloudwatch.New(awsSession),
+		Validator:           initiate.PpnBoardValidator{},
+	}
+
+	tracing.MustSetUp("chessfinder-search-initiate")
+
+	lambda.Start(api.WithRecover(registrar.RegisterSearchRequest))
+
+}
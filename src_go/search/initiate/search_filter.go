@@ -0,0 +1,69 @@
+package initiate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+)
+
+const filterDateLayout = "2006-01-02"
+
+// The time classes of chess.com and the speeds of lichess.
+var timeClasses = map[string]bool{
+	"ultrabullet":    true,
+	"bullet":         true,
+	"blitz":          true,
+	"rapid":          true,
+	"classical":      true,
+	"daily":          true,
+	"correspondence": true,
+}
+
+// GameFilter reads the optional filters of the request. The end dates are UTC days, both inclusive.
+func (request SearchRequest) GameFilter() (filter games.GameFilter, err error) {
+	if request.EndedFrom != "" {
+		endedFrom, errOfParsing := time.Parse(filterDateLayout, request.EndedFrom)
+		if errOfParsing != nil {
+			err = InvalidSearchFilters(fmt.Sprintf("endedFrom %q is not a date like 2006-01-02", request.EndedFrom))
+			return
+		}
+		filter.EndedFrom = endedFrom.Unix()
+	}
+
+	if request.EndedTo != "" {
+		endedTo, errOfParsing := time.Parse(filterDateLayout, request.EndedTo)
+		if errOfParsing != nil {
+			err = InvalidSearchFilters(fmt.Sprintf("endedTo %q is not a date like 2006-01-02", request.EndedTo))
+			return
+		}
+		filter.EndedTo = endedTo.AddDate(0, 0, 1).Unix() - 1
+	}
+
+	if filter.EndedFrom != 0 && filter.EndedTo != 0 && filter.EndedFrom > filter.EndedTo {
+		err = InvalidSearchFilters("endedFrom is after endedTo")
+		return
+	}
+
+	filter.Color = strings.ToLower(request.Color)
+	if filter.Color != "" && filter.Color != games.White && filter.Color != games.Black {
+		err = InvalidSearchFilters(fmt.Sprintf("unknown color %q", request.Color))
+		return
+	}
+
+	filter.Result = strings.ToLower(request.Result)
+	if filter.Result != "" && filter.Result != games.Win && filter.Result != games.Draw && filter.Result != games.Loss {
+		err = InvalidSearchFilters(fmt.Sprintf("unknown result %q", request.Result))
+		return
+	}
+
+	filter.TimeClass = strings.ToLower(request.TimeClass)
+	if filter.TimeClass != "" && !timeClasses[filter.TimeClass] {
+		err = InvalidSearchFilters(fmt.Sprintf("unknown time class %q", request.TimeClass))
+		return
+	}
+
+	filter.Opponent = strings.ToLower(strings.TrimSpace(request.Opponent))
+	return
+}
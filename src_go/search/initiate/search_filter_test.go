@@ -0,0 +1,53 @@
+package initiate
+
+import (
+	"testing"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SearchRequest_should_turn_the_filters_into_a_GameFilter(t *testing.T) {
+	request := SearchRequest{
+		EndedFrom: "2022-08-01",
+		EndedTo:   "2022-08-31",
+		Color:     "Black",
+		Result:    "win",
+		TimeClass: "blitz",
+		Opponent:  " N-60 ",
+	}
+
+	actualFilter, err := request.GameFilter()
+	assert.NoError(t, err)
+
+	expectedFilter := games.GameFilter{
+		EndedFrom: 1659312000,
+		EndedTo:   1661990399,
+		Color:     games.Black,
+		Result:    games.Win,
+		TimeClass: "blitz",
+		Opponent:  "n-60",
+	}
+	assert.Equal(t, expectedFilter, actualFilter)
+
+	actualFilter, err = SearchRequest{}.GameFilter()
+	assert.NoError(t, err)
+	assert.True(t, actualFilter.IsEmpty())
+}
+
+func Test_SearchRequest_should_reject_invalid_filters(t *testing.T) {
+	_, err := SearchRequest{EndedFrom: "01.08.2022"}.GameFilter()
+	assert.Equal(t, InvalidSearchFilters(`endedFrom "01.08.2022" is not a date like 2006-01-02`), err)
+
+	_, err = SearchRequest{EndedFrom: "2022-09-01", EndedTo: "2022-08-31"}.GameFilter()
+	assert.Equal(t, InvalidSearchFilters("endedFrom is after endedTo"), err)
+
+	_, err = SearchRequest{Color: "red"}.GameFilter()
+	assert.Equal(t, InvalidSearchFilters(`unknown color "red"`), err)
+
+	_, err = SearchRequest{Result: "abandoned"}.GameFilter()
+	assert.Equal(t, InvalidSearchFilters(`unknown result "abandoned"`), err)
+
+	_, err = SearchRequest{TimeClass: "hyperbullet"}.GameFilter()
+	assert.Equal(t, InvalidSearchFilters(`unknown time class "hyperbullet"`), err)
+}
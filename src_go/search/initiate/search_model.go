@@ -1,4 +1,4 @@
-package main
+package initiate
 
 import (
 	"fmt"
@@ -7,9 +7,20 @@ import (
 )
 
 type SearchRequest struct {
-	Username string `json:"username"`
-	Platform string `json:"platform"`
-	Board    string `json:"board"`
+	Username   string   `json:"username"`
+	Platform   string   `json:"platform"`
+	Board      string   `json:"board"`
+	Boards     []string `json:"boards,omitempty"`
+	MaxPlyGap  int      `json:"maxPlyGap,omitempty"`
+	SideToMove string   `json:"sideToMove,omitempty"`
+	Castling   string   `json:"castling,omitempty"`
+	EnPassant  string   `json:"enPassant,omitempty"`
+	EndedFrom  string   `json:"endedFrom,omitempty"`
+	EndedTo    string   `json:"endedTo,omitempty"`
+	Color      string   `json:"color,omitempty"`
+	Result     string   `json:"result,omitempty"`
+	TimeClass  string   `json:"timeClass,omitempty"`
+	Opponent   string   `json:"opponent,omitempty"`
 }
 
 type SearchResponse struct {
@@ -21,6 +32,32 @@ var InvalidSearchBoard = api.BusinessError{
 	Message: "Invalid board!",
 }
 
+func InvalidSearchBoardAt(reason string) api.BusinessError {
+	return api.BusinessError{
+		Code:    InvalidSearchBoard.Code,
+		Message: fmt.Sprintf("Invalid board: %s!", reason),
+	}
+}
+
+var InvalidSearchSequence = api.BusinessError{
+	Code:    "INVALID_SEARCH_SEQUENCE",
+	Message: "A sequence needs at least two boards instead of the board and a positive maxPlyGap!",
+}
+
+func InvalidSearchConstraints(reason string) api.BusinessError {
+	return api.BusinessError{
+		Code:    "INVALID_SEARCH_CONSTRAINTS",
+		Message: fmt.Sprintf("Invalid constraints: %s!", reason),
+	}
+}
+
+func InvalidSearchFilters(reason string) api.BusinessError {
+	return api.BusinessError{
+		Code:    "INVALID_SEARCH_FILTERS",
+		Message: fmt.Sprintf("Invalid filters: %s!", reason),
+	}
+}
+
 func ProfileIsNotCached(username string, platform string) api.BusinessError {
 	return api.BusinessError{
 		Code:    "PROFILE_IS_NOT_CACHED",
@@ -1,4 +1,4 @@
-package main
+package initiate
 
 import (
 	"encoding/json"
@@ -6,48 +6,43 @@ import (
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/api"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/metrics"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
 	"go.uber.org/zap"
 )
 
 type SearchRegistrar struct {
-	usersTableName      string
-	archivesTableName   string
-	downloadsTableName  string
-	searchesTableName   string
-	searchBoardQueueUrl string
-	searchInfoExpiresIn time.Duration
-	metricsNamespace    string
-	awsConfig           *aws.Config
-	validator           BoardValidator
+	UsersTable          users.UsersRepository
+	ArchivesTable       archives.ArchivesRepository
+	DownloadsTable      downloads.DownloadsRepository
+	SearchesTable       searches.SearchesRepository
+	GamesTable          games.GamesRepository
+	SearchBoardQueue    queue.Publisher
+	SearchInfoExpiresIn time.Duration
+	MetricsNamespace    string
+	CloudWatchClient    *cloudwatch.CloudWatch
+	Validator           BoardValidator
 }
 
 func (registrar *SearchRegistrar) RegisterSearchRequest(event *events.APIGatewayV2HTTPRequest) (responseEvent events.APIGatewayV2HTTPResponse, err error) {
-	logger := logging.MustCreateZuluTimeLogger()
+	ctx, span := tracing.StartRequest(event)
+	defer func() { tracing.End(span, err) }()
+
+	logger := tracing.Logger(ctx, logging.MustCreateZuluTimeLogger())
 	logger = logger.With(zap.String("requestId", event.RequestContext.RequestID))
 	defer logger.Sync()
 
-	awsSession, err := session.NewSession(registrar.awsConfig)
-	if err != nil {
-		logger.Panic("impossible to create an AWS session!")
-		return
-	}
-	dynamodbClient := dynamodb.New(awsSession)
-	svc := sqs.New(awsSession)
-
 	method := event.RequestContext.HTTP.Method
 	path := event.RequestContext.HTTP.Path
 
@@ -58,10 +53,9 @@ func (registrar *SearchRegistrar) RegisterSearchRequest(event *events.APIGateway
 
 	logger = logger.With(zap.String("method", method), zap.String("path", path))
 
-	cloudWatchClient := cloudwatch.New(awsSession)
 	searchAttemptMeter := metrics.SearchAttemtMeter{
-		Namespace:        registrar.metricsNamespace,
-		CloudWatchClient: cloudWatchClient,
+		Namespace:        registrar.MetricsNamespace,
+		CloudWatchClient: registrar.CloudWatchClient,
 	}
 
 	errOfTotalMetricRegistration := searchAttemptMeter.SearchStatistics(metrics.Total)
@@ -85,23 +79,71 @@ func (registrar *SearchRegistrar) RegisterSearchRequest(event *events.APIGateway
 	searchRequest.Username = strings.ToLower(searchRequest.Username)
 
 	logger = logger.With(zap.String("username", searchRequest.Username), zap.String("platform", searchRequest.Platform))
-	logger = logger.With(zap.String("board", searchRequest.Board))
+
+	boards := []string{searchRequest.Board}
+	searchBoard := searchRequest.Board
+	if len(searchRequest.Boards) > 0 {
+		logger = logger.With(zap.Strings("boards", searchRequest.Boards), zap.Int("maxPlyGap", searchRequest.MaxPlyGap))
+		if searchRequest.Board != "" || len(searchRequest.Boards) < 2 || searchRequest.MaxPlyGap < 1 {
+			logger.Info("invalid sequence")
+			err = InvalidSearchSequence
+			return
+		}
+		boards = searchRequest.Boards
+		searchBoard = searches.SequenceBoard(searchRequest.Boards, searchRequest.MaxPlyGap)
+	} else {
+		logger = logger.With(zap.String("board", searchRequest.Board))
+	}
 
 	logger.Info("validating board")
-	if isValid, _, strangeError := registrar.validator.Validate(event.RequestContext.RequestID, searchRequest.Board, logger); !isValid || strangeError != nil {
-		logger.Info("invalid board")
-		if strangeError != nil {
-			logger.Error("error while validating board", zap.Error(strangeError))
+	for _, board := range boards {
+		if isValid, comment, strangeError := registrar.Validator.Validate(event.RequestContext.RequestID, board, logger); !isValid || strangeError != nil {
+			logger.Info("invalid board", zap.String("invalidBoard", board), zap.Stringp("comment", comment))
+			if strangeError != nil {
+				logger.Error("error while validating board", zap.Error(strangeError))
+				err = InvalidSearchBoard
+				return
+			}
+			if comment == nil {
+				err = InvalidSearchBoard
+				return
+			}
+			err = InvalidSearchBoardAt(*comment)
+			return
 		}
-		err = InvalidSearchBoard
+	}
+
+	constraints, errOfConstraints := chess.ParseConstraints(searchRequest.SideToMove, searchRequest.Castling, searchRequest.EnPassant)
+	if errOfConstraints != nil {
+		logger.Info("invalid constraints", zap.Error(errOfConstraints))
+		reason := errOfConstraints.Error()
+		if constraintsError, isConstraintsError := errOfConstraints.(chess.ConstraintsError); isConstraintsError {
+			reason = constraintsError.Reason
+		}
+		err = InvalidSearchConstraints(reason)
 		return
 	}
+	sideToMove, castling, enPassant := constraints.Fields()
+	boardConstraints := searches.BoardConstraints{
+		SideToMove: sideToMove,
+		Castling:   castling,
+		EnPassant:  enPassant,
+	}
+	logger = logger.With(zap.String("sideToMove", sideToMove), zap.String("castling", castling), zap.String("enPassant", enPassant))
+
+	gameFilter, err := searchRequest.GameFilter()
+	if err != nil {
+		logger.Info("invalid filters", zap.Error(err))
+		return
+	}
+	if !gameFilter.IsEmpty() {
+		logger = logger.With(zap.Any("filter", gameFilter))
+	}
 
 	logger.Info("fetching user from db", zap.String("user", searchRequest.Username))
-	user, err := users.UsersTable{
-		Name:           registrar.usersTableName,
-		DynamodbClient: dynamodbClient,
-	}.GetUserRecord(searchRequest.Username, users.Platform(searchRequest.Platform))
+	usersCtx, usersSpan := tracing.StartCall(ctx, "UsersTable.GetUserRecord")
+	user, err := registrar.UsersTable.GetUserRecord(usersCtx, searchRequest.Username, users.Platform(searchRequest.Platform))
+	tracing.End(usersSpan, err)
 
 	if err != nil {
 		logger.Error("error while getting user from db", zap.Error(err))
@@ -118,16 +160,10 @@ func (registrar *SearchRegistrar) RegisterSearchRequest(event *events.APIGateway
 
 	logger.Info("fetching download record ...")
 
-	searchesTable := searches.SearchesTable{
-		Name:           registrar.searchesTableName,
-		DynamodbClient: dynamodbClient,
-	}
-
 	downloadId := downloads.NewDownloadId(user.UserId)
-	exisitngDownload, err := downloads.DownloadsTable{
-		Name:           registrar.downloadsTableName,
-		DynamodbClient: dynamodbClient,
-	}.GetDownloadRecord(downloadId.String())
+	downloadsCtx, downloadsSpan := tracing.StartCall(ctx, "DownloadsTable.GetDownloadRecord")
+	exisitngDownload, err := registrar.DownloadsTable.GetDownloadRecord(downloadsCtx, downloadId.String())
+	tracing.End(downloadsSpan, err)
 
 	if err != nil {
 		logger.Error("error while getting download record", zap.Error(err))
@@ -142,8 +178,10 @@ func (registrar *SearchRegistrar) RegisterSearchRequest(event *events.APIGateway
 		exisitngDownloadStartedAt = &exisitngDownload.StartAt
 	}
 
-	exisitngSearchId := searches.NewSearchId(user.UserId, exisitngDownloadStartedAt, searchRequest.Board)
-	exisitngSearch, err := searchesTable.GetSearchRecord(exisitngSearchId.String())
+	exisitngSearchId := searches.NewSearchId(user.UserId, exisitngDownloadStartedAt, searchBoard, boardConstraints, gameFilter)
+	searchesCtx, searchesSpan := tracing.StartCall(ctx, "SearchesTable.GetSearchRecord")
+	exisitngSearch, err := registrar.SearchesTable.GetSearchRecord(searchesCtx, exisitngSearchId.String())
+	tracing.End(searchesSpan, err)
 	if err != nil {
 		logger.Error("error while getting search record", zap.Error(err))
 		return
@@ -168,11 +206,9 @@ func (registrar *SearchRegistrar) RegisterSearchRequest(event *events.APIGateway
 	}
 
 	logger.Info("fetching archives from db")
-	archives, err :=
-		archives.ArchivesTable{
-			Name:           registrar.archivesTableName,
-			DynamodbClient: dynamodbClient,
-		}.GetArchiveRecords(user.UserId)
+	archivesCtx, archivesSpan := tracing.StartCall(ctx, "ArchivesTable.GetArchiveRecords")
+	archives, err := registrar.ArchivesTable.GetArchiveRecords(archivesCtx, user.UserId)
+	tracing.End(archivesSpan, err)
 
 	if err != nil {
 		return
@@ -189,14 +225,29 @@ func (registrar *SearchRegistrar) RegisterSearchRequest(event *events.APIGateway
 		return
 	}
 
-	searchId := searches.NewSearchId(user.UserId, exisitngDownloadStartedAt, searchRequest.Board)
+	if !gameFilter.IsEmpty() {
+		logger.Info("counting the filtered games")
+		gamesCtx, gamesSpan := tracing.StartCall(ctx, "GamesTable.CountGames")
+		downloadedGames, err = registrar.GamesTable.CountGames(gamesCtx, user.UserId, gameFilter)
+		tracing.End(gamesSpan, err)
+
+		if err != nil {
+			logger.Error("error while counting the filtered games", zap.Error(err))
+			return
+		}
+		logger = logger.With(zap.Int("filteredGames", downloadedGames))
+	}
+
+	searchId := searches.NewSearchId(user.UserId, exisitngDownloadStartedAt, searchBoard, boardConstraints, gameFilter)
 	logger = logger.With(zap.String("searchResultId", searchId.String()))
 	now := time.Now()
 
-	searchResult := searches.NewSearchRecord(searchId, now, downloadedGames, registrar.searchInfoExpiresIn)
+	searchResult := searches.NewSearchRecord(searchId, now, downloadedGames, registrar.SearchInfoExpiresIn)
 
 	logger.Info("putting search result")
-	err = searchesTable.PutSearchRecord(searchResult)
+	putSearchCtx, putSearchSpan := tracing.StartCall(ctx, "SearchesTable.PutSearchRecord")
+	err = registrar.SearchesTable.PutSearchRecord(putSearchCtx, searchResult)
+	tracing.End(putSearchSpan, err)
 
 	if err != nil {
 		logger.Error("error while persisting search record", zap.Error(err))
@@ -209,22 +260,34 @@ func (registrar *SearchRegistrar) RegisterSearchRequest(event *events.APIGateway
 		UserId:   user.UserId,
 		SearchId: searchId.String(),
 		Board:    searchRequest.Board,
+
+		Boards:    searchRequest.Boards,
+		MaxPlyGap: searchRequest.MaxPlyGap,
+
+		SideToMove: sideToMove,
+		Castling:   castling,
+		EnPassant:  enPassant,
+
+		EndedFrom: gameFilter.EndedFrom,
+		EndedTo:   gameFilter.EndedTo,
+		Color:     gameFilter.Color,
+		Result:    gameFilter.Result,
+		TimeClass: gameFilter.TimeClass,
+		Opponent:  gameFilter.Opponent,
 	}
 
-	searchBoardCommandJson, err := json.Marshal(searchBoardCommand)
+	searchBoardCommandBody, err := queue.SearchBoardCodec.Encode(event.RequestContext.RequestID, searchBoardCommand)
 	if err != nil {
 		logger.Error("error while marshalling search board command")
 	}
 
-	sendMessageInput := &sqs.SendMessageInput{
-		MessageBody: aws.String(string(searchBoardCommandJson)),
-		QueueUrl:    aws.String(registrar.searchBoardQueueUrl),
+	err = registrar.SearchBoardQueue.Publish(queue.Message{
+		Body: searchBoardCommandBody,
 		//fixme this should be the boeard, but that makes the test flaky. in test we need to wait for the message to be processed and forgotten by SQS. To overcome this we should generate valid boead each time. That will break the restriction of deduplication.
-		MessageDeduplicationId: aws.String(searchBoardCommand.SearchId),
-		MessageGroupId:         aws.String(user.UserId),
-	}
-
-	_, err = svc.SendMessage(sendMessageInput)
+		DeduplicationId: searchBoardCommand.SearchId,
+		GroupId:         user.UserId,
+		Attributes:      tracing.Inject(ctx),
+	})
 	if err != nil {
 		logger.Error("error while sending search board command", zap.Error(err))
 	}
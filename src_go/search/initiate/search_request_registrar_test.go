@@ -1,6 +1,7 @@
-package main
+package initiate
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -18,6 +20,7 @@ import (
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/archives"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/downloads"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
@@ -41,35 +44,45 @@ func (s MockedValidator) Validate(requestId string, board string, logger *zap.Lo
 }
 
 var registrar = SearchRegistrar{
-	usersTableName:      "chessfinder_dynamodb-users",
-	archivesTableName:   "chessfinder_dynamodb-archives",
-	downloadsTableName:  "chessfinder_dynamodb-downloads",
-	searchesTableName:   "chessfinder_dynamodb-searches",
-	searchBoardQueueUrl: "http://localhost:4566/000000000000/chessfinder_sqs-SearchBoard.fifo",
-	searchInfoExpiresIn: 24 * time.Hour,
-	awsConfig:           &awsConfig,
+	UsersTable:          usersTable,
+	ArchivesTable:       archivesTable,
+	DownloadsTable:      downloadsTable,
+	SearchesTable:       searchesTable,
+	GamesTable:          gamesTable,
+	SearchBoardQueue:    searchBoardQueue,
+	SearchInfoExpiresIn: 24 * time.Hour,
+	CloudWatchClient:    cloudwatch.New(awsSession),
 }
 var awsSession = session.Must(session.NewSession(&awsConfig))
 var dynamodbClient = dynamodb.New(awsSession)
 var sqsClient = sqs.New(awsSession)
+var searchBoardQueue = queue.SqsQueue{
+	QueueUrl:  "http://localhost:4566/000000000000/chessfinder_sqs-SearchBoard.fifo",
+	SqsClient: sqsClient,
+}
 
 var usersTable = users.UsersTable{
-	Name:           registrar.usersTableName,
+	Name:           "chessfinder_dynamodb-users",
 	DynamodbClient: dynamodbClient,
 }
 
 var archivesTable = archives.ArchivesTable{
-	Name:           registrar.archivesTableName,
+	Name:           "chessfinder_dynamodb-archives",
 	DynamodbClient: dynamodbClient,
 }
 
 var downloadsTable = downloads.DownloadsTable{
-	Name:           registrar.downloadsTableName,
+	Name:           "chessfinder_dynamodb-downloads",
+	DynamodbClient: dynamodbClient,
+}
+
+var gamesTable = games.GamesTable{
+	Name:           "chessfinder_dynamodb-games",
 	DynamodbClient: dynamodbClient,
 }
 
 var searchesTable = searches.SearchesTable{
-	Name:           registrar.searchesTableName,
+	Name:           "chessfinder_dynamodb-searches",
 	DynamodbClient: dynamodbClient,
 }
 
@@ -77,7 +90,7 @@ func Test_SearchRegistrar_should_emit_SearchBoardCommand_for_an_existing_user_if
 	var err error
 	startOfTest := time.Now()
 
-	registrar.validator = MockedValidator{isAlwaysValid: true}
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
 
 	username := uuid.New().String()
 	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
@@ -87,7 +100,7 @@ func Test_SearchRegistrar_should_emit_SearchBoardCommand_for_an_existing_user_if
 		Platform: users.ChessDotCom,
 	}
 
-	err = usersTable.PutUserRecord(user)
+	err = usersTable.PutUserRecord(context.Background(), user)
 	assert.NoError(t, err)
 
 	downloadId := downloads.NewDownloadId(userId)
@@ -103,14 +116,14 @@ func Test_SearchRegistrar_should_emit_SearchBoardCommand_for_an_existing_user_if
 		ExpiresAt:        dynamodbattribute.UnixTime(startOfTest.Add(24 * time.Hour)),
 	}
 
-	err = downloadsTable.PutDownloadRecord(download)
+	err = downloadsTable.PutDownloadRecord(context.Background(), download)
 	assert.NoError(t, err)
 
 	otherBoard := "????R?r?/?????kR?/????Q???/????????/????????/????????/????????/????????"
-	otherSearchId := searches.NewSearchId(userId, &download.StartAt, otherBoard)
+	otherSearchId := searches.NewSearchId(userId, &download.StartAt, otherBoard, searches.BoardConstraints{}, games.GameFilter{})
 	otherSearch := searches.NewSearchRecord(otherSearchId, startOfTest, 10, 24*time.Hour)
 
-	err = searchesTable.PutSearchRecord(otherSearch)
+	err = searchesTable.PutSearchRecord(context.Background(), otherSearch)
 	assert.NoError(t, err)
 
 	archive1Resource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
@@ -125,7 +138,7 @@ func Test_SearchRegistrar_should_emit_SearchBoardCommand_for_an_existing_user_if
 		Downloaded:   17,
 	}
 
-	err = archivesTable.PutArchiveRecord(archive1)
+	err = archivesTable.PutArchiveRecord(context.Background(), archive1)
 	assert.NoError(t, err)
 
 	archive2Resource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/11", username)
@@ -140,7 +153,7 @@ func Test_SearchRegistrar_should_emit_SearchBoardCommand_for_an_existing_user_if
 		Downloaded:   23,
 	}
 
-	err = archivesTable.PutArchiveRecord(archive2)
+	err = archivesTable.PutArchiveRecord(context.Background(), archive2)
 	assert.NoError(t, err)
 
 	event := events.APIGatewayV2HTTPRequest{
@@ -164,7 +177,7 @@ func Test_SearchRegistrar_should_emit_SearchBoardCommand_for_an_existing_user_if
 	err = json.Unmarshal([]byte(actualResponse.Body), &actualSearchResultResponse)
 	assert.NoError(t, err)
 
-	actualSearchRecord, err := searchesTable.GetSearchRecord(actualSearchResultResponse.SearchId)
+	actualSearchRecord, err := searchesTable.GetSearchRecord(context.Background(), actualSearchResultResponse.SearchId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualSearchRecord)
 
@@ -177,14 +190,14 @@ func Test_SearchRegistrar_should_emit_SearchBoardCommand_for_an_existing_user_if
 	assert.True(t, startOfTest.Before(actualSearchRecord.LastExaminedAt.ToTime()))
 	assert.True(t, startOfCheck.After(actualSearchRecord.LastExaminedAt.ToTime()))
 
-	assert.True(t, startOfTest.Add(registrar.searchInfoExpiresIn-time.Second).Before(time.Time(actualSearchRecord.ExpiresAt)))
-	assert.True(t, startOfCheck.Add(registrar.searchInfoExpiresIn+time.Second).After(time.Time(actualSearchRecord.ExpiresAt)))
+	assert.True(t, startOfTest.Add(registrar.SearchInfoExpiresIn-time.Second).Before(time.Time(actualSearchRecord.ExpiresAt)))
+	assert.True(t, startOfCheck.Add(registrar.SearchInfoExpiresIn+time.Second).After(time.Time(actualSearchRecord.ExpiresAt)))
 
-	lastCommand, err := queue.GetLastNCommands(sqsClient, registrar.searchBoardQueueUrl, 1)
+	lastCommand, err := queue.GetLastNCommands(sqsClient, searchBoardQueue.QueueUrl, 1)
 	assert.NoError(t, err)
 
 	actualCommand := queue.SearchBoardCommand{}
-	err = json.Unmarshal([]byte(*lastCommand[0].Body), &actualCommand)
+	_, err = queue.SearchBoardCodec.Decode(*lastCommand[0].Body, &actualCommand)
 	if err != nil {
 		return
 	}
@@ -203,7 +216,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 	var err error
 	startOfTest := time.Now()
 
-	registrar.validator = MockedValidator{isAlwaysValid: true}
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
 
 	username := uuid.New().String()
 	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
@@ -213,7 +226,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 		Platform: users.ChessDotCom,
 	}
 
-	err = usersTable.PutUserRecord(user)
+	err = usersTable.PutUserRecord(context.Background(), user)
 	assert.NoError(t, err)
 
 	downloadId := downloads.NewDownloadId(userId)
@@ -229,14 +242,14 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 		ExpiresAt:        dynamodbattribute.UnixTime(startOfTest.Add(24 * time.Hour)),
 	}
 
-	err = downloadsTable.PutDownloadRecord(download)
+	err = downloadsTable.PutDownloadRecord(context.Background(), download)
 	assert.NoError(t, err)
 
 	board := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
-	existingSearchId := searches.NewSearchId(userId, &download.StartAt, board)
+	existingSearchId := searches.NewSearchId(userId, &download.StartAt, board, searches.BoardConstraints{}, games.GameFilter{})
 	existingSearch := searches.NewSearchRecord(existingSearchId, startOfTest, 10, 24*time.Hour)
 
-	err = searchesTable.PutSearchRecord(existingSearch)
+	err = searchesTable.PutSearchRecord(context.Background(), existingSearch)
 	assert.NoError(t, err)
 
 	archive1Resource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
@@ -251,7 +264,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 		Downloaded:   17,
 	}
 
-	err = archivesTable.PutArchiveRecord(archive1)
+	err = archivesTable.PutArchiveRecord(context.Background(), archive1)
 	assert.NoError(t, err)
 
 	archive2Resource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/11", username)
@@ -266,7 +279,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 		Downloaded:   23,
 	}
 
-	err = archivesTable.PutArchiveRecord(archive2)
+	err = archivesTable.PutArchiveRecord(context.Background(), archive2)
 	assert.NoError(t, err)
 
 	event := events.APIGatewayV2HTTPRequest{
@@ -288,7 +301,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 	err = json.Unmarshal([]byte(actualResponse.Body), &actualSearchResultResponse)
 	assert.NoError(t, err)
 
-	actualSearchRecord, err := searchesTable.GetSearchRecord(actualSearchResultResponse.SearchId)
+	actualSearchRecord, err := searchesTable.GetSearchRecord(context.Background(), actualSearchResultResponse.SearchId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualSearchRecord)
 
@@ -301,7 +314,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 	assert.ElementsMatch(t, existingSearch.Matched, actualSearchRecord.Matched)
 	assert.Equal(t, time.Time(existingSearch.ExpiresAt).Unix(), time.Time(actualSearchRecord.ExpiresAt).Unix())
 
-	lastCommand, err := queue.GetLastNCommands(sqsClient, registrar.searchBoardQueueUrl, 1)
+	lastCommand, err := queue.GetLastNCommands(sqsClient, searchBoardQueue.QueueUrl, 1)
 	assert.NoError(t, err)
 	assert.Nil(t, lastCommand)
 
@@ -311,7 +324,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 	var err error
 	startOfTest := time.Now()
 
-	registrar.validator = MockedValidator{isAlwaysValid: true}
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
 
 	username := uuid.New().String()
 	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
@@ -321,14 +334,14 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 		Platform: users.ChessDotCom,
 	}
 
-	err = usersTable.PutUserRecord(user)
+	err = usersTable.PutUserRecord(context.Background(), user)
 	assert.NoError(t, err)
 
 	board := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
-	existingSearchId := searches.NewSearchId(userId, nil, board)
+	existingSearchId := searches.NewSearchId(userId, nil, board, searches.BoardConstraints{}, games.GameFilter{})
 	existingSearch := searches.NewSearchRecord(existingSearchId, startOfTest, 10, 24*time.Hour)
 
-	err = searchesTable.PutSearchRecord(existingSearch)
+	err = searchesTable.PutSearchRecord(context.Background(), existingSearch)
 	assert.NoError(t, err)
 
 	archive1Resource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
@@ -343,7 +356,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 		Downloaded:   17,
 	}
 
-	err = archivesTable.PutArchiveRecord(archive1)
+	err = archivesTable.PutArchiveRecord(context.Background(), archive1)
 	assert.NoError(t, err)
 
 	archive2Resource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/11", username)
@@ -358,7 +371,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 		Downloaded:   23,
 	}
 
-	err = archivesTable.PutArchiveRecord(archive2)
+	err = archivesTable.PutArchiveRecord(context.Background(), archive2)
 	assert.NoError(t, err)
 
 	event := events.APIGatewayV2HTTPRequest{
@@ -380,7 +393,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 	err = json.Unmarshal([]byte(actualResponse.Body), &actualSearchResultResponse)
 	assert.NoError(t, err)
 
-	actualSearchRecord, err := searchesTable.GetSearchRecord(actualSearchResultResponse.SearchId)
+	actualSearchRecord, err := searchesTable.GetSearchRecord(context.Background(), actualSearchResultResponse.SearchId)
 	assert.NoError(t, err)
 	assert.NotNil(t, actualSearchRecord)
 
@@ -393,7 +406,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 	assert.ElementsMatch(t, existingSearch.Matched, actualSearchRecord.Matched)
 	assert.Equal(t, time.Time(existingSearch.ExpiresAt).Unix(), time.Time(actualSearchRecord.ExpiresAt).Unix())
 
-	lastCommand, err := queue.GetLastNCommands(sqsClient, registrar.searchBoardQueueUrl, 1)
+	lastCommand, err := queue.GetLastNCommands(sqsClient, searchBoardQueue.QueueUrl, 1)
 	assert.NoError(t, err)
 	assert.Nil(t, lastCommand)
 
@@ -402,7 +415,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_existing_use
 func Test_SearchRegistrar_not_should_emit_SearchBoardCommand_for_an_existing_user_if_there_are_no_cached_archives(t *testing.T) {
 	var err error
 
-	registrar.validator = MockedValidator{isAlwaysValid: true}
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
 
 	username := uuid.New().String()
 	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
@@ -412,7 +425,7 @@ func Test_SearchRegistrar_not_should_emit_SearchBoardCommand_for_an_existing_use
 		Platform: users.ChessDotCom,
 	}
 
-	err = usersTable.PutUserRecord(user)
+	err = usersTable.PutUserRecord(context.Background(), user)
 	assert.NoError(t, err)
 
 	event := events.APIGatewayV2HTTPRequest{
@@ -446,7 +459,7 @@ func Test_SearchRegistrar_not_should_emit_SearchBoardCommand_for_an_existing_use
 func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_invalid_searchfen(t *testing.T) {
 	var err error
 
-	registrar.validator = MockedValidator{isAlwaysValid: false}
+	registrar.Validator = MockedValidator{isAlwaysValid: false}
 
 	username := uuid.New().String()
 
@@ -478,10 +491,378 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_invalid_sear
 	assert.Equal(t, 0, amountOfCommands, "Amount of commands is not equal!")
 }
 
+func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_an_invalid_board_and_name_the_square_at_fault(t *testing.T) {
+	var err error
+
+	registrar.Validator = PpnBoardValidator{}
+
+	username := uuid.New().String()
+
+	event := events.APIGatewayV2HTTPRequest{
+		Body: fmt.Sprintf(`{"username":"%v", "platform": "CHESS_DOT_COM", "board": "????R?r?/?????kq?/????Q???/????????/???x????/????????/????????/????????"}`, username),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/board",
+			},
+		},
+	}
+
+	actualResponse, err := api.WithRecover(registrar.RegisterSearchRequest)(&event)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, actualResponse.StatusCode, "Response status code is not 422!")
+
+	expectedErroneousResponse := fmt.Sprintf(
+		`{"code":"%v","message":"%v"}`,
+		"INVALID_SEARCH_BOARD",
+		`Invalid board: invalid PPN at rank 4, file d: unknown symbol 'x'!`,
+	)
+	assert.JSONEq(t, expectedErroneousResponse, actualResponse.Body, "Response body is not equal!")
+
+	amountOfCommands, err := countCommands(sqsClient, registrar)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, amountOfCommands, "Amount of commands is not equal!")
+}
+
+func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_invalid_constraints(t *testing.T) {
+	var err error
+
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
+
+	username := uuid.New().String()
+
+	event := events.APIGatewayV2HTTPRequest{
+		Body: fmt.Sprintf(`{"username":"%v", "platform": "CHESS_DOT_COM", "board": "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????", "sideToMove": "red"}`, username),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/board",
+			},
+		},
+	}
+
+	actualResponse, err := api.WithRecover(registrar.RegisterSearchRequest)(&event)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, actualResponse.StatusCode, "Response status code is not 422!")
+
+	expectedErroneousResponse := fmt.Sprintf(
+		`{"code":"%v","message":"%v"}`,
+		"INVALID_SEARCH_CONSTRAINTS",
+		`Invalid constraints: unknown side to move \"red\"!`,
+	)
+	assert.JSONEq(t, expectedErroneousResponse, actualResponse.Body, "Response body is not equal!")
+
+	amountOfCommands, err := countCommands(sqsClient, registrar)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, amountOfCommands, "Amount of commands is not equal!")
+}
+
+func Test_SearchRegistrar_should_emit_SearchBoardCommand_with_constraints_as_a_separate_search(t *testing.T) {
+	var err error
+	startOfTest := time.Now()
+
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
+
+	username := uuid.New().String()
+	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
+	user := users.UserRecord{
+		UserId:   userId,
+		Username: username,
+		Platform: users.ChessDotCom,
+	}
+
+	err = usersTable.PutUserRecord(context.Background(), user)
+	assert.NoError(t, err)
+
+	board := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
+	plainSearchId := searches.NewSearchId(userId, nil, board, searches.BoardConstraints{}, games.GameFilter{})
+	plainSearch := searches.NewSearchRecord(plainSearchId, startOfTest, 10, 24*time.Hour)
+
+	err = searchesTable.PutSearchRecord(context.Background(), plainSearch)
+	assert.NoError(t, err)
+
+	archiveResource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
+	archiveDownloadedAt := db.Zuludatetime(time.Date(2021, 10, 17, 0, 0, 0, 0, time.UTC))
+	archive := archives.ArchiveRecord{
+		UserId:       userId,
+		ArchiveId:    archiveResource,
+		Resource:     archiveResource,
+		Year:         2021,
+		Month:        10,
+		DownloadedAt: &archiveDownloadedAt,
+		Downloaded:   17,
+	}
+
+	err = archivesTable.PutArchiveRecord(context.Background(), archive)
+	assert.NoError(t, err)
+
+	event := events.APIGatewayV2HTTPRequest{
+		Body: fmt.Sprintf(`{"username":"%v", "platform": "CHESS_DOT_COM", "board": "%v", "sideToMove": "b", "castling": "QK", "enPassant": "-"}`, username, board),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/board",
+			},
+		},
+	}
+
+	actualResponse, err := registrar.RegisterSearchRequest(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, actualResponse.StatusCode, "Response status code is not 200!")
+
+	actualSearchResultResponse := SearchResponse{}
+	err = json.Unmarshal([]byte(actualResponse.Body), &actualSearchResultResponse)
+	assert.NoError(t, err)
+
+	expectedConstraints := searches.BoardConstraints{SideToMove: "black", Castling: "KQ", EnPassant: "-"}
+	expectedSearchId := searches.NewSearchId(userId, nil, board, expectedConstraints, games.GameFilter{})
+	assert.Equal(t, expectedSearchId.String(), actualSearchResultResponse.SearchId)
+	assert.NotEqual(t, plainSearchId.String(), actualSearchResultResponse.SearchId)
+
+	lastCommand, err := queue.GetLastNCommands(sqsClient, searchBoardQueue.QueueUrl, 1)
+	assert.NoError(t, err)
+
+	actualCommand := queue.SearchBoardCommand{}
+	_, err = queue.SearchBoardCodec.Decode(*lastCommand[0].Body, &actualCommand)
+	assert.NoError(t, err)
+
+	expectedCommand := queue.SearchBoardCommand{
+		UserId:     userId,
+		SearchId:   expectedSearchId.String(),
+		Board:      board,
+		SideToMove: "black",
+		Castling:   "KQ",
+		EnPassant:  "-",
+	}
+
+	assert.Equal(t, expectedCommand, actualCommand, "Commands are not equal!")
+}
+
+func Test_SearchRegistrar_should_emit_SearchBoardCommand_with_filters_counting_only_the_filtered_games(t *testing.T) {
+	var err error
+	startOfTest := time.Now()
+
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
+
+	username := uuid.New().String()
+	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
+	user := users.UserRecord{
+		UserId:   userId,
+		Username: username,
+		Platform: users.ChessDotCom,
+	}
+
+	err = usersTable.PutUserRecord(context.Background(), user)
+	assert.NoError(t, err)
+
+	archiveResource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2022/08", username)
+	archiveDownloadedAt := db.Zuludatetime(time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC))
+	archive := archives.ArchiveRecord{
+		UserId:       userId,
+		ArchiveId:    archiveResource,
+		Resource:     archiveResource,
+		Year:         2022,
+		Month:        8,
+		DownloadedAt: &archiveDownloadedAt,
+		Downloaded:   3,
+	}
+
+	err = archivesTable.PutArchiveRecord(context.Background(), archive)
+	assert.NoError(t, err)
+
+	newGame := func(gameId string, color string) games.GameRecord {
+		return games.GameRecord{
+			UserId:       userId,
+			ArchiveId:    archiveResource,
+			GameId:       gameId,
+			Resource:     gameId,
+			Pgn:          "1. e4 e5 *",
+			EndTimestamp: 1659429921,
+			TimeClass:    "blitz",
+			UserColor:    color,
+			UserResult:   games.Win,
+			Opponent:     "n-60",
+		}
+	}
+
+	err = gamesTable.PutGameRecords(context.Background(), []games.GameRecord{
+		newGame("https://www.chess.com/game/live/1", games.White),
+		newGame("https://www.chess.com/game/live/2", games.Black),
+		newGame("https://www.chess.com/game/live/3", games.Black),
+	})
+	assert.NoError(t, err)
+
+	board := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
+	event := events.APIGatewayV2HTTPRequest{
+		Body: fmt.Sprintf(`{"username":"%v", "platform": "CHESS_DOT_COM", "board": "%v", "color": "black", "endedFrom": "2022-08-01", "endedTo": "2022-08-31", "opponent": "N-60"}`, username, board),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/board",
+			},
+		},
+	}
+
+	actualResponse, err := registrar.RegisterSearchRequest(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, actualResponse.StatusCode, "Response status code is not 200!")
+
+	actualSearchResultResponse := SearchResponse{}
+	err = json.Unmarshal([]byte(actualResponse.Body), &actualSearchResultResponse)
+	assert.NoError(t, err)
+
+	expectedFilter := games.GameFilter{
+		EndedFrom: 1659312000,
+		EndedTo:   1661990399,
+		Color:     games.Black,
+		Opponent:  "n-60",
+	}
+	expectedSearchId := searches.NewSearchId(userId, nil, board, searches.BoardConstraints{}, expectedFilter)
+	assert.Equal(t, expectedSearchId.String(), actualSearchResultResponse.SearchId)
+
+	actualSearch, err := searchesTable.GetSearchRecord(context.Background(), expectedSearchId.String())
+	assert.NoError(t, err)
+	assert.NotNil(t, actualSearch)
+	assert.Equal(t, 2, actualSearch.Total)
+	assert.True(t, startOfTest.Add(-time.Second).Before(actualSearch.StartAt.ToTime()))
+
+	lastCommand, err := queue.GetLastNCommands(sqsClient, searchBoardQueue.QueueUrl, 1)
+	assert.NoError(t, err)
+
+	actualCommand := queue.SearchBoardCommand{}
+	_, err = queue.SearchBoardCodec.Decode(*lastCommand[0].Body, &actualCommand)
+	assert.NoError(t, err)
+
+	expectedCommand := queue.SearchBoardCommand{
+		UserId:    userId,
+		SearchId:  expectedSearchId.String(),
+		Board:     board,
+		EndedFrom: 1659312000,
+		EndedTo:   1661990399,
+		Color:     games.Black,
+		Opponent:  "n-60",
+	}
+
+	assert.Equal(t, expectedCommand, actualCommand, "Commands are not equal!")
+}
+
+func Test_SearchRegistrar_should_emit_SearchBoardCommand_for_a_sequence_of_boards(t *testing.T) {
+	var err error
+
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
+
+	username := uuid.New().String()
+	userId := fmt.Sprintf("https://api.chess.com/pub/player/%v", username)
+	user := users.UserRecord{
+		UserId:   userId,
+		Username: username,
+		Platform: users.ChessDotCom,
+	}
+
+	err = usersTable.PutUserRecord(context.Background(), user)
+	assert.NoError(t, err)
+
+	archiveResource := fmt.Sprintf("https://api.chess.com/pub/player/%v/games/2021/10", username)
+	archiveDownloadedAt := db.Zuludatetime(time.Date(2021, 10, 17, 0, 0, 0, 0, time.UTC))
+	archive := archives.ArchiveRecord{
+		UserId:       userId,
+		ArchiveId:    archiveResource,
+		Resource:     archiveResource,
+		Year:         2021,
+		Month:        10,
+		DownloadedAt: &archiveDownloadedAt,
+		Downloaded:   17,
+	}
+
+	err = archivesTable.PutArchiveRecord(context.Background(), archive)
+	assert.NoError(t, err)
+
+	boards := []string{
+		"????????/?????N??/????????/????????/????????/????????/????????/????????",
+		"????????/???????Q/????????/????????/????????/????????/????????/????????",
+	}
+
+	event := events.APIGatewayV2HTTPRequest{
+		Body: fmt.Sprintf(`{"username":"%v", "platform": "CHESS_DOT_COM", "boards": ["%v", "%v"], "maxPlyGap": 6}`, username, boards[0], boards[1]),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/board",
+			},
+		},
+	}
+
+	actualResponse, err := registrar.RegisterSearchRequest(&event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, actualResponse.StatusCode, "Response status code is not 200!")
+
+	actualSearchResultResponse := SearchResponse{}
+	err = json.Unmarshal([]byte(actualResponse.Body), &actualSearchResultResponse)
+	assert.NoError(t, err)
+
+	expectedSearchId := searches.NewSearchId(userId, nil, searches.SequenceBoard(boards, 6), searches.BoardConstraints{}, games.GameFilter{})
+	assert.Equal(t, expectedSearchId.String(), actualSearchResultResponse.SearchId)
+
+	lastCommand, err := queue.GetLastNCommands(sqsClient, searchBoardQueue.QueueUrl, 1)
+	assert.NoError(t, err)
+
+	actualCommand := queue.SearchBoardCommand{}
+	_, err = queue.SearchBoardCodec.Decode(*lastCommand[0].Body, &actualCommand)
+	assert.NoError(t, err)
+
+	expectedCommand := queue.SearchBoardCommand{
+		UserId:    userId,
+		SearchId:  expectedSearchId.String(),
+		Boards:    boards,
+		MaxPlyGap: 6,
+	}
+
+	assert.Equal(t, expectedCommand, actualCommand, "Commands are not equal!")
+}
+
+func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_a_sequence_without_a_gap(t *testing.T) {
+	var err error
+
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
+
+	username := uuid.New().String()
+
+	event := events.APIGatewayV2HTTPRequest{
+		Body: fmt.Sprintf(`{"username":"%v", "platform": "CHESS_DOT_COM", "boards": ["????????/????????/????????/????????/????????/????????/????????/????????", "????????/????????/????????/????????/????????/????????/????????/????????"]}`, username),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/board",
+			},
+		},
+	}
+
+	actualResponse, err := api.WithRecover(registrar.RegisterSearchRequest)(&event)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, actualResponse.StatusCode, "Response status code is not 422!")
+
+	expectedErroneousResponse := fmt.Sprintf(
+		`{"code":"%v","message":"%v"}`,
+		"INVALID_SEARCH_SEQUENCE",
+		"A sequence needs at least two boards instead of the board and a positive maxPlyGap!",
+	)
+	assert.JSONEq(t, expectedErroneousResponse, actualResponse.Body, "Response body is not equal!")
+
+	amountOfCommands, err := countCommands(sqsClient, registrar)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, amountOfCommands, "Amount of commands is not equal!")
+}
+
 func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_a_non_existing_user(t *testing.T) {
 	var err error
 
-	registrar.validator = MockedValidator{isAlwaysValid: true}
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
 
 	username := uuid.New().String()
 
@@ -516,7 +897,7 @@ func Test_SearchRegistrar_should_not_emit_SearchBoardCommand_for_a_non_existing_
 func Test_SearchRegistrar_return_error_if_the_username_if_empty(t *testing.T) {
 	var err error
 
-	registrar.validator = MockedValidator{isAlwaysValid: true}
+	registrar.Validator = MockedValidator{isAlwaysValid: true}
 
 	username := ""
 
@@ -547,7 +928,7 @@ func Test_SearchRegistrar_return_error_if_the_username_if_empty(t *testing.T) {
 
 func countCommands(svc *sqs.SQS, registrar SearchRegistrar) (count int, err error) {
 	resp, err := svc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
-		QueueUrl: &registrar.searchBoardQueueUrl,
+		QueueUrl: &searchBoardQueue.QueueUrl,
 		AttributeNames: []*string{
 			aws.String("ApproximateNumberOfMessages"),
 		},
@@ -0,0 +1,54 @@
+package initiate
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/users"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanRecordingUsersTable keeps the span of the context every user is fetched with.
+type spanRecordingUsersTable struct {
+	users.UsersRepository
+	spans []trace.SpanContext
+}
+
+func (table *spanRecordingUsersTable) GetUserRecord(ctx context.Context, username string, platform users.Platform) (*users.UserRecord, error) {
+	table.spans = append(table.spans, trace.SpanContextFromContext(ctx))
+	return table.UsersRepository.GetUserRecord(ctx, username, platform)
+}
+
+func Test_SearchRegistrar_should_fetch_the_user_within_a_span_of_the_trace_of_the_request(t *testing.T) {
+	tracing.MustSetUp("chessfinder-test")
+
+	usersTable := &spanRecordingUsersTable{UsersRepository: users.NewInMemoryUsersTable()}
+	tracedRegistrar := SearchRegistrar{
+		UsersTable: usersTable,
+		Validator:  MockedValidator{isAlwaysValid: true},
+	}
+
+	event := events.APIGatewayV2HTTPRequest{
+		Headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		Body:    fmt.Sprintf(`{"username":"%v", "platform": "CHESS_DOT_COM", "board": "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"}`, uuid.New().String()),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: "POST",
+				Path:   "/api/faster/board",
+			},
+		},
+	}
+
+	_, err := tracedRegistrar.RegisterSearchRequest(&event)
+	assert.Error(t, err)
+
+	assert.Len(t, usersTable.spans, 1)
+	assert.True(t, usersTable.spans[0].IsValid())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", usersTable.spans[0].TraceID().String())
+	assert.NotEqual(t, "00f067aa0ba902b7", usersTable.spans[0].SpanID().String())
+}
@@ -1,6 +1,7 @@
-package main
+package process
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 
@@ -9,15 +10,89 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
 )
 
 type BoardSearcher interface {
-	Match(requestId string, board string, games []GamePgn, logger *zap.Logger) ([]string, int, error)
+	Match(ctx context.Context, requestId string, query BoardQuery, games []GamePgn, logger *zap.Logger) ([]searches.Match, int, error)
 }
 
+// BoardQuery is either a single board or a sequence of boards, each one at most MaxPlyGap plies after the previous one.
+type BoardQuery struct {
+	Boards      []string
+	MaxPlyGap   int
+	Constraints searches.BoardConstraints
+}
+
+// GamePgn is a game to search in, either as its squeezed PGN or as its moves encoded by chess.EncodeMoves.
 type GamePgn struct {
 	Resource string `json:"resource"`
 	Pgn      string `json:"pgn"`
+	Moves    []byte `json:"moves,omitempty"`
+}
+
+type PpnBoardSearcher struct{}
+
+func (searcher PpnBoardSearcher) Match(ctx context.Context, requestId string, query BoardQuery, games []GamePgn, logger *zap.Logger) (matchingResult []searches.Match, examined int, err error) {
+	logger = logger.With(zap.String("requestId", requestId))
+
+	probabilisticBoards := make([]chess.ProbabilisticBoard, len(query.Boards))
+	for i, board := range query.Boards {
+		probabilisticBoards[i], err = chess.ParsePpn(board)
+		if err != nil {
+			logger.Error("board is not a valid PPN", zap.String("board", board), zap.Error(err))
+			return
+		}
+	}
+
+	constraints, err := chess.ParseConstraints(query.Constraints.SideToMove, query.Constraints.Castling, query.Constraints.EnPassant)
+	if err != nil {
+		logger.Error("constraints are not valid", zap.Error(err))
+		return
+	}
+
+	for _, game := range games {
+		examined++
+		var occurrences []chess.Occurrence
+		var found bool
+		var replayErr error
+		if len(game.Moves) > 0 {
+			occurrences, found, replayErr = chess.FindSequenceInMoves(game.Moves, probabilisticBoards, query.MaxPlyGap, constraints)
+		} else {
+			occurrences, found, replayErr = chess.FindSequence(game.Pgn, probabilisticBoards, query.MaxPlyGap, constraints)
+		}
+		if replayErr != nil {
+			logger.Warn("game could not be replayed", zap.String("resource", game.Resource), zap.Error(replayErr))
+		}
+		if found {
+			matchingResult = append(matchingResult, newMatch(game.Resource, occurrences))
+		}
+		if len(matchingResult) >= StopSearchIfFound {
+			break
+		}
+	}
+	return
+}
+
+func newMatch(resource string, occurrences []chess.Occurrence) (match searches.Match) {
+	match.Resource = resource
+	for _, occurrence := range occurrences {
+		location := searches.MatchLocation{
+			Ply:        occurrence.Ply,
+			MoveNumber: occurrence.Position.FullmoveNumber,
+			SideToMove: occurrence.Position.Turn.String(),
+			Fen:        occurrence.Position.Fen(),
+		}
+		if match.Location == nil {
+			match.Location = &location
+		}
+		if len(occurrences) > 1 {
+			match.Steps = append(match.Steps, location)
+		}
+	}
+	return
 }
 
 type DelegatedBoardSearcher struct {
@@ -37,9 +112,15 @@ type SearchResponse struct {
 	MatchedGameResources []string `json:"matchedGameResources"`
 }
 
-func (searcher DelegatedBoardSearcher) Match(requestId string, board string, games []GamePgn, logger *zap.Logger) (matchingResult []string, examined int, err error) {
+func (searcher DelegatedBoardSearcher) Match(ctx context.Context, requestId string, query BoardQuery, games []GamePgn, logger *zap.Logger) (matchingResult []searches.Match, examined int, err error) {
 	logger = logger.With(zap.String("functionName", searcher.FunctionName))
 
+	if len(query.Boards) != 1 || !query.Constraints.IsEmpty() {
+		err = errors.New("the core supports neither sequences nor side to move, castling and en passant constraints")
+		logger.Error("impossible to delegate the search", zap.Error(err))
+		return
+	}
+
 	awsSession, err := session.NewSession(searcher.AwsConfig)
 	if err != nil {
 		logger.Error("impossible to create an AWS session!")
@@ -48,9 +129,23 @@ func (searcher DelegatedBoardSearcher) Match(requestId string, board string, gam
 
 	lambdaClient := lambda.New(awsSession)
 
+	// the core reads PGN only
+	gamesInPgn := make([]GamePgn, 0, len(games))
+	for _, game := range games {
+		if len(game.Moves) > 0 {
+			pgn, errOfDecoding := chess.DecodePgn(game.Moves)
+			if errOfDecoding != nil {
+				logger.Warn("game could not be decoded", zap.String("resource", game.Resource), zap.Error(errOfDecoding))
+				continue
+			}
+			game = GamePgn{Resource: game.Resource, Pgn: pgn}
+		}
+		gamesInPgn = append(gamesInPgn, game)
+	}
+
 	command := SearchCommand{
-		Board:     board,
-		Games:     games,
+		Board:     query.Boards[0],
+		Games:     gamesInPgn,
 		RequestId: requestId,
 	}
 	payload, err := json.Marshal(command)
@@ -59,10 +154,12 @@ func (searcher DelegatedBoardSearcher) Match(requestId string, board string, gam
 		return
 	}
 
-	invokeOutput, err := lambdaClient.Invoke(&lambda.InvokeInput{
+	ctx, span := tracing.StartCall(ctx, "Lambda.Invoke "+searcher.FunctionName)
+	invokeOutput, err := lambdaClient.InvokeWithContext(ctx, &lambda.InvokeInput{
 		FunctionName: aws.String(searcher.FunctionName),
 		Payload:      payload,
 	})
+	tracing.End(span, err)
 	if err != nil {
 		logger.Error("Couldn't invoke function", zap.Error(err))
 		return
@@ -81,7 +178,9 @@ func (searcher DelegatedBoardSearcher) Match(requestId string, board string, gam
 		return
 	}
 
-	matchingResult = response.MatchedGameResources
+	for _, resource := range response.MatchedGameResources {
+		matchingResult = append(matchingResult, searches.Match{Resource: resource})
+	}
 	examined = response.Examined
 	return
 }
@@ -0,0 +1,201 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/pgn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_every_game_of_the_testdata_should_be_replayed_up_to_the_final_position_reported_by_chess_dot_com(t *testing.T) {
+	archives, err := filepath.Glob("testdata/2022-*.json")
+	assert.NoError(t, err)
+
+	for _, archive := range archives {
+		if strings.HasSuffix(archive, "_repeating_games.json") {
+			// the games of this archive share one pgn and keep their original final positions
+			continue
+		}
+		for _, game := range loadGamesJson(t, archive) {
+			positions, err := chess.ReplayPgn(game.Pgn)
+			if !assert.NoError(t, err, game.Url) {
+				continue
+			}
+			// chess.com keeps the castling right of a rook captured on its initial square, so it is not compared
+			expected := strings.Fields(game.Fen)
+			actual := strings.Fields(positions[len(positions)-1].Fen())
+			assert.Equal(t, []string{expected[0], expected[1], expected[3]}, []string{actual[0], actual[1], actual[3]}, game.Url)
+		}
+	}
+}
+
+func Test_PpnBoardSearcher_should_match_the_same_games_as_the_core(t *testing.T) {
+	board := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
+	games := append(loadGamePgns(t, "testdata/2022-10.json"), loadGamePgns(t, "testdata/2022-11.json")...)
+
+	matched, examined, err := PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{board}}, games, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, len(games), examined)
+	assert.Equal(t, []searches.Match{
+		{
+			Resource: "https://www.chess.com/game/live/63025767719",
+			Location: &searches.MatchLocation{
+				Ply:        79,
+				MoveNumber: 40,
+				SideToMove: "black",
+				Fen:        "4R1r1/1p3kq1/p3Q3/3p1p2/BP6/P7/6PP/7K b - - 6 40",
+			},
+		},
+	}, matched)
+}
+
+// testdata/core_matches.json holds the games the core finds for boards of every kind of square, no match included.
+// The same file is asserted against the core by CoreParitySpec.
+func Test_PpnBoardSearcher_should_match_the_same_games_as_the_core_in_every_archive(t *testing.T) {
+	data, err := os.ReadFile("testdata/core_matches.json")
+	assert.NoError(t, err)
+	coreMatches := []struct {
+		Board   string   `json:"board"`
+		Matches []string `json:"matches"`
+	}{}
+	assert.NoError(t, json.Unmarshal(data, &coreMatches))
+
+	archives, err := filepath.Glob("testdata/2022-*.json")
+	assert.NoError(t, err)
+	games := []GamePgn{}
+	for _, archive := range archives {
+		games = append(games, loadGamePgns(t, archive)...)
+	}
+
+	for _, expected := range coreMatches {
+		actualMatches := []string{}
+		for _, game := range games {
+			matched, _, err := PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{expected.Board}}, []GamePgn{game}, zap.NewNop())
+			assert.NoError(t, err, expected.Board)
+			actualMatches = append(actualMatches, searches.Resources(matched)...)
+		}
+		assert.Equal(t, expected.Matches, actualMatches, expected.Board)
+	}
+}
+
+func Test_PpnBoardSearcher_should_match_the_same_games_in_pgn_and_in_encoded_moves(t *testing.T) {
+	board := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
+	games := append(loadGamePgns(t, "testdata/2022-10.json"), loadGamePgns(t, "testdata/2022-11.json")...)
+
+	encodedGames := make([]GamePgn, len(games))
+	for i, game := range games {
+		encoded, err := chess.EncodePgn(game.Pgn)
+		assert.NoError(t, err, game.Resource)
+		encodedGames[i] = GamePgn{Resource: game.Resource, Moves: encoded}
+
+		parsedGame, err := pgn.ParseGame(game.Pgn)
+		assert.NoError(t, err, game.Resource)
+		expectedSans := []string{}
+		for _, move := range parsedGame.Moves {
+			expectedSans = append(expectedSans, move.San)
+		}
+		actualSans, err := chess.DecodeSan(chess.StandardPosition(), encoded)
+		assert.NoError(t, err, game.Resource)
+		assert.Equal(t, expectedSans, actualSans, game.Resource)
+	}
+
+	expectedMatched, _, err := PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{board}}, games, zap.NewNop())
+	assert.NoError(t, err)
+
+	actualMatched, examined, err := PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{board}}, encodedGames, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, len(games), examined)
+	assert.Equal(t, expectedMatched, actualMatched)
+}
+
+func Test_PpnBoardSearcher_should_stop_when_enough_games_are_found(t *testing.T) {
+	board := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
+	games := loadGamePgns(t, "testdata/2022-07_repeating_games.json")
+
+	matched, examined, err := PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{board}}, games, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, StopSearchIfFound, examined)
+	assert.Equal(t, []string{
+		"https://www.chess.com/game/live/52659611873",
+		"https://www.chess.com/game/live/52660795633",
+		"https://www.chess.com/game/live/52661955709",
+		"https://www.chess.com/game/live/52662068301",
+		"https://www.chess.com/game/live/52663147917",
+		"https://www.chess.com/game/live/52669789117",
+		"https://www.chess.com/game/live/52670386207",
+		"https://www.chess.com/game/live/52670970713",
+		"https://www.chess.com/game/live/52671571319",
+		"https://www.chess.com/game/live/52671679953",
+	}, searches.Resources(matched))
+}
+
+func Test_PpnBoardSearcher_should_reject_an_invalid_board(t *testing.T) {
+	_, _, err := PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{"not_an_actual_board"}}, nil, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func Test_PpnBoardSearcher_should_match_only_the_positions_that_satisfy_the_constraints(t *testing.T) {
+	board := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
+	games := append(loadGamePgns(t, "testdata/2022-10.json"), loadGamePgns(t, "testdata/2022-11.json")...)
+
+	matched, _, err := PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{board}, Constraints: searches.BoardConstraints{SideToMove: "black", Castling: "-"}}, games, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://www.chess.com/game/live/63025767719"}, searches.Resources(matched))
+
+	matched, _, err = PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{board}, Constraints: searches.BoardConstraints{SideToMove: "white"}}, games, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Empty(t, matched)
+
+	_, _, err = PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{board}, Constraints: searches.BoardConstraints{EnPassant: "z9"}}, games, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func Test_PpnBoardSearcher_should_match_a_sequence_of_boards_within_the_gap(t *testing.T) {
+	queenOnH7 := "????????/???????Q/????????/????????/????????/????????/????????/????????"
+	mate := "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????"
+	games := append(loadGamePgns(t, "testdata/2022-10.json"), loadGamePgns(t, "testdata/2022-11.json")...)
+
+	matched, examined, err := PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{queenOnH7, mate}, MaxPlyGap: 8}, games, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, len(games), examined)
+	if assert.Len(t, matched, 1) {
+		assert.Equal(t, "https://www.chess.com/game/live/63025767719", matched[0].Resource)
+		assert.Equal(t, 71, matched[0].Location.Ply)
+		assert.Equal(t, 36, matched[0].Location.MoveNumber)
+		assert.Equal(t, "black", matched[0].Location.SideToMove)
+		assert.Len(t, matched[0].Steps, 2)
+		assert.Equal(t, *matched[0].Location, matched[0].Steps[0])
+		assert.Equal(t, 79, matched[0].Steps[1].Ply)
+	}
+
+	matched, _, err = PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{queenOnH7, mate}, MaxPlyGap: 4}, games, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Empty(t, matched)
+
+	matched, _, err = PpnBoardSearcher{}.Match(context.Background(), "requestId", BoardQuery{Boards: []string{mate, queenOnH7}, MaxPlyGap: 80}, games, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Empty(t, matched)
+}
+
+func loadGamesJson(t *testing.T, fileRelativePath string) []GameJson {
+	data, err := os.ReadFile(fileRelativePath)
+	assert.NoError(t, err)
+	allGamesJson := GamesJson{}
+	assert.NoError(t, json.Unmarshal(data, &allGamesJson))
+	return allGamesJson.Games
+}
+
+func loadGamePgns(t *testing.T, fileRelativePath string) (games []GamePgn) {
+	for _, game := range loadGamesJson(t, fileRelativePath) {
+		games = append(games, GamePgn{Resource: game.Url, Pgn: game.Pgn})
+	}
+	return
+}
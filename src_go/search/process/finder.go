@@ -1,18 +1,16 @@
-package main
+package process
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
 	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
 	"go.uber.org/zap"
 )
 
@@ -20,49 +18,70 @@ const MaxGamesPerRequest = 500
 const StopSearchIfFound = 10
 
 type BoardFinder struct {
-	searchesTableName   string
-	gamesTableName      string
-	searchInfoExpiresIn time.Duration
-	awsConfig           *aws.Config
-	searcher            BoardSearcher
+	SearchesTable       searches.SearchesRepository
+	GamesTable          games.GamesRepository
+	SearchInfoExpiresIn time.Duration
+	Searcher            BoardSearcher
+	Concurrency         int
+	DeadLetters         queue.Publisher
 }
 
 func (finder *BoardFinder) Find(commands events.SQSEvent) (events.SQSEventResponse, error) {
 	logger := logging.MustCreateZuluTimeLogger()
 	defer logger.Sync()
-	failedEvents := queue.ProcessMultiple(commands, finder, logger)
+	failedEvents := queue.BatchProcessor{Concurrency: finder.Concurrency, DeadLetters: finder.DeadLetters}.Process(commands, finder, logger)
 	return failedEvents, nil
 }
 
 func (finder *BoardFinder) ProcessSingle(
+	ctx context.Context,
 	message *events.SQSMessage,
 	logger *zap.Logger,
-) (commandProcessed *events.SQSBatchItemFailure, err error) {
-
-	awsSession, err := session.NewSession(finder.awsConfig)
-	if err != nil {
-		logger.Error("impossible to create an AWS session!")
-		return
-	}
-	dynamodbClient := dynamodb.New(awsSession)
+) (err error) {
 
 	command := queue.SearchBoardCommand{}
-	err = json.Unmarshal([]byte(message.Body), &command)
+	envelope, err := queue.SearchBoardCodec.Decode(message.Body, &command)
 	if err != nil {
 		logger.Error("impossible to unmarshal the command", zap.Error(err))
 		return
 	}
 
+	logger = logger.With(zap.String("correlationId", envelope.CorrelationId))
+
 	logger = logger.With(zap.String("searchId", command.SearchId))
 	logger = logger.With(zap.String("userId", command.UserId))
 	logger = logger.With(zap.String("board", command.Board))
 	logger.Info("Processing command")
 
+	query := BoardQuery{
+		Boards:    []string{command.Board},
+		MaxPlyGap: command.MaxPlyGap,
+		Constraints: searches.BoardConstraints{
+			SideToMove: command.SideToMove,
+			Castling:   command.Castling,
+			EnPassant:  command.EnPassant,
+		},
+	}
+	gameFilter := games.GameFilter{
+		EndedFrom: command.EndedFrom,
+		EndedTo:   command.EndedTo,
+		Color:     command.Color,
+		Result:    command.Result,
+		TimeClass: command.TimeClass,
+		Opponent:  command.Opponent,
+	}
+	if !gameFilter.IsEmpty() {
+		logger = logger.With(zap.Any("filter", gameFilter))
+	}
+	if len(command.Boards) > 0 {
+		logger = logger.With(zap.Strings("boards", command.Boards), zap.Int("maxPlyGap", command.MaxPlyGap))
+		query.Boards = command.Boards
+	}
+
 	logger.Info("getting the search record")
-	searchRecord, err := searches.SearchesTable{
-		Name:           finder.searchesTableName,
-		DynamodbClient: dynamodbClient,
-	}.GetSearchRecord(command.SearchId)
+	searchCtx, searchSpan := tracing.StartCall(ctx, "SearchesTable.GetSearchRecord")
+	searchRecord, err := finder.SearchesTable.GetSearchRecord(searchCtx, command.SearchId)
+	tracing.End(searchSpan, err)
 
 	if err != nil {
 		logger.Error("impossible to get the search record", zap.Error(err))
@@ -81,12 +100,12 @@ func (finder *BoardFinder) ProcessSingle(
 
 	getGamesAnalyseAndUpdateStatus := func(
 		logger *zap.Logger,
-		lastKey map[string]*dynamodb.AttributeValue,
+		lastKey games.PageKey,
 		examinedBefore int,
-		matchedBefore []string,
+		matchedBefore []searches.Match,
 	) (
-		totalMatched []string,
-		nextKey map[string]*dynamodb.AttributeValue,
+		totalMatched []searches.Match,
+		nextKey games.PageKey,
 		totalExamined int,
 		err error,
 	) {
@@ -96,10 +115,9 @@ func (finder *BoardFinder) ProcessSingle(
 		now := db.Zuludatetime(time.Now())
 		logger.Info("getting the game records")
 
-		gameRecords, nextKey, err := games.GamesTable{
-			Name:           finder.gamesTableName,
-			DynamodbClient: dynamodbClient,
-		}.QueryGames(command.UserId, lastKey, MaxGamesPerRequest)
+		gamesCtx, gamesSpan := tracing.StartCall(ctx, "GamesTable.QueryGames")
+		gameRecords, nextKey, err := finder.GamesTable.QueryGames(gamesCtx, command.UserId, gameFilter, lastKey, MaxGamesPerRequest)
+		tracing.End(gamesSpan, err)
 
 		if err != nil {
 			logger.Error("impossible to get the game records", zap.Error(err))
@@ -116,10 +134,11 @@ func (finder *BoardFinder) ProcessSingle(
 			gamePgn[i] = GamePgn{
 				Resource: gameRecord.GameId,
 				Pgn:      gameRecord.Pgn,
+				Moves:    gameRecord.Moves,
 			}
 		}
 
-		matchedGames, examined, errFromSearch := finder.searcher.Match(command.SearchId, command.Board, gamePgn, logger)
+		matchedGames, examined, errFromSearch := finder.Searcher.Match(ctx, command.SearchId, query, gamePgn, logger)
 		totalExamined += examined
 		if errFromSearch != nil {
 			logger.Error("impossible to match the board", zap.Error(err))
@@ -131,10 +150,9 @@ func (finder *BoardFinder) ProcessSingle(
 		logger = logger.With(zap.Int("examined", totalExamined))
 		logger.Info("updating the search record")
 
-		err = searches.SearchesTable{
-			Name:           finder.searchesTableName,
-			DynamodbClient: dynamodbClient,
-		}.UpdateMatchings(command.SearchId, totalExamined, totalMatched, now, finder.searchInfoExpiresIn)
+		matchingsCtx, matchingsSpan := tracing.StartCall(ctx, "SearchesTable.UpdateMatchings")
+		err = finder.SearchesTable.UpdateMatchings(matchingsCtx, command.SearchId, totalExamined, totalMatched, now, finder.SearchInfoExpiresIn)
+		tracing.End(matchingsSpan, err)
 
 		if err != nil {
 			logger.Error("impossible to update the search record", zap.Error(err))
@@ -143,8 +161,8 @@ func (finder *BoardFinder) ProcessSingle(
 		return
 	}
 
-	matchedGames := []string{}
-	var lastKey map[string]*dynamodb.AttributeValue
+	matchedGames := []searches.Match{}
+	var lastKey games.PageKey
 
 	round := 0
 	examined := 0
@@ -180,10 +198,9 @@ func (finder *BoardFinder) ProcessSingle(
 
 	logger.Info("updating the search record")
 
-	err = searches.SearchesTable{
-		Name:           finder.searchesTableName,
-		DynamodbClient: dynamodbClient,
-	}.UpdateStatus(command.SearchId, searchStatus)
+	statusCtx, statusSpan := tracing.StartCall(ctx, "SearchesTable.UpdateStatus")
+	err = finder.SearchesTable.UpdateStatus(statusCtx, command.SearchId, searchStatus)
+	tracing.End(statusSpan, err)
 
 	if err != nil {
 		logger.Error("impossible to update the search record", zap.Error(err))
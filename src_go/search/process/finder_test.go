@@ -1,6 +1,7 @@
-package main
+package process
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -27,10 +28,10 @@ type MockedBoardSearcher struct {
 	validPgn string
 }
 
-func (searcher MockedBoardSearcher) Match(requestId string, board string, games []GamePgn, logger *zap.Logger) (result []string, examined int, err error) {
+func (searcher MockedBoardSearcher) Match(ctx context.Context, requestId string, query BoardQuery, games []GamePgn, logger *zap.Logger) (result []searches.Match, examined int, err error) {
 	for _, game := range games {
 		if strings.Contains(game.Pgn, searcher.validPgn) {
-			result = append(result, game.Resource)
+			result = append(result, searches.Match{Resource: game.Resource})
 		}
 		examined++
 
@@ -52,10 +53,9 @@ var awsConfig = aws.Config{
 }
 
 var finder = BoardFinder{
-	searchesTableName: "chessfinder_dynamodb-searches",
-	gamesTableName:    "chessfinder_dynamodb-games",
-	searcher:          mockedBoardSearcher,
-	awsConfig:         &awsConfig,
+	SearchesTable: searchesTable,
+	GamesTable:    gamesTable,
+	Searcher:      mockedBoardSearcher,
 }
 
 var awsSession = session.Must(session.NewSession(&awsConfig))
@@ -63,12 +63,12 @@ var dynamodbClient = dynamodb.New(awsSession)
 var wiremockClient = wiremock.NewClient("http://0.0.0.0:18443")
 
 var searchesTable = searches.SearchesTable{
-	Name:           finder.searchesTableName,
+	Name:           "chessfinder_dynamodb-searches",
 	DynamodbClient: dynamodbClient,
 }
 
 var gamesTable = games.GamesTable{
-	Name:           finder.gamesTableName,
+	Name:           "chessfinder_dynamodb-games",
 	DynamodbClient: dynamodbClient,
 }
 
@@ -80,18 +80,18 @@ func Test_when_there_is_a_registered_search_BoardFinder_should_look_through_all_
 	var err error
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
-	searchId := searches.NewSearchId(userId, &downloadStartedAt, "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????")
+	searchId := searches.NewSearchId(userId, &downloadStartedAt, "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????", searches.BoardConstraints{}, games.GameFilter{})
 	total := 0
 
 	if gameRecords, err := loadGameRecords(userId, searchId.String(), "testdata/2022-10.json"); assert.NoError(t, err) {
-		err = gamesTable.PutGameRecords(gameRecords)
+		err = gamesTable.PutGameRecords(context.Background(), gameRecords)
 		assert.NoError(t, err)
 		total += len(gameRecords)
 
 	}
 
 	if gameRecords, err := loadGameRecords(userId, searchId.String(), "testdata/2022-11.json"); assert.NoError(t, err) {
-		err = gamesTable.PutGameRecords(gameRecords)
+		err = gamesTable.PutGameRecords(context.Background(), gameRecords)
 		assert.NoError(t, err)
 		total += len(gameRecords)
 	}
@@ -109,10 +109,7 @@ func Test_when_there_is_a_registered_search_BoardFinder_should_look_through_all_
 		ExpiresAt:      dynamodbattribute.UnixTime(startOfTest.Add(24 * time.Hour)),
 	}
 
-	err = searches.SearchesTable{
-		Name:           finder.searchesTableName,
-		DynamodbClient: dynamodbClient,
-	}.PutSearchRecord(searchRecord)
+	err = searchesTable.PutSearchRecord(context.Background(), searchRecord)
 
 	assert.NoError(t, err)
 
@@ -139,7 +136,7 @@ func Test_when_there_is_a_registered_search_BoardFinder_should_look_through_all_
 	}
 	assert.Equal(t, expectedCommandsProcessed, actualCommandsProcessed)
 
-	actualSearchRecord, err := searchesTable.GetSearchRecord(searchId.String())
+	actualSearchRecord, err := searchesTable.GetSearchRecord(context.Background(), searchId.String())
 	assert.NoError(t, err)
 
 	startOfChecking := time.Now().UTC()
@@ -149,12 +146,70 @@ func Test_when_there_is_a_registered_search_BoardFinder_should_look_through_all_
 	assert.Equal(t, searches.SearchedAll, actualSearchRecord.Status)
 	assert.True(t, startOfChecking.After(actualSearchRecord.LastExaminedAt.ToTime()))
 	assert.True(t, startOfTest.Before(actualSearchRecord.LastExaminedAt.ToTime()))
-	assert.True(t, startOfTest.Add(finder.searchInfoExpiresIn-time.Second).Before(time.Time(actualSearchRecord.ExpiresAt)))
-	assert.True(t, startOfChecking.Add(finder.searchInfoExpiresIn+time.Second).After(time.Time(actualSearchRecord.ExpiresAt)))
+	assert.True(t, startOfTest.Add(finder.SearchInfoExpiresIn-time.Second).Before(time.Time(actualSearchRecord.ExpiresAt)))
+	assert.True(t, startOfChecking.Add(finder.SearchInfoExpiresIn+time.Second).After(time.Time(actualSearchRecord.ExpiresAt)))
 
 	assert.ElementsMatch(t, []string{"https://www.chess.com/game/live/63025767719"}, actualSearchRecord.Matched)
 }
 
+func Test_BoardFinder_should_look_through_games_of_any_repositories(t *testing.T) {
+	startOfTest := time.Now().UTC()
+
+	var err error
+	inMemorySearchesTable := searches.NewInMemorySearchesTable()
+	inMemoryGamesTable := games.NewInMemoryGamesTable()
+	inMemoryFinder := BoardFinder{
+		SearchesTable:       inMemorySearchesTable,
+		GamesTable:          inMemoryGamesTable,
+		SearchInfoExpiresIn: time.Hour,
+		Searcher:            mockedBoardSearcher,
+	}
+
+	userId := uuid.New().String()
+	searchId := searches.NewSearchId(userId, nil, "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????", searches.BoardConstraints{}, games.GameFilter{})
+	total := 0
+
+	for _, archive := range []string{"testdata/2022-10.json", "testdata/2022-11.json"} {
+		if gameRecords, err := loadGameRecords(userId, searchId.String(), archive); assert.NoError(t, err) {
+			err = inMemoryGamesTable.PutGameRecords(context.Background(), gameRecords)
+			assert.NoError(t, err)
+			total += len(gameRecords)
+		}
+	}
+
+	searchAtartAt := db.Zuludatetime(startOfTest.Add(-1 * time.Hour))
+
+	err = inMemorySearchesTable.PutSearchRecord(context.Background(), searches.SearchRecord{
+		SearchId:       searchId,
+		StartAt:        searchAtartAt,
+		LastExaminedAt: searchAtartAt,
+		Total:          total,
+		Status:         searches.InProgress,
+		ExpiresAt:      dynamodbattribute.UnixTime(startOfTest.Add(24 * time.Hour)),
+	})
+	assert.NoError(t, err)
+
+	command :=
+		events.SQSMessage{
+			Body: fmt.Sprintf(
+				`{"searchId": "%s", "board": "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????", "userId": "%s"}`,
+				searchId,
+				userId,
+			),
+			MessageId: "1",
+		}
+
+	actualCommandsProcessed, err := inMemoryFinder.Find(events.SQSEvent{Records: []events.SQSMessage{command}})
+	assert.NoError(t, err)
+	assert.Nil(t, actualCommandsProcessed.BatchItemFailures)
+
+	actualSearchRecord, err := inMemorySearchesTable.GetSearchRecord(context.Background(), searchId.String())
+	assert.NoError(t, err)
+	assert.Equal(t, total, actualSearchRecord.Examined)
+	assert.Equal(t, searches.SearchedAll, actualSearchRecord.Status)
+	assert.ElementsMatch(t, []string{"https://www.chess.com/game/live/63025767719"}, actualSearchRecord.Matched)
+}
+
 func Test_when_there_is_no_registered_search_BoardFinder_should_skip(t *testing.T) {
 	defer wiremockClient.Reset()
 
@@ -163,7 +218,7 @@ func Test_when_there_is_no_registered_search_BoardFinder_should_skip(t *testing.
 	searchId := uuid.New().String()
 
 	if gameRecords, err := loadGameRecords(userId, searchId, "testdata/2022-07_very_few_games.json"); assert.NoError(t, err) {
-		err = gamesTable.PutGameRecords(gameRecords)
+		err = gamesTable.PutGameRecords(context.Background(), gameRecords)
 		assert.NoError(t, err)
 	}
 
@@ -187,7 +242,7 @@ func Test_when_there_is_no_registered_search_BoardFinder_should_skip(t *testing.
 	assert.NoError(t, err)
 	assert.Nil(t, actualCommandsProcessed.BatchItemFailures)
 
-	actualSearchRecord, err := searchesTable.GetSearchRecord(searchId)
+	actualSearchRecord, err := searchesTable.GetSearchRecord(context.Background(), searchId)
 	assert.NoError(t, err)
 	assert.Nil(t, actualSearchRecord)
 }
@@ -200,17 +255,17 @@ func Test_when_there_are_more_then_10_games_that_have_the_same_position_BoardFin
 	var err error
 	userId := uuid.New().String()
 	downloadStartedAt := db.Zuludatetime(time.Date(2023, time.October, 1, 11, 30, 17, 123000000, time.UTC))
-	searchId := searches.NewSearchId(userId, &downloadStartedAt, "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????")
+	searchId := searches.NewSearchId(userId, &downloadStartedAt, "????R?r?/?????kq?/????Q???/????????/????????/????????/????????/????????", searches.BoardConstraints{}, games.GameFilter{})
 	total := 0
 
 	if gameRecords, err := loadGameRecords(userId, searchId.String(), "testdata/2022-07_repeating_games.json"); assert.NoError(t, err) {
-		err = gamesTable.PutGameRecords(gameRecords)
+		err = gamesTable.PutGameRecords(context.Background(), gameRecords)
 		assert.NoError(t, err)
 		total += len(gameRecords)
 	}
 
 	if gameRecords, err := loadGameRecords(userId, searchId.String(), "testdata/2022-08.json"); assert.NoError(t, err) {
-		err = gamesTable.PutGameRecords(gameRecords)
+		err = gamesTable.PutGameRecords(context.Background(), gameRecords)
 		assert.NoError(t, err)
 		total += len(gameRecords)
 	}
@@ -228,10 +283,7 @@ func Test_when_there_are_more_then_10_games_that_have_the_same_position_BoardFin
 		ExpiresAt:      dynamodbattribute.UnixTime(startOfTest.Add(24 * time.Hour)),
 	}
 
-	err = searches.SearchesTable{
-		Name:           finder.searchesTableName,
-		DynamodbClient: dynamodbClient,
-	}.PutSearchRecord(searchRecord)
+	err = searchesTable.PutSearchRecord(context.Background(), searchRecord)
 	assert.NoError(t, err)
 
 	command :=
@@ -257,7 +309,7 @@ func Test_when_there_are_more_then_10_games_that_have_the_same_position_BoardFin
 	}
 	assert.Equal(t, expectedCommandsProcessed, actualCommandsProcessed)
 
-	actualSearchRecord, err := searchesTable.GetSearchRecord(searchId.String())
+	actualSearchRecord, err := searchesTable.GetSearchRecord(context.Background(), searchId.String())
 	assert.NoError(t, err)
 
 	startOfChecking := time.Now().UTC()
@@ -267,8 +319,8 @@ func Test_when_there_are_more_then_10_games_that_have_the_same_position_BoardFin
 	assert.Equal(t, searches.SearchedPartially, actualSearchRecord.Status)
 	assert.True(t, startOfChecking.After(actualSearchRecord.LastExaminedAt.ToTime()))
 	assert.True(t, startOfTest.Before(actualSearchRecord.LastExaminedAt.ToTime()))
-	assert.True(t, startOfTest.Add(finder.searchInfoExpiresIn-time.Second).Before(time.Time(actualSearchRecord.ExpiresAt)))
-	assert.True(t, startOfChecking.Add(finder.searchInfoExpiresIn+time.Second).After(time.Time(actualSearchRecord.ExpiresAt)))
+	assert.True(t, startOfTest.Add(finder.SearchInfoExpiresIn-time.Second).Before(time.Time(actualSearchRecord.ExpiresAt)))
+	assert.True(t, startOfChecking.Add(finder.SearchInfoExpiresIn+time.Second).After(time.Time(actualSearchRecord.ExpiresAt)))
 
 	expectedMatchedGames := []string{
 		"https://www.chess.com/game/live/52659611873",
@@ -327,4 +379,5 @@ type GameJson struct {
 	Url     string `json:"url"`
 	Pgn     string `json:"pgn"`
 	EndTime int64  `json:"end_time"`
+	Fen     string `json:"fen"`
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/blobs"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/searches"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/queue"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/tracing"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/search/process"
+)
+
+func main() {
+
+	gamesTableName, gamesTableNameExists := os.LookupEnv("GAMES_TABLE_NAME")
+	if !gamesTableNameExists {
+		panic(errors.New("GAMES_TABLE_NAME is missing"))
+	}
+
+	searchesTableName, searchesTableNameExists := os.LookupEnv("SEARCHES_TABLE_NAME")
+	if !searchesTableNameExists {
+		panic(errors.New("SEARCHES_TABLE_NAME is missing"))
+	}
+
+	searchInfoExpiresInCadidate, searchInfoExpiresInExists := os.LookupEnv("SEARCH_INFO_EXPIRES_IN_SECONDS")
+	if !searchInfoExpiresInExists {
+		panic(errors.New("SEARCH_INFO_EXPIRES_IN_SECONDS is missing"))
+	}
+
+	searchInfoExpiresIn, err := time.ParseDuration(searchInfoExpiresInCadidate + "s")
+	if err != nil {
+		panic(err)
+	}
+
+	concurrency := 1
+	if concurrencyCandidate := os.Getenv("PROCESSING_CONCURRENCY"); concurrencyCandidate != "" {
+		concurrency, err = strconv.Atoi(concurrencyCandidate)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	awsRegion, awsRegionExists := os.LookupEnv("AWS_REGION")
+	if !awsRegionExists {
+		panic(errors.New("AWS_REGION is missing"))
+	}
+
+	awsConfig := &aws.Config{
+		Region: &awsRegion,
+	}
+
+	awsSession := session.Must(session.NewSession(awsConfig))
+	dynamodbClient := dynamodb.New(awsSession)
+
+	var pgnBlobs blobs.BlobStore
+	if pgnBucketName := os.Getenv("PGN_BUCKET_NAME"); pgnBucketName != "" {
+		pgnBlobs = blobs.S3BlobStore{Bucket: pgnBucketName, S3Client: s3.New(awsSession)}
+	}
+
+	// without the queue the messages failed permanently are only logged
+	var deadLetters queue.Publisher
+	if deadLetterQueueUrl := os.Getenv("DEAD_LETTER_QUEUE_URL"); deadLetterQueueUrl != "" {
+		deadLetters = queue.SqsQueue{QueueUrl: deadLetterQueueUrl, SqsClient: sqs.New(awsSession)}
+	}
+
+	finder := process.BoardFinder{
+		SearchesTable: searches.SearchesTable{
+			Name:           searchesTableName,
+			DynamodbClient: dynamodbClient,
+		},
+		GamesTable: games.GamesTable{
+			Name:           gamesTableName,
+			DynamodbClient: dynamodbClient,
+			PgnBlobs:       pgnBlobs,
+		},
+		SearchInfoExpiresIn: searchInfoExpiresIn,
+		Searcher:            process.PpnBoardSearcher{},
+		Concurrency:         concurrency,
+		DeadLetters:         deadLetters,
+	}
+
+	tracing.MustSetUp("chessfinder-search-process")
+
+	lambda.Start(finder.Find)
+
+}
@@ -0,0 +1,50 @@
+// encode_games re-encodes the games stored as squeezed PGN into the compact move encoding.
+//
+//	AWS_REGION=eu-central-1 encode_games -table chessfinder-games [-endpoint http://localhost:4566] [-dry-run]
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/logging"
+	"go.uber.org/zap"
+)
+
+func main() {
+	tableName := flag.String("table", "", "the name of the games table")
+	endpoint := flag.String("endpoint", "", "the DynamoDB endpoint, LocalStack for example")
+	dryRun := flag.Bool("dry-run", false, "count the games to encode without updating them")
+	flag.Parse()
+
+	logger := logging.MustCreateZuluTimeLogger()
+	defer logger.Sync()
+
+	if *tableName == "" {
+		logger.Error("the table is missing")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	awsConfig := &aws.Config{}
+	if *endpoint != "" {
+		awsConfig.Endpoint = endpoint
+	}
+
+	migration := GamesMigration{
+		TableName:      *tableName,
+		DynamodbClient: dynamodb.New(session.Must(session.NewSessionWithOptions(session.Options{Config: *awsConfig, SharedConfigState: session.SharedConfigEnable}))),
+		DryRun:         *dryRun,
+	}
+
+	statistics, err := migration.Run(logger)
+	logger = logger.With(zap.Int("scanned", statistics.Scanned), zap.Int("encoded", statistics.Encoded), zap.Int("skipped", statistics.Skipped))
+	if err != nil {
+		logger.Error("the migration stopped", zap.Error(err))
+		os.Exit(1)
+	}
+	logger.Info("the migration is over")
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"go.uber.org/zap"
+)
+
+type GamesMigration struct {
+	TableName      string
+	DynamodbClient *dynamodb.DynamoDB
+	DryRun         bool
+}
+
+type MigrationStatistics struct {
+	Scanned int
+	Encoded int
+	Skipped int
+}
+
+// Run scans the whole table and replaces the pgn of every game by its encoded moves.
+// The games whose pgn cannot be encoded keep it, the same way the downloader keeps them.
+// A game is updated only if its pgn has not changed since the scan, so the migration can run next to the downloads and be repeated.
+func (migration GamesMigration) Run(logger *zap.Logger) (statistics MigrationStatistics, err error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName:        aws.String(migration.TableName),
+		FilterExpression: aws.String("attribute_exists(pgn) AND attribute_not_exists(moves)"),
+	}
+
+	// ScanPages returns nil when the page function stops the scan, so the error that stopped it is kept apart
+	var errFromPage error
+	err = migration.DynamodbClient.ScanPages(scanInput, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		statistics.Scanned += int(aws.Int64Value(page.ScannedCount))
+
+		gameRecords := []games.GameRecord{}
+		errFromPage = dynamodbattribute.UnmarshalListOfMaps(page.Items, &gameRecords)
+		if errFromPage != nil {
+			logger.Error("impossible to unmarshal the game records", zap.Error(errFromPage))
+			return false
+		}
+
+		for _, gameRecord := range gameRecords {
+			logger := logger.With(zap.String("userId", gameRecord.UserId), zap.String("gameId", gameRecord.GameId))
+
+			encodedRecord, errFromEncoding := encode(gameRecord)
+			if errFromEncoding != nil {
+				logger.Warn("impossible to encode the game, the pgn is kept", zap.Error(errFromEncoding))
+				statistics.Skipped++
+				continue
+			}
+
+			if migration.DryRun {
+				statistics.Encoded++
+				continue
+			}
+
+			isUpdated, errFromUpdate := migration.update(gameRecord.Pgn, encodedRecord)
+			if errFromUpdate != nil {
+				errFromPage = errFromUpdate
+				logger.Error("impossible to update the game", zap.Error(errFromPage))
+				return false
+			}
+			if !isUpdated {
+				logger.Info("the game has changed since the scan")
+				statistics.Skipped++
+				continue
+			}
+			statistics.Encoded++
+		}
+
+		logger.Info("page migrated", zap.Int("scanned", statistics.Scanned), zap.Int("encoded", statistics.Encoded), zap.Int("skipped", statistics.Skipped))
+		return true
+	})
+	if err == nil {
+		err = errFromPage
+	}
+	return
+}
+
+func encode(gameRecord games.GameRecord) (encodedRecord games.GameRecord, err error) {
+	encodedMoves, err := chess.EncodePgn(gameRecord.Pgn)
+	if err != nil {
+		return
+	}
+	encodedRecord = gameRecord
+	encodedRecord.Moves = encodedMoves
+	encodedRecord.Pgn = ""
+	return
+}
+
+func (migration GamesMigration) update(pgn string, encodedRecord games.GameRecord) (isUpdated bool, err error) {
+	_, err = migration.DynamodbClient.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(migration.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"user_id": {S: aws.String(encodedRecord.UserId)},
+			"game_id": {S: aws.String(encodedRecord.GameId)},
+		},
+		UpdateExpression:    aws.String("SET moves = :moves REMOVE pgn"),
+		ConditionExpression: aws.String("pgn = :pgn"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":moves": {B: encodedRecord.Moves},
+			":pgn":   {S: aws.String(pgn)},
+		},
+	})
+	if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return false, nil
+	}
+	isUpdated = err == nil
+	return
+}
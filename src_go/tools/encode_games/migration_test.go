@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/chess"
+	"github.com/chessfinder/chessfinder-faster-backend/src_go/details/db/games"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func Test_encode_should_replace_the_pgn_by_the_encoded_moves(t *testing.T) {
+	gameRecord := games.GameRecord{
+		UserId: "user",
+		GameId: "https://www.chess.com/game/live/53168947271",
+		Pgn:    "1. d4 b5 2. Nc3 Bb7 3. Nxb5 a6 4. Nc3 c6 5. e3 Qb6 6. Nf3 e6 7. Bd3 Bb4 8. Bd2 1-0",
+		Result: "1-0",
+	}
+
+	actualRecord, err := encode(gameRecord)
+	assert.NoError(t, err)
+	assert.Empty(t, actualRecord.Pgn)
+	assert.Len(t, actualRecord.Moves, 2*15)
+	assert.Equal(t, "1-0", actualRecord.Result)
+
+	actualPgn, err := chess.DecodePgn(actualRecord.Moves)
+	assert.NoError(t, err)
+	assert.Equal(t, "1. d4 b5 2. Nc3 Bb7 3. Nxb5 a6 4. Nc3 c6 5. e3 Qb6 6. Nf3 e6 7. Bd3 Bb4 8. Bd2", actualPgn)
+}
+
+func Test_encode_should_keep_a_game_that_cannot_be_replayed(t *testing.T) {
+	_, err := encode(games.GameRecord{Pgn: "1. e4 e5 2. Ke3"})
+	assert.Error(t, err)
+}
+
+// dynamodbStandIn scans one page with a game to encode and rejects every update of the game.
+func dynamodbStandIn() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if request.Header.Get("X-Amz-Target") == "DynamoDB_20120810.Scan" {
+			writer.Write([]byte(`{"Count":1,"ScannedCount":1,"Items":[{"user_id":{"S":"user"},"game_id":{"S":"https://www.chess.com/game/live/53168947271"},"pgn":{"S":"1. d4 b5 1-0"}}]}`))
+			return
+		}
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ResourceNotFoundException","message":"Requested resource not found"}`))
+	}))
+}
+
+func Test_GamesMigration_should_return_the_error_that_stopped_the_scan(t *testing.T) {
+	server := dynamodbStandIn()
+	defer server.Close()
+
+	migration := GamesMigration{
+		TableName: "chessfinder-games",
+		DynamodbClient: dynamodb.New(session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String("us-east-1"),
+			Endpoint:    aws.String(server.URL),
+			Credentials: credentials.NewStaticCredentials("test", "test", ""),
+			MaxRetries:  aws.Int(0),
+		}))),
+	}
+
+	actualStatistics, err := migration.Run(zap.NewNop())
+	assert.ErrorContains(t, err, dynamodb.ErrCodeResourceNotFoundException)
+	assert.Equal(t, MigrationStatistics{Scanned: 1}, actualStatistics)
+}